@@ -0,0 +1,133 @@
+// Copyright (c) 2023 Yawning Angel
+//
+// SPDX-License-Identifier: BSD-3-Clause
+
+package secp256k1
+
+import (
+	"gitlab.com/yawning/secp256k1-voi/internal/disalloweq"
+	"gitlab.com/yawning/secp256k1-voi/internal/field"
+)
+
+// FieldElementSize is the size of a field element in bytes.
+const FieldElementSize = field.ElementSize
+
+// FieldElement is an integer modulo `p = 2^256 - 2^32 - 977`.  All
+// arguments and receivers are allowed to alias.  The zero value is
+// a valid zero element.
+//
+// Note: This is a thin wrapper around the internal field arithmetic,
+// exposed for the benefit of callers that need to implement their own
+// checks using the curve constants.  Most users will never need this.
+type FieldElement struct {
+	_  disalloweq.DisallowEqual
+	fe field.Element
+}
+
+// Add sets `fe = a + b` and returns `fe`.
+func (fe *FieldElement) Add(a, b *FieldElement) *FieldElement {
+	fe.fe.Add(&a.fe, &b.fe)
+	return fe
+}
+
+// Subtract sets `fe = a - b` and returns `fe`.
+func (fe *FieldElement) Subtract(a, b *FieldElement) *FieldElement {
+	fe.fe.Subtract(&a.fe, &b.fe)
+	return fe
+}
+
+// Negate sets `fe = -a` and returns `fe`.
+func (fe *FieldElement) Negate(a *FieldElement) *FieldElement {
+	fe.fe.Negate(&a.fe)
+	return fe
+}
+
+// Multiply sets `fe = a * b` and returns `fe`.
+func (fe *FieldElement) Multiply(a, b *FieldElement) *FieldElement {
+	fe.fe.Multiply(&a.fe, &b.fe)
+	return fe
+}
+
+// Square sets `fe = a * a` and returns `fe`.
+func (fe *FieldElement) Square(a *FieldElement) *FieldElement {
+	fe.fe.Square(&a.fe)
+	return fe
+}
+
+// Set sets `fe = a` and returns `fe`.
+func (fe *FieldElement) Set(a *FieldElement) *FieldElement {
+	fe.fe.Set(&a.fe)
+	return fe
+}
+
+// SetCanonicalBytes sets `fe = src`, where `src` is a 32-byte big-endian
+// encoding of `fe`, and returns `fe`.  If `src` is not a canonical
+// encoding of `fe`, SetCanonicalBytes returns nil and an error, and the
+// receiver is unchanged.
+func (fe *FieldElement) SetCanonicalBytes(src *[FieldElementSize]byte) (*FieldElement, error) {
+	if _, err := fe.fe.SetCanonicalBytes(src); err != nil {
+		return nil, err
+	}
+	return fe, nil
+}
+
+// Bytes returns the canonical big-endian encoding of `fe`.
+func (fe *FieldElement) Bytes() []byte {
+	return fe.fe.Bytes()
+}
+
+// Equal returns 1 iff `fe == a`, 0 otherwise.
+func (fe *FieldElement) Equal(a *FieldElement) uint64 {
+	return fe.fe.Equal(&a.fe)
+}
+
+// IsZero returns 1 iff `fe == 0`, 0 otherwise.
+func (fe *FieldElement) IsZero() uint64 {
+	return fe.fe.IsZero()
+}
+
+// IsOdd returns 1 iff `fe % 2 == 1`, 0 otherwise.
+func (fe *FieldElement) IsOdd() uint64 {
+	return fe.fe.IsOdd()
+}
+
+// Sign returns `sgn0(fe)` as specified in RFC 9380, Section 4.1.
+//
+// Note: As the extension degree of the field is 1, this is equivalent
+// to IsOdd.
+func (fe *FieldElement) Sign() uint64 {
+	// 1. return x mod 2
+	return fe.fe.IsOdd()
+}
+
+// NewFieldElement returns a new zero FieldElement.
+func NewFieldElement() *FieldElement {
+	return &FieldElement{}
+}
+
+// NewFieldElementFromCanonicalBytes creates a new FieldElement from the
+// canonical big-endian byte representation.
+func NewFieldElementFromCanonicalBytes(src *[FieldElementSize]byte) (*FieldElement, error) {
+	fe, err := NewFieldElement().SetCanonicalBytes(src)
+	if err != nil {
+		return nil, err
+	}
+
+	return fe, nil
+}
+
+// CurveB returns a new FieldElement set to the constant `b = 7`, part
+// of the curve equation `y^2 = x^3 + b`.
+func CurveB() *FieldElement {
+	fe := NewFieldElement()
+	fe.fe.Set(feB)
+	return fe
+}
+
+// CurveRHS returns a new FieldElement set to `x^3 + b`, the right-hand
+// side of the curve equation `y^2 = x^3 + b`.
+func CurveRHS(x *FieldElement) *FieldElement {
+	fe := NewFieldElement()
+	fe.fe.Set(maybeYY(&x.fe))
+	return fe
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2023 Yawning Angel
+//
+// SPDX-License-Identifier: BSD-3-Clause
+
+package secp256k1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gitlab.com/yawning/secp256k1-voi/internal/helpers"
+)
+
+func TestFieldElement(t *testing.T) {
+	t.Run("CurveB", func(t *testing.T) {
+		b := CurveB()
+
+		var expected [FieldElementSize]byte
+		expected[FieldElementSize-1] = 7
+		require.Equal(t, expected[:], b.Bytes(), "CurveB()")
+
+		// The returned value must be a copy.
+		b.Add(b, b)
+		require.Equal(t, expected[:], CurveB().Bytes(), "CurveB() - copy")
+	})
+	t.Run("CurveRHS", func(t *testing.T) {
+		x, err := NewFieldElementFromCanonicalBytes((*[FieldElementSize]byte)(feGX.Bytes()))
+		require.NoError(t, err, "NewFieldElementFromCanonicalBytes(gX)")
+		y, err := NewFieldElementFromCanonicalBytes((*[FieldElementSize]byte)(feGY.Bytes()))
+		require.NoError(t, err, "NewFieldElementFromCanonicalBytes(gY)")
+
+		yy := NewFieldElement().Square(y)
+		require.EqualValues(t, 1, CurveRHS(x).Equal(yy), "gY^2 == gX^3 + 7")
+
+		// Off-curve.
+		y.Add(y, NewFieldElement().Set(CurveB()))
+		yy.Square(y)
+		require.EqualValues(t, 0, CurveRHS(x).Equal(yy), "(gY+7)^2 != gX^3 + 7")
+	})
+	t.Run("IsOdd", func(t *testing.T) {
+		// gY is even, -gY is odd.
+		y, err := NewFieldElementFromCanonicalBytes((*[FieldElementSize]byte)(feGY.Bytes()))
+		require.NoError(t, err, "NewFieldElementFromCanonicalBytes(gY)")
+		require.EqualValues(t, 0, y.IsOdd(), "gY.IsOdd()")
+		require.EqualValues(t, 0, y.Sign(), "sgn0(gY)")
+
+		y.Negate(y)
+		require.EqualValues(t, 1, y.IsOdd(), "(-gY).IsOdd()")
+		require.EqualValues(t, 1, y.Sign(), "sgn0(-gY)")
+
+		require.EqualValues(t, 0, NewFieldElement().Sign(), "sgn0(0)")
+		require.EqualValues(t, 1, CurveB().Sign(), "sgn0(7)")
+	})
+	t.Run("SetCanonicalBytes", func(t *testing.T) {
+		fe := NewFieldElement()
+		pBytes := helpers.Must256BitsFromHex("0xfffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f") // P
+		fe2, err := fe.SetCanonicalBytes(pBytes)
+		require.Nil(t, fe2, "SetCanonicalBytes(p)")
+		require.Error(t, err, "SetCanonicalBytes(p)")
+		require.EqualValues(t, 1, fe.IsZero(), "SetCanonicalBytes(p) - unchanged")
+	})
+}
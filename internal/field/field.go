@@ -174,6 +174,11 @@ func (fe *Element) ConditionalSelect(a, b *Element, ctrl uint64) *Element {
 	return fe
 }
 
+// ConditionalSwap swaps `fe` and `a` iff `ctrl != 0`.
+func (fe *Element) ConditionalSwap(a *Element, ctrl uint64) {
+	helpers.FiatLimbsConditionalSwap((*[4]uint64)(&fe.m), (*[4]uint64)(&a.m), ctrl)
+}
+
 // Equal returns 1 iff `fe == a`, 0 otherwise.
 func (fe *Element) Equal(a *Element) uint64 {
 	return helpers.FiatLimbsAreEqual((*[4]uint64)(&fe.m), (*[4]uint64)(&a.m))
@@ -41,6 +41,18 @@ func FiatLimbsAreEqual(a, b *[4]uint64) uint64 {
 	return Uint64IsZero(v)
 }
 
+// FiatLimbsConditionalSwap swaps `a` and `b` iff `ctrl != 0`, in
+// constant time.
+func FiatLimbsConditionalSwap(a, b *[4]uint64, ctrl uint64) {
+	mask := -Uint64IsNonzero(ctrl)
+
+	for i := 0; i < len(a); i++ {
+		t := (a[i] ^ b[i]) & mask
+		a[i] ^= t
+		b[i] ^= t
+	}
+}
+
 // BytesToSaturated interprets src as a 256-bit big-endian integer, and
 // returns the 64-bit saturated representation, compatible with the
 // autogenerated fiat routines.
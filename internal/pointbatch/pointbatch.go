@@ -0,0 +1,31 @@
+// Copyright (c) 2023 Yawning Angel
+//
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package pointbatch exposes batched point serialization to the other
+// packages in this module, without making it part of the public API.
+//
+// The implementation lives in the secp256k1 package (as it requires
+// access to the projective coordinates), and is registered here at
+// initialization time, as this package can not import secp256k1
+// without creating an import cycle.
+package pointbatch
+
+var uncompressedBytes any
+
+// RegisterUncompressedBytes registers the batched uncompressed point
+// encoder.  It is called by the secp256k1 package, and MUST NOT be
+// called by anything else.
+func RegisterUncompressedBytes(fn any) {
+	if uncompressedBytes != nil {
+		panic("secp256k1/internal/pointbatch: UncompressedBytes already registered")
+	}
+	uncompressedBytes = fn
+}
+
+// UncompressedBytes returns the SEC 1, Version 2.0, Section 2.3.3
+// uncompressed or infinity encoding of each of `points`, sharing a
+// single field inversion across the batch.
+func UncompressedBytes[P any](points []P) [][]byte {
+	return uncompressedBytes.(func([]P) [][]byte)(points)
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2023 Yawning Angel
+//
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package rfc6979 implements the RFC 6979 deterministic nonce generation
+// procedure with SHA-256.
+package rfc6979
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"io"
+
+	"gitlab.com/yawning/secp256k1-voi"
+)
+
+type hmacDRBG struct {
+	v []byte
+	k []byte
+
+	needUpdate bool
+}
+
+func (drbg *hmacDRBG) Read(b []byte) (int, error) {
+	if len(b) != secp256k1.ScalarSize {
+		panic("secp256k1/internal/rfc6979: invalid RFC6979 read length")
+	}
+
+	if drbg.needUpdate {
+		// Step 3 from the previous Read call is delayed till the
+		// next read, as it is extremely unlikely that the first k
+		// to get sampled is unsuitable.
+		drbg.updateK()
+		drbg.updateV()
+	}
+
+	// h. Apply the following algorithm until a proper value is found for k:
+
+	// 1.  Set T to the empty sequence.  The length of T (in bits) is
+	// denoted tlen; thus, at that point, tlen = 0.
+
+	// 2.  While tlen < qlen, do the following:
+	// V = HMAC_K(V)
+	// T = T || V
+
+	drbg.updateV()
+	copy(b, drbg.v) // Return T instead (Note: len(v) = qlen)
+
+	// 3.  Compute:
+	// k = bits2int(T)
+	//
+	// If that value of k is within the [1,q-1] range, and is
+	// suitable for DSA or ECDSA (i.e., it results in an r value
+	// that is not 0; see Section 3.4), then the generation of k is
+	// finished.  The obtained value of k is used in DSA or ECDSA.
+	// Otherwise, compute:
+	//
+	// K = HMAC_K(V || 0x00)
+	// V = HMAC_K(V)
+	//
+	// and loop (try to generate a new T, and so on).
+
+	drbg.needUpdate = true
+
+	return len(b), nil
+}
+
+func (drbg *hmacDRBG) updateV() {
+	// V = HMAC_K(V)
+	m := hmac.New(sha256.New, drbg.k)
+	_, _ = m.Write(drbg.v)
+	drbg.v = m.Sum(drbg.v[:0])
+}
+
+func (drbg *hmacDRBG) updateK() {
+	// K = HMAC_K(V || 0x00)
+	m := hmac.New(sha256.New, drbg.k)
+	_, _ = m.Write(drbg.v)
+	_, _ = m.Write([]byte{0x00})
+	drbg.k = m.Sum(drbg.k[:0])
+}
+
+// NewDRBG returns a HMAC_DRBG instance, instantiated with the private
+// key `x` and the message representative `e`, as specified in RFC 6979,
+// Section 3.2, using SHA-256 as the hash function.  Each call to Read
+// MUST request exactly 32-bytes, and returns a candidate k.
+//
+// If provided, `additionalData` is appended to `bits2octets(h1)` in
+// Steps d and f, as specified in RFC 6979, Section 3.6.
+func NewDRBG(x, e *secp256k1.Scalar, additionalData ...[]byte) io.Reader {
+	// 3.2.  Generation of k
+
+	const kvLen = 32 // 8 * ceil(hlen/8)
+
+	// a. Process m through the hash function H, yielding:
+	// h1 = H(m) (h1 is a sequence of hlen bits).
+
+	// b. Set: V = 0x01 0x01 0x01 ... 0x01
+	// c. Set: K = 0x00 0x00 0x00 ... 0x00
+	drbg := &hmacDRBG{
+		v: bytes.Repeat([]byte{0x01}, kvLen),
+		k: make([]byte, kvLen),
+	}
+
+	i2oB := x.Bytes()
+	b2oH1 := e.Bytes()
+
+	// d. Set: K = HMAC_K(V || 0x00 || int2octets(x) || bits2octets(h1) || k')
+	// e. Set: V = HMAC_K(V)
+	// f. Set: K = HMAC_K(V || 0x01 || int2octets(x) || bits2octets(h1) || k')
+	// g. Set: V = HMAC_K(V)
+
+	initUpdateK := func(internalOctet byte) {
+		m := hmac.New(sha256.New, drbg.k)
+		_, _ = m.Write(drbg.v)
+		_, _ = m.Write([]byte{internalOctet})
+		_, _ = m.Write(i2oB)
+		_, _ = m.Write(b2oH1)
+		for _, v := range additionalData {
+			_, _ = m.Write(v)
+		}
+		drbg.k = m.Sum(drbg.k[:0])
+	}
+	initUpdateK(0x00) // Step d
+	drbg.updateV()    // Step e
+	initUpdateK(0x01) // Step f
+	drbg.updateV()    // Step g
+
+	return drbg
+}
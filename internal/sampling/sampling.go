@@ -0,0 +1,56 @@
+// Copyright (c) 2023 Yawning Angel
+//
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package sampling implements uniform random scalar sampling.
+package sampling
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"gitlab.com/yawning/secp256k1-voi"
+)
+
+const maxScalarResamples = 8
+
+var (
+	// ErrEntropySource is the error returned when the entropy source
+	// fails.
+	ErrEntropySource = errors.New("secp256k1/secec: entropy source failure")
+
+	// ErrRejectionSampling is the error returned when rejection sampling
+	// fails to produce a suitable scalar.
+	ErrRejectionSampling = errors.New("secp256k1/secec: failed rejection sampling")
+)
+
+// RandomScalar samples a uniformly random non-zero scalar from `rand`.
+func RandomScalar(rand io.Reader) (*secp256k1.Scalar, error) {
+	// Do rejection sampling to ensure that there is no bias in the
+	// scalar values.  Note that the odds of a single failure are
+	// approximately p = 3.73 * 10^-39, so even requiring a single
+	// retry is unlikely unless the entropy source is broken.
+	var (
+		tmp [secp256k1.ScalarSize]byte
+		s   = secp256k1.NewScalar()
+	)
+	defer func() {
+		for i := range tmp {
+			tmp[i] = 0
+		}
+	}()
+
+	for i := 0; i < maxScalarResamples; i++ {
+		if _, err := io.ReadFull(rand, tmp[:]); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrEntropySource, err)
+		}
+
+		_, didReduce := s.SetBytes(&tmp)
+		if didReduce == 0 && s.IsZero() == 0 { // Short circuit reject is ok.
+			return s, nil
+		}
+	}
+
+	return nil, ErrRejectionSampling
+}
@@ -0,0 +1,33 @@
+// Copyright (c) 2023 Yawning Angel
+//
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sampling
+
+import (
+	"bytes"
+	csrand "crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gitlab.com/yawning/secp256k1-voi"
+)
+
+func TestRandomScalar(t *testing.T) {
+	s, err := RandomScalar(csrand.Reader)
+	require.NoError(t, err, "RandomScalar")
+	require.EqualValues(t, 0, s.IsZero(), "RandomScalar - non-zero")
+
+	// All-zero entropy source should cause the rejection sampling
+	// to give up, because it keeps generating scalars that are 0.
+	zeroReader := bytes.NewReader(make([]byte, maxScalarResamples*secp256k1.ScalarSize))
+	s, err = RandomScalar(zeroReader)
+	require.Nil(t, s, "RandomScalar - zero reader")
+	require.ErrorIs(t, err, ErrRejectionSampling, "RandomScalar - zero reader")
+
+	// Broken (non-functional) entropy source should just fail.
+	s, err = RandomScalar(bytes.NewReader(make([]byte, 13)))
+	require.Nil(t, s, "RandomScalar - short reader")
+	require.ErrorIs(t, err, ErrEntropySource, "RandomScalar - short reader")
+}
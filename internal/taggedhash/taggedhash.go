@@ -0,0 +1,70 @@
+// Copyright (c) 2023 Yawning Angel
+//
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package taggedhash implements the BIP-0340 tagged hash construct,
+// `SHA256(SHA256(tag) || SHA256(tag) || x)`.
+package taggedhash
+
+import (
+	"crypto/sha256"
+	"encoding"
+	"hash"
+)
+
+// Midstate is a tagged hash, with the SHA-256 state after absorbing
+// the tag prefix cached.
+type Midstate struct {
+	state []byte
+}
+
+// New returns a new [hash.Hash] computing the tagged hash, with the
+// tag prefix already absorbed.
+func (m *Midstate) New() hash.Hash {
+	h := sha256.New()
+	if err := h.(encoding.BinaryUnmarshaler).UnmarshalBinary(m.state); err != nil {
+		panic("secp256k1/internal/taggedhash: failed to restore midstate: " + err.Error())
+	}
+	return h
+}
+
+// Sum returns the tagged hash of the concatenation of `vals`.
+func (m *Midstate) Sum(vals ...[]byte) []byte {
+	h := m.New()
+	for _, v := range vals {
+		_, _ = h.Write(v)
+	}
+	return h.Sum(nil)
+}
+
+// NewMidstate returns the Midstate for `tag`.
+func NewMidstate(tag string) *Midstate {
+	h := newUncached(tag)
+	state, err := h.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		panic("secp256k1/internal/taggedhash: failed to save midstate: " + err.Error())
+	}
+
+	return &Midstate{
+		state: state,
+	}
+}
+
+// Sum returns the tagged hash of the concatenation of `vals`, with
+// the tag `tag`.
+func Sum(tag string, vals ...[]byte) []byte {
+	h := newUncached(tag)
+	for _, v := range vals {
+		_, _ = h.Write(v)
+	}
+	return h.Sum(nil)
+}
+
+func newUncached(tag string) hash.Hash {
+	hashedTag := sha256.Sum256([]byte(tag))
+
+	h := sha256.New()
+	_, _ = h.Write(hashedTag[:])
+	_, _ = h.Write(hashedTag[:])
+	return h
+}
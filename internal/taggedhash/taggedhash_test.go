@@ -0,0 +1,39 @@
+// Copyright (c) 2023 Yawning Angel
+//
+// SPDX-License-Identifier: BSD-3-Clause
+
+package taggedhash
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gitlab.com/yawning/secp256k1-voi/internal/helpers"
+)
+
+func TestTaggedHash(t *testing.T) {
+	// BIP-0340 test vector 0 challenge:
+	// e = hash_BIP0340/challenge(bytes(R) || bytes(P) || m)
+	var (
+		tag = "BIP0340/challenge"
+		r   = helpers.MustBytesFromHex("E907831F80848D1069A5371B402410364BDF1C5F8307B0084C55F1CE2DCA8215")
+		p   = helpers.MustBytesFromHex("F9308A019258C31049344F85F89D5229B531C845836F99B08601F113BCE036F9")
+		m   = helpers.MustBytesFromHex("0000000000000000000000000000000000000000000000000000000000000000")
+	)
+
+	expected := Sum(tag, r, p, m)
+
+	midstate := NewMidstate(tag)
+	require.Equal(t, expected, midstate.Sum(r, p, m), "Midstate.Sum")
+
+	h := midstate.New()
+	_, _ = h.Write(r)
+	_, _ = h.Write(p)
+	_, _ = h.Write(m)
+	require.Equal(t, expected, h.Sum(nil), "Midstate.New")
+
+	// The midstate must not be mutated by use.
+	require.Equal(t, expected, midstate.Sum(r, p, m), "Midstate.Sum - again")
+	require.NotEqual(t, expected, NewMidstate("BIP0340/aux").Sum(r, p, m), "Midstate.Sum - other tag")
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2023 Yawning Angel
+//
+// SPDX-License-Identifier: BSD-3-Clause
+
+package secp256k1
+
+import (
+	"encoding/asn1"
+	"math/big"
+)
+
+// CurveParameters are the secp256k1 domain parameters, as specified in
+// SEC 2, Version 2.0, Section 2.4.1.  The curve is `y^2 = x^3 + ax + b`
+// over GF(p).
+type CurveParameters struct {
+	// P is the order of the underlying field.
+	P *big.Int
+	// N is the order of the base point.
+	N *big.Int
+	// A is the constant `a` of the curve equation.
+	A *big.Int
+	// B is the constant `b` of the curve equation.
+	B *big.Int
+	// Gx is the x-coordinate of the base point.
+	Gx *big.Int
+	// Gy is the y-coordinate of the base point.
+	Gy *big.Int
+	// Cofactor is the cofactor of the curve.
+	Cofactor int
+
+	// OID is the ASN.1 object identifier of the curve, as specified in
+	// SEC 2, Version 2.0, Appendix A.2.
+	OID asn1.ObjectIdentifier
+}
+
+// Parameters returns a new copy of the secp256k1 domain parameters,
+// for the purpose of documentation and introspection.
+func Parameters() CurveParameters {
+	return CurveParameters{
+		P:        mustBigIntFromHex("fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f"),
+		N:        new(big.Int).SetBytes(nBytes),
+		A:        new(big.Int),
+		B:        new(big.Int).SetBytes(feB.Bytes()),
+		Gx:       new(big.Int).SetBytes(feGX.Bytes()),
+		Gy:       new(big.Int).SetBytes(feGY.Bytes()),
+		Cofactor: 1,
+		OID:      asn1.ObjectIdentifier{1, 3, 132, 0, 10},
+	}
+}
+
+func mustBigIntFromHex(s string) *big.Int {
+	v, ok := new(big.Int).SetString(s, 16)
+	if !ok {
+		panic("secp256k1: invalid hex big.Int: " + s)
+	}
+	return v
+}
@@ -11,6 +11,7 @@ import (
 
 	"gitlab.com/yawning/secp256k1-voi/internal/disalloweq"
 	"gitlab.com/yawning/secp256k1-voi/internal/field"
+	"gitlab.com/yawning/secp256k1-voi/internal/helpers"
 )
 
 var (
@@ -122,6 +123,29 @@ func (v *Point) ConditionalSelect(a, b *Point, ctrl uint64) *Point {
 	return v
 }
 
+// ConditionalSwap swaps `v` and `p` iff `ctrl != 0`.
+func (v *Point) ConditionalSwap(p *Point, ctrl uint64) {
+	assertPointsValid(v, p)
+
+	v.x.ConditionalSwap(&p.x, ctrl)
+	v.y.ConditionalSwap(&p.y, ctrl)
+	v.z.ConditionalSwap(&p.z, ctrl)
+}
+
+// ConstantTimeSelectPoint returns a new Point set to `tbl[idx]`, or
+// the identity point if `idx` is out of range.  The lookup is done in
+// constant time with respect to `idx`, by scanning the entire table.
+func ConstantTimeSelectPoint(tbl []*Point, idx uint64) *Point {
+	assertPointsValid(tbl...)
+
+	v := NewIdentityPoint()
+	for i, p := range tbl {
+		v.uncheckedConditionalSelect(v, p, helpers.Uint64Equal(idx, uint64(i)))
+	}
+
+	return v
+}
+
 func (v *Point) uncheckedConditionalSelect(a, b *Point, ctrl uint64) *Point {
 	v.x.ConditionalSelect(&a.x, &b.x, ctrl)
 	v.y.ConditionalSelect(&a.y, &b.y, ctrl)
@@ -151,7 +175,48 @@ func (v *Point) IsIdentity() uint64 {
 	return v.z.IsZero()
 }
 
-// IsYOdd returns 1 iff `v.y` is odd, 0 otherwise.
+// IsOnCurve returns 1 iff `v` satisfies the curve equation, 0 otherwise.
+// The identity point is considered to be on the curve, and returns 1.
+//
+// Note: Every initialized Point returned by this package is on the
+// curve, as all of the constructors and deserialization routines
+// validate their inputs.  This is provided for defensive checks when
+// a point crosses a trust boundary.
+func (v *Point) IsOnCurve() uint64 {
+	assertPointsValid(v)
+
+	// The projective form of `y^2 = x^3 + 7` is `Y^2 * Z = X^3 + 7 * Z^3`,
+	// which is also satisfied by the identity point `(0:1:0)`.
+	zz := field.NewElement().Square(&v.z)
+	lhs := field.NewElement().Square(&v.y)
+	lhs.Multiply(lhs, &v.z)
+
+	rhs := field.NewElement().Multiply(zz, &v.z)
+	rhs.Multiply(rhs, feB)
+	xxx := field.NewElement().Square(&v.x)
+	xxx.Multiply(xxx, &v.x)
+	rhs.Add(rhs, xxx)
+
+	return lhs.Equal(rhs)
+}
+
+// IsInPrimeOrderSubgroup returns 1 iff `v` is in the prime-order
+// subgroup, 0 otherwise.
+//
+// Note: As secp256k1 has a cofactor of 1, this always returns 1, as
+// every valid point (including the identity) is in the prime-order
+// subgroup.  This is provided for the benefit of curve-agnostic code.
+func (v *Point) IsInPrimeOrderSubgroup() uint64 {
+	assertPointsValid(v)
+
+	return 1
+}
+
+// IsYOdd returns 1 iff `v.y` is odd, 0 otherwise.  This is the parity
+// bit used by the SEC 1 compressed and BIP-0340 x-only encodings, and
+// matches the prefix returned by `CompressedBytes` (`0x02 | IsYOdd()`).
+// The identity point has no affine y-coordinate, and the return value
+// for it is meaningless.
 func (v *Point) IsYOdd() uint64 {
 	assertPointsValid(v)
 
@@ -177,6 +242,26 @@ func NewGeneratorPoint() *Point {
 	return newRcvr().Generator()
 }
 
+// GeneratorMultiple returns a new Point set to `n * G`.
+//
+// Note: This is variable-time with respect to `n`, and is intended
+// for deriving protocol constants and test points.
+func GeneratorMultiple(n uint64) *Point {
+	const maxRepeatedAdds = 16
+
+	if n > maxRepeatedAdds {
+		return NewIdentityPoint().ScalarBaseMult(NewScalarFromUint64(n))
+	}
+
+	g := NewGeneratorPoint()
+	v := NewIdentityPoint()
+	for i := uint64(0); i < n; i++ {
+		v.Add(v, g)
+	}
+
+	return v
+}
+
 // NewIdentityPoint returns a new Point set to the identity element (point at infinity).
 func NewIdentityPoint() *Point {
 	// Note: This doesn't use p.Identity(), because x and z are guaranteed
@@ -199,7 +284,11 @@ func NewPointFrom(p *Point) *Point {
 }
 
 // NewPointFromCoords creates a new Point from the big-endian encoded x
-// and y coordinates.
+// and y coordinates.  Both coordinates MUST be canonical field elements
+// (`< p`), and `(x, y)` MUST satisfy the curve equation, otherwise
+// NewPointFromCoords returns nil and an error.  As the point at
+// infinity has no affine coordinates, it can not be created with this
+// routine.
 func NewPointFromCoords(xBytes, yBytes *[CoordSize]byte) (*Point, error) {
 	x, err := field.NewElementFromCanonicalBytes(xBytes)
 	if err != nil {
@@ -0,0 +1,107 @@
+// Copyright (c) 2023 Yawning Angel
+//
+// SPDX-License-Identifier: BSD-3-Clause
+
+package secp256k1
+
+import (
+	"gitlab.com/yawning/secp256k1-voi/internal/disalloweq"
+	"gitlab.com/yawning/secp256k1-voi/internal/field"
+)
+
+// fixedBaseTableSize is the number of 4-bit window tables required to
+// cover a scalar.
+const fixedBaseTableSize = ScalarSize * 2
+
+// FixedBasePoint is a point with precomputed multiples, suitable for
+// accelerating repeated constant-time scalar multiplication with the
+// same base.
+//
+// Creating a FixedBasePoint is expensive (approximately 1000 point
+// additions and field inversions), and each instance consumes
+// approximately 60 KiB of memory, so this is only worth it if a large
+// number of multiplies will be done with the same point.
+type FixedBasePoint struct {
+	_ disalloweq.DisallowEqual
+
+	// tbl stores the series of 64 tables of precomputed multiples of
+	// P [1P, ... 15P], with each successive table being the previous
+	// table doubled 4 times.
+	tbl        *[fixedBaseTableSize]affinePointMultTable
+	isIdentity bool
+}
+
+// ScalarMult returns a new Point set to `s * P`, where `P` is the
+// fixed base point.
+func (fb *FixedBasePoint) ScalarMult(s *Scalar) *Point {
+	v := NewIdentityPoint()
+	if fb.isIdentity {
+		return v
+	}
+
+	// This is identical to ScalarBaseMult, except that all of the
+	// tables are stored in a single array.
+	for i, b := range s.Bytes() {
+		tblIdx := 2 * (ScalarSize - (1 + i))
+		fb.tbl[tblIdx+1].SelectAndAdd(v, uint64(b>>4))
+		fb.tbl[tblIdx].SelectAndAdd(v, uint64(b&0xf))
+	}
+
+	return v
+}
+
+// ScalarMultVartime returns a new Point set to `s * P` in variable
+// time, where `P` is the fixed base point.
+func (fb *FixedBasePoint) ScalarMultVartime(s *Scalar) *Point {
+	v := NewIdentityPoint()
+	if fb.isIdentity {
+		return v
+	}
+
+	for i, b := range s.Bytes() {
+		tblIdx := 2 * (ScalarSize - (1 + i))
+		fb.tbl[tblIdx+1].SelectAndAddVartime(v, uint64(b>>4))
+		fb.tbl[tblIdx].SelectAndAddVartime(v, uint64(b&0xf))
+	}
+
+	return v
+}
+
+// NewFixedBasePoint returns a new FixedBasePoint with `p` as the
+// base point.
+func NewFixedBasePoint(p *Point) *FixedBasePoint {
+	assertPointsValid(p)
+
+	fb := &FixedBasePoint{
+		isIdentity: p.IsIdentity() == 1,
+	}
+	if fb.isIdentity {
+		// All multiples of the point at infinity are the point at
+		// infinity, which can not be represented in affine coordinates.
+		return fb
+	}
+
+	fb.tbl = new([fixedBaseTableSize]affinePointMultTable)
+
+	base, zInv := NewPointFrom(p), field.NewElement()
+	for i := range fb.tbl {
+		if i != 0 {
+			base.doubleComplete(base)
+			base.doubleComplete(base)
+			base.doubleComplete(base)
+			base.doubleComplete(base)
+		}
+
+		// Note: None of the multiples can be the point at infinity,
+		// as `15 * 16^63 < n`, so rescaling is always safe.
+		projTbl := newProjectivePointMultTable(base)
+		for j := range projTbl {
+			pt := &projTbl[j]
+			zInv.Invert(&pt.z)
+			fb.tbl[i][j].x.Multiply(zInv, &pt.x)
+			fb.tbl[i][j].y.Multiply(zInv, &pt.y)
+		}
+	}
+
+	return fb
+}
@@ -56,7 +56,9 @@ var (
 	scG2 = newScalarFromCanonicalHex("0xe4437ed6010e88286f547fa90abfe4c4221208ac9df506c61571b4ae8ac47f71")
 )
 
-func (s *Scalar) splitGLV() (*Scalar, *Scalar) {
+// splitGLV sets `k1, k2` to the balanced length-two representation of
+// `s`, and returns `k1, k2`.
+func (s *Scalar) splitGLV(k1, k2 *Scalar) (*Scalar, *Scalar) {
 	// From "Guide to Elliptic Curve Cryptography" by Hankerson,
 	// Menezes, Vanstone, Algorithm 3.74 "Balanced length-two
 	// representation of a multiplier":
@@ -105,13 +107,13 @@ func (s *Scalar) splitGLV() (*Scalar, *Scalar) {
 	c2 := NewScalar().mulGFlooredDiv(s, scG2)
 
 	// k2 = -c1b1 - c2b2
-	k2 := NewScalar().Multiply(c1, scNegB1)
+	k2.Multiply(c1, scNegB1)
 	tmp := NewScalar().Multiply(c2, scNegB2)
-	k2 = k2.Add(k2, tmp)
+	k2.Add(k2, tmp)
 
 	// k1 = k - k2 * lambda mod n
-	k1 := NewScalar().Multiply(k2, scNegLambda)
-	k1.Add(s, k1)
+	tmp.Multiply(k2, scNegLambda)
+	k1.Add(s, tmp)
 
 	return k1, k2
 }
@@ -201,26 +203,32 @@ func (v *Point) mulBeta(p *Point) *Point {
 
 // scalarMultVartimeGLV sets `v = s * p`, and returns `v` in variable time.
 func (v *Point) scalarMultVartimeGLV(s *Scalar, p *Point) *Point {
-	pee := NewPointFrom(p) // Note: Checks p is valid.
-	peePrime := newMulBeta(p)
+	// Note: This is the core of ECDSA verification, so the temporaries
+	// are kept on the stack.
+	var (
+		pee, peePrime Point
+		k1, k2        Scalar
+	)
+	pee.Set(p) // Note: Checks p is valid.
+	peePrime.mulBeta(p)
 
 	// Split the scalar.
 	//
 	// Pick the shorter reprentation for each of the returned scalars
 	// by negating both the scalar and it's corresponding point if
 	// required.
-	k1, k2 := s.splitGLV()
+	s.splitGLV(&k1, &k2)
 	if k1.IsGreaterThanHalfN() == 1 {
-		k1.Negate(k1)
-		pee.Negate(pee)
+		k1.Negate(&k1)
+		pee.Negate(&pee)
 	}
 	if k2.IsGreaterThanHalfN() == 1 {
-		k2.Negate(k2)
-		peePrime.Negate(peePrime)
+		k2.Negate(&k2)
+		peePrime.Negate(&peePrime)
 	}
 
-	pTbl := newProjectivePointMultTable(pee)
-	pPrimeTbl := newProjectivePointMultTable(peePrime)
+	pTbl := newProjectivePointMultTable(&pee)
+	pPrimeTbl := newProjectivePointMultTable(&peePrime)
 
 	v.Identity()
 
@@ -258,7 +266,7 @@ func (v *Point) ScalarMult(s *Scalar, p *Point) *Point {
 	pee := NewPointFrom(p) // Note: Checks p is valid.
 	peePrime := newMulBeta(p)
 
-	k1, k2 := s.splitGLV()
+	k1, k2 := s.splitGLV(NewScalar(), NewScalar())
 
 	negateK1 := k1.IsGreaterThanHalfN()
 	k1.ConditionalNegate(k1, negateK1)
@@ -302,6 +310,32 @@ func (v *Point) ScalarMult(s *Scalar, p *Point) *Point {
 	return v
 }
 
+// ScalarMultBytes sets `v = s * p`, where `s` is the 32-byte big-endian
+// encoding of a scalar, and returns `v`.  Non-canonical encodings (ie:
+// `sBytes >= n`) are reduced modulo `n`, and the returned error is
+// always nil.
+//
+// This is equivalent to decoding `sBytes` with NewScalarFromBytes and
+// calling ScalarMult, without the intermediary heap allocated Scalar.
+func (v *Point) ScalarMultBytes(sBytes *[ScalarSize]byte, p *Point) (*Point, error) {
+	var s Scalar
+	_, _ = s.SetBytes(sBytes) // Reduction info unneeded.
+	defer s.Zero()
+
+	return v.ScalarMult(&s, p), nil
+}
+
+// ScalarMultVartime sets `v = s * p`, and returns `v` in variable time.
+//
+// WARNING: This is NOT constant-time with respect to either `s` or `p`,
+// and MUST only be used when both are public.  Use `ScalarMult` for
+// secret scalars.
+func (v *Point) ScalarMultVartime(s *Scalar, p *Point) *Point {
+	assertPointsValid(p)
+
+	return v.scalarMultVartimeGLV(s, p)
+}
+
 // DoubleScalarMultBasepointVartime sets `v = u1 * G + u2 * P`, and returns
 // `v` in variable time, where `G` is the generator.
 func (v *Point) DoubleScalarMultBasepointVartime(u1, u2 *Scalar, p *Point) *Point {
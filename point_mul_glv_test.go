@@ -45,7 +45,7 @@ func testScalarSplit(t *testing.T) {
 		newScalarFromCanonicalHex("0x26c75a9980b861c14a4c38051024c8b4704d760ee95e7cd3de1bfdb1ce2c5a45"),
 	} {
 		t.Run(fmt.Sprintf("Case %d", i), func(t *testing.T) {
-			k1, k2 := v.splitGLV()
+			k1, k2 := v.splitGLV(NewScalar(), NewScalar())
 
 			// k = k1 + k2 * lambda mod n
 			k := NewScalar().Multiply(k2, lambda)
@@ -115,3 +115,28 @@ func (v *Point) MultiScalarMultVartime(scalars []*Scalar, points []*Point) *Poin
 
 	return v
 }
+
+// MultiScalarMultBasepointVartime sets `v = sum(us[i] * G) +
+// sum(vs[i] * ps[i])`, and returns `v` in variable time, where `G` is
+// the generator.
+func (v *Point) MultiScalarMultBasepointVartime(us, vs []*Scalar, ps []*Point) *Point {
+	if len(vs) != len(ps) {
+		panic("secp256k1: len(vs) != len(ps)")
+	}
+
+	// sum(us[i] * G) = sum(us[i]) * G, so the basepoint terms can be
+	// handled with a single scalar-basepoint multiply, which is
+	// considerably faster than adding G to the Straus table.
+	u := NewScalar()
+	for _, s := range us {
+		u.Add(u, s)
+	}
+	ug := newRcvr().scalarBaseMultVartime(u)
+
+	if len(vs) == 0 {
+		return v.Set(ug)
+	}
+
+	vp := newRcvr().MultiScalarMultVartime(vs, ps)
+	return v.Add(ug, vp)
+}
@@ -49,6 +49,29 @@ func testPointMultiScalarMult(t *testing.T) {
 			[]*Point{NewGeneratorPoint()},
 		)
 	})
+
+	for _, sz := range testSizes {
+		t.Run(fmt.Sprintf("MultiScalarMultBasepointVartime/%d", sz), func(t *testing.T) {
+			scalars, points, check := setupTestMultiScalarMult(sz)
+
+			var us []*Scalar
+			for i := 0; i < sz/2+1; i++ {
+				u := NewScalar().DebugMustRandomizeNonZero()
+				us = append(us, u)
+				check.Add(check, newRcvr().ScalarBaseMult(u))
+			}
+
+			out := newRcvr().MultiScalarMultBasepointVartime(us, scalars, points)
+			requirePointEquals(t, check, out, "MultiScalarMultBasepointVartime")
+		})
+	}
+	require.Panics(t, func() {
+		NewIdentityPoint().MultiScalarMultBasepointVartime(
+			nil,
+			[]*Scalar{NewScalar(), NewScalar()},
+			[]*Point{NewGeneratorPoint()},
+		)
+	})
 }
 
 func setupTestMultiScalarMult(sz int) ([]*Scalar, []*Point, *Point) {
@@ -70,7 +93,22 @@ func setupTestMultiScalarMult(sz int) ([]*Scalar, []*Point, *Point) {
 }
 
 func benchPointMultiScalarMult(b *testing.B) {
-	benchSizes := []int{1, 16, 32, 64, 1024}
+	benchSizes := []int{1, 8, 16, 32, 64, 512, 1024}
+
+	for _, sz := range benchSizes {
+		b.Run(fmt.Sprintf("NaiveVartime/%d", sz), func(b *testing.B) {
+			scalars, points, _ := setupTestMultiScalarMult(sz)
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				v, tmp := newRcvr().Identity(), newRcvr()
+				for j := range scalars {
+					v.Add(v, tmp.scalarMultVartimeGLV(scalars[j], points[j]))
+				}
+			}
+		})
+	}
 
 	for _, sz := range benchSizes {
 		b.Run(fmt.Sprintf("MultiScalarMult/%d", sz), func(b *testing.B) {
@@ -95,4 +133,17 @@ func benchPointMultiScalarMult(b *testing.B) {
 			}
 		})
 	}
+
+	for _, sz := range benchSizes {
+		b.Run(fmt.Sprintf("MultiScalarMultBasepointVartime/%d", sz), func(b *testing.B) {
+			scalars, points, _ := setupTestMultiScalarMult(sz)
+			us := []*Scalar{NewScalar().DebugMustRandomizeNonZero()}
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				_ = newRcvr().MultiScalarMultBasepointVartime(us, scalars, points)
+			}
+		})
+	}
 }
@@ -128,6 +128,17 @@ func (tbl *affinePointMultTable) SelectAndAdd(sum *Point, idx uint64) *Point {
 	return sum.uncheckedConditionalSelect(tmp, sum, isInfinity)
 }
 
+// SelectAndAddVartime sets `sum = sum + idx * P`, and returns `sum` in
+// variable time.  idx MUST be in the range of `[0, 15]`.
+func (tbl *affinePointMultTable) SelectAndAddVartime(sum *Point, idx uint64) *Point {
+	if idx == 0 {
+		return sum
+	}
+
+	p := &tbl[idx-1]
+	return sum.addMixed(sum, &p.x, &p.y)
+}
+
 // This stores the odd-indexed doubled tables of precomputed multiples of
 // G, such that interleaved with generatorHugeAffineTable one ends up
 // with a series of 64 tables of precomputed multiples of G [1G, ... 15G],
@@ -11,18 +11,15 @@ import (
 
 	"gitlab.com/yawning/secp256k1-voi/internal/field"
 	"gitlab.com/yawning/secp256k1-voi/internal/helpers"
+	"gitlab.com/yawning/secp256k1-voi/internal/pointbatch"
 )
 
 // See: https://www.secg.org/sec1-v2.pdf
 //
-// There apparently is a "hybrid" format in X9.62 which is uncompressed
-// but with the prefix encoding if y is odd or even.  However:
-// - That's fucking moronic.
-// - Not part of SEC 1.
-// - A PDF copy of X9.62 costs 100 USD, assuming I don't get it from
-// a domain that ends in `ru` or similar.
-// - If you absolutely need to deal with a point in that format, it's
-// trivial to convert to either of the supported encodings.
+// There is also a "hybrid" format in X9.62 which is uncompressed but
+// with the prefix encoding if y is odd or even.  It is not part of
+// SEC 1, and is only supported for decoding, for interoperability
+// with legacy implementations.
 
 const (
 	// CompressedPointSize is the size of a compressed point in bytes,
@@ -46,6 +43,8 @@ const (
 	prefixCompressedEven = 0x02
 	prefixCompressedOdd  = 0x03
 	prefixUncompressed   = 0x04
+	prefixHybridEven     = 0x06
+	prefixHybridOdd      = 0x07
 )
 
 var (
@@ -85,6 +84,74 @@ func (v *Point) getUncompressedBytes(dst *[UncompressedPointSize]byte) []byte {
 	return buf
 }
 
+func init() {
+	pointbatch.RegisterUncompressedBytes(batchUncompressedBytes)
+}
+
+// batchUncompressedBytes returns the SEC 1, Version 2.0, Section 2.3.3
+// uncompressed or infinity encoding of each of `points`, using
+// Montgomery's trick to share a single field inversion across the
+// batch.
+//
+// Note: As with UncompressedBytes, which (if any) of `points` are the
+// point at infinity is not treated as secret.
+func batchUncompressedBytes(points []*Point) [][]byte {
+	assertPointsValid(points...)
+
+	n := len(points)
+	if n == 0 {
+		return nil
+	}
+
+	// Substitute 1 for Z = 0 (the point at infinity), so that a single
+	// point at infinity does not zero the running product.
+	var one field.Element
+	one.One()
+
+	zs := make([]field.Element, n)
+	for i, p := range points {
+		zs[i].ConditionalSelect(&p.z, &one, p.IsIdentity())
+	}
+
+	// acc[i] = zs[0] * ... * zs[i]
+	acc := make([]field.Element, n)
+	acc[0].Set(&zs[0])
+	for i := 1; i < n; i++ {
+		acc[i].Multiply(&acc[i-1], &zs[i])
+	}
+
+	// As with rescale, the inversion is Fermat's Little Theorem based,
+	// and constant time.
+	invs := make([]field.Element, n)
+	var inv field.Element
+	inv.Invert(&acc[n-1])
+	for i := n - 1; i > 0; i-- {
+		invs[i].Multiply(&inv, &acc[i-1])
+		inv.Multiply(&inv, &zs[i])
+	}
+	invs[0].Set(&inv)
+
+	ret := make([][]byte, 0, n)
+	for i, p := range points {
+		if p.IsIdentity() != 0 {
+			ret = append(ret, []byte{prefixIdentity})
+			continue
+		}
+
+		var x, y field.Element
+		x.Multiply(&invs[i], &p.x)
+		y.Multiply(&invs[i], &p.y)
+
+		buf := make([]byte, 0, UncompressedPointSize)
+		buf = append(buf, prefixUncompressed)
+		buf = append(buf, x.Bytes()...)
+		buf = append(buf, y.Bytes()...)
+		ret = append(ret, buf)
+	}
+
+	return ret
+}
+
 // CompressedBytes returns the SEC 1, Version 2.0, Section 2.3.3
 // compressed or infinity encoding of `v`.
 func (v *Point) CompressedBytes() []byte {
@@ -114,21 +181,82 @@ func (v *Point) getCompressedBytes(dst *[CompressedPointSize]byte) []byte {
 	return buf
 }
 
-// XBytes returns the SEC 1, Version 2.0, Section 2.3.5 encoding of the
-// x-coordinate, or an error if the point is the point at infinity.
-func (v *Point) XBytes() ([]byte, error) {
-	assertPointsValid(v)
+// EqualCompressedBytes returns true iff `b` is the SEC 1, Version 2.0,
+// Section 2.3.3 compressed encoding of `v`, or the 1-byte `0x00`
+// encoding iff `v` is the point at infinity.  This is equivalent to,
+// but cheaper than, decoding `b` and comparing the points, and does
+// not allocate.
+func (v *Point) EqualCompressedBytes(b []byte) bool {
+	var dst [CompressedPointSize]byte
+	vBytes := v.getCompressedBytes(&dst)
 
-	if v.IsIdentity() != 0 {
-		return nil, errPointNotOnCurve
+	return subtle.ConstantTimeCompare(vBytes, b) == 1
+}
+
+// MarshalBinaryAllowIdentity returns the SEC 1, Version 2.0, Section
+// 2.3.3 compressed encoding of `v`, or the 1-byte `0x00` encoding iff
+// `v` is the point at infinity.
+//
+// Note: This is identical to CompressedBytes, and exists to provide an
+// explicit pathway for callers that need to serialize the point at
+// infinity, paired with UnmarshalBinaryAllowIdentity.
+func (v *Point) MarshalBinaryAllowIdentity() []byte {
+	return v.CompressedBytes()
+}
+
+// UnmarshalBinaryAllowIdentity sets `v = src`, where `src` is either a
+// valid SEC 1, Version 2.0, Section 2.3.3 compressed encoding of a
+// point, or the 1-byte `0x00` encoding of the point at infinity.  If
+// `src` is not a valid encoding, UnmarshalBinaryAllowIdentity returns
+// an error, and the receiver is unchanged.
+func (v *Point) UnmarshalBinaryAllowIdentity(src []byte) error {
+	switch len(src) {
+	case IdentityPointSize, CompressedPointSize:
+	default:
+		return errInvalidEncoding
 	}
 
+	_, err := v.SetBytes(src)
+	return err
+}
+
+// MarshalBinary returns the SEC 1, Version 2.0, Section 2.3.3
+// compressed encoding of `v`, or the 1-byte `0x00` encoding iff `v` is
+// the point at infinity.  This implements the [encoding.BinaryMarshaler]
+// interface.
+func (v *Point) MarshalBinary() ([]byte, error) {
+	return v.MarshalBinaryAllowIdentity(), nil
+}
+
+// UnmarshalBinary sets `v = src`, where `src` is either a valid SEC 1,
+// Version 2.0, Section 2.3.3 compressed encoding of a point, or the
+// 1-byte `0x00` encoding of the point at infinity.  If `src` is not a
+// valid encoding, UnmarshalBinary returns an error, and the receiver
+// is unchanged.  This implements the [encoding.BinaryUnmarshaler]
+// interface.
+//
+// Note: The point at infinity is only ever accepted in the form of
+// the explicit `0x00` sentinel, as with SetBytes.  The uncompressed
+// encoding is rejected.
+func (v *Point) UnmarshalBinary(src []byte) error {
+	return v.UnmarshalBinaryAllowIdentity(src)
+}
+
+// XBytes returns the SEC 1, Version 2.0, Section 2.3.5 encoding of the
+// x-coordinate, or an error if the point is the point at infinity.
+func (v *Point) XBytes() ([]byte, error) {
 	// Blah outline blah escape analysis blah.
 	var dst [CoordSize]byte
 	return v.getXBytes(&dst)
 }
 
 func (v *Point) getXBytes(dst *[CoordSize]byte) ([]byte, error) {
+	assertPointsValid(v)
+
+	if v.IsIdentity() != 0 {
+		return nil, errPointNotOnCurve
+	}
+
 	scaled := newRcvr().rescale(v) // XXX/perf: Don't need to rescale Y.
 	return append(dst[:0], scaled.x.Bytes()...), nil
 }
@@ -208,10 +336,44 @@ func (v *Point) SetUncompressedBytes(src []byte) (*Point, error) {
 	return v, nil
 }
 
+// SetHybridBytes sets `p = src`, where `src` is a valid X9.62 hybrid
+// encoding of a point (`0x06 | X | Y` or `0x07 | X | Y`, with the
+// prefix indicating if Y is even or odd).  If `src` is not a valid
+// encoding of `p`, including if the prefix does not match the parity
+// of the Y-coordinate, SetHybridBytes returns nil and an error, and the
+// receiver is unchanged.
+func (v *Point) SetHybridBytes(src []byte) (*Point, error) {
+	if len(src) != UncompressedPointSize {
+		return nil, errInvalidEncoding
+	}
+
+	var yIsOdd uint64
+	switch src[0] {
+	case prefixHybridEven:
+	case prefixHybridOdd:
+		yIsOdd = 1
+	default:
+		return nil, errInvalidPrefix
+	}
+
+	var tmp [UncompressedPointSize]byte
+	copy(tmp[:], src)
+	tmp[0] = prefixUncompressed
+
+	if _, yParity := SplitUncompressedPoint(tmp[:]); yParity != yIsOdd {
+		return nil, errInvalidPrefix
+	}
+
+	return v.SetUncompressedBytes(tmp[:])
+}
+
 // SetBytes sets `p = src`, where `src` is a valid SEC 1, Version 2.0,
 // Section 2.3.3 encoding of a point.  If `src` is not a valid encoding
 // of `p`, SetBytes returns nil and an error, and the receiver is
 // unchanged.
+//
+// Note: For interoperability, the X9.62 hybrid encoding is also
+// accepted, as in `SetHybridBytes`.
 func (v *Point) SetBytes(src []byte) (*Point, error) {
 	switch len(src) {
 	case IdentityPointSize:
@@ -223,6 +385,9 @@ func (v *Point) SetBytes(src []byte) (*Point, error) {
 	case CompressedPointSize:
 		return v.SetCompressedBytes(src)
 	case UncompressedPointSize:
+		if src[0] == prefixHybridEven || src[0] == prefixHybridOdd {
+			return v.SetHybridBytes(src)
+		}
 		return v.SetUncompressedBytes(src)
 	}
 
@@ -230,7 +395,7 @@ func (v *Point) SetBytes(src []byte) (*Point, error) {
 }
 
 // NewPointFromBytes creates a new Point from either of the SEC 1
-// encodings (uncompressed or compressed).
+// encodings (uncompressed or compressed), or the X9.62 hybrid encoding.
 func NewPointFromBytes(src []byte) (*Point, error) {
 	p, err := newRcvr().SetBytes(src)
 	if err != nil {
@@ -6,13 +6,16 @@ package secp256k1
 
 import (
 	"bytes"
+	"encoding/gob"
 	"fmt"
+	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	"gitlab.com/yawning/secp256k1-voi/internal/field"
 	"gitlab.com/yawning/secp256k1-voi/internal/helpers"
+	"gitlab.com/yawning/secp256k1-voi/internal/pointbatch"
 )
 
 const randomTestIters = 1000
@@ -21,6 +24,81 @@ func TestPoint(t *testing.T) {
 	t.Run("Uninitialized", func(t *testing.T) {
 		var p Point
 		require.Panics(t, func() { assertPointsValid(&p) })
+		require.Panics(t, func() { p.IsInPrimeOrderSubgroup() })
+	})
+	t.Run("ConstantTimeSelectPoint", func(t *testing.T) {
+		tbl := make([]*Point, 0, 5)
+		for i := uint64(1); i <= 5; i++ {
+			tbl = append(tbl, GeneratorMultiple(i))
+		}
+
+		for i, p := range tbl {
+			selected := ConstantTimeSelectPoint(tbl, uint64(i))
+			require.EqualValues(t, 1, p.Equal(selected), "ConstantTimeSelectPoint(tbl, %d)", i)
+		}
+
+		selected := ConstantTimeSelectPoint(tbl, uint64(len(tbl)))
+		require.EqualValues(t, 1, selected.IsIdentity(), "ConstantTimeSelectPoint(tbl, out of range)")
+
+		selected = ConstantTimeSelectPoint(nil, 0)
+		require.EqualValues(t, 1, selected.IsIdentity(), "ConstantTimeSelectPoint(nil, 0)")
+
+		require.Panics(t, func() {
+			ConstantTimeSelectPoint([]*Point{NewGeneratorPoint(), {}}, 0)
+		}, "ConstantTimeSelectPoint(uninitialized)")
+	})
+	t.Run("ConditionalSwap", func(t *testing.T) {
+		g, id := NewGeneratorPoint(), NewIdentityPoint()
+		a, b := NewPointFrom(g), NewPointFrom(id)
+
+		a.ConditionalSwap(b, 0)
+		requirePointDeepEquals(t, g, a, "ConditionalSwap(0) - a")
+		requirePointDeepEquals(t, id, b, "ConditionalSwap(0) - b")
+
+		a.ConditionalSwap(b, 1)
+		requirePointDeepEquals(t, id, a, "ConditionalSwap(1) - a")
+		requirePointDeepEquals(t, g, b, "ConditionalSwap(1) - b")
+	})
+	t.Run("GeneratorMultiple", func(t *testing.T) {
+		for _, n := range []uint64{0, 1, 2, 3, 15, 16, 17, 69, 0xffffffffffffffff} {
+			expected := NewIdentityPoint().ScalarBaseMult(NewScalarFromUint64(n))
+			require.EqualValues(t, 1, expected.Equal(GeneratorMultiple(n)), "GeneratorMultiple(%d)", n)
+		}
+	})
+	t.Run("IsInPrimeOrderSubgroup", func(t *testing.T) {
+		require.EqualValues(t, 1, NewIdentityPoint().IsInPrimeOrderSubgroup(), "Identity")
+		require.EqualValues(t, 1, NewGeneratorPoint().IsInPrimeOrderSubgroup(), "G")
+
+		s := NewScalar().DebugMustRandomizeNonZero()
+		p := NewIdentityPoint().ScalarBaseMult(s)
+		require.EqualValues(t, 1, p.IsInPrimeOrderSubgroup(), "s * G")
+	})
+	t.Run("IsOnCurve", func(t *testing.T) {
+		require.EqualValues(t, 1, NewIdentityPoint().IsOnCurve(), "IsOnCurve(Identity)")
+		require.EqualValues(t, 1, NewGeneratorPoint().IsOnCurve(), "IsOnCurve(G)")
+
+		p := newRcvr().DebugMustRandomize().DebugMustRandomizeZ()
+		require.EqualValues(t, 1, p.IsOnCurve(), "IsOnCurve(random)")
+
+		p.y.Add(&p.y, &p.z)
+		require.EqualValues(t, 0, p.IsOnCurve(), "IsOnCurve(corrupted)")
+
+		require.Panics(t, func() {
+			(&Point{}).IsOnCurve()
+		})
+	})
+	t.Run("IsYOdd", func(t *testing.T) {
+		require.EqualValues(t, 0, NewGeneratorPoint().IsYOdd(), "G")
+
+		for i := 0; i < 16; i++ {
+			s := NewScalar().DebugMustRandomizeNonZero()
+			p := NewIdentityPoint().ScalarBaseMult(s)
+			negP := NewIdentityPoint().Negate(p)
+
+			prefix := p.CompressedBytes()[0]
+			require.EqualValues(t, prefix&1, p.IsYOdd(), "[%d]: s * G", i)
+			require.EqualValues(t, 1^p.IsYOdd(), negP.IsYOdd(), "[%d]: -(s * G)", i)
+		}
 	})
 	t.Run("S11n", testPointS11n)
 	t.Run("Add", testPointAdd)
@@ -30,8 +108,40 @@ func TestPoint(t *testing.T) {
 	testPointMultiScalarMult(t)
 	t.Run("ScalarBaseMult", testPointScalarBaseMult)
 	t.Run("DoubleScalarMultBasepointVartime", testPointDoubleScalarMultBasepointVartime)
+	t.Run("FixedBasePoint", testPointFixedBasePoint)
 
 	t.Run("GLV/Split", testScalarSplit)
+	t.Run("Parameters", func(t *testing.T) {
+		params := Parameters()
+
+		// p - 1 is the largest field element.
+		pMinusOne := new(big.Int).Sub(params.P, big.NewInt(1))
+		require.EqualValues(t, field.NewElement().Negate(field.NewElement().One()).Bytes(), pMinusOne.Bytes(), "P")
+
+		// n - 1 is the largest scalar.
+		nMinusOne := new(big.Int).Sub(params.N, big.NewInt(1))
+		require.EqualValues(t, NewScalar().Negate(NewScalarFromUint64(1)).Bytes(), nMinusOne.Bytes(), "N")
+		require.True(t, params.N.ProbablyPrime(20), "N is prime")
+
+		// Gy^2 = Gx^3 + a * Gx + b (mod p)
+		lhs := new(big.Int).Exp(params.Gy, big.NewInt(2), params.P)
+		rhs := new(big.Int).Exp(params.Gx, big.NewInt(3), params.P)
+		rhs.Add(rhs, new(big.Int).Mul(params.A, params.Gx))
+		rhs.Add(rhs, params.B)
+		rhs.Mod(rhs, params.P)
+		require.Zero(t, lhs.Cmp(rhs), "G is on the curve")
+
+		require.EqualValues(t, NewGeneratorPoint().UncompressedBytes()[1:33], params.Gx.Bytes(), "Gx")
+		require.Equal(t, 1, params.Cofactor, "Cofactor")
+		require.Equal(t, "1.3.132.0.10", params.OID.String(), "OID")
+
+		// Returns a copy.
+		params.N.SetUint64(69)
+		require.NotZero(t, Parameters().N.Cmp(params.N), "Parameters() - copy")
+	})
+	t.Run("SelfTest", func(t *testing.T) {
+		require.NoError(t, SelfTest(), "SelfTest")
+	})
 }
 
 func testPointS11n(t *testing.T) {
@@ -55,6 +165,44 @@ func testPointS11n(t *testing.T) {
 		gBytes := p.UncompressedBytes()
 		require.Equal(t, gUncompressed, gBytes, "G")
 	})
+	t.Run("G hybrid", func(t *testing.T) {
+		// G has an even y-coordinate, -G has an odd y-coordinate.
+		gHybrid := helpers.MustBytesFromHex("0679BE667EF9DCBBAC55A06295CE870B07029BFCDB2DCE28D959F2815B16F81798483ADA7726A3C4655DA4FBFC0E1108A8FD17B448A68554199C47D08FFB10D4B8")
+		negGHybrid := helpers.MustBytesFromHex("0779BE667EF9DCBBAC55A06295CE870B07029BFCDB2DCE28D959F2815B16F81798B7C52588D95C3B9AA25B0403F1EEF75702E84BB7597AABE663B82F6F04EF2777")
+
+		p, err := NewPointFromBytes(gHybrid)
+		require.NoError(t, err, "NewPointFromBytes(gHybrid)")
+		requirePointDeepEquals(t, NewGeneratorPoint(), p, "G")
+
+		p, err = NewPointFromBytes(negGHybrid)
+		require.NoError(t, err, "NewPointFromBytes(negGHybrid)")
+		requirePointDeepEquals(t, NewIdentityPoint().Negate(NewGeneratorPoint()), p, "-G")
+
+		// Mismatched parity.
+		b := bytes.Clone(gHybrid)
+		b[0] = prefixHybridOdd
+		p, err = NewIdentityPoint().SetHybridBytes(b)
+		require.Nil(t, p, "SetHybridBytes(badParity)")
+		require.ErrorIs(t, err, errInvalidPrefix, "SetHybridBytes(badParity)")
+
+		b = bytes.Clone(negGHybrid)
+		b[0] = prefixHybridEven
+		_, err = NewPointFromBytes(b)
+		require.ErrorIs(t, err, errInvalidPrefix, "NewPointFromBytes(badParity)")
+
+		// Not on the curve (y = Gy + 2, same parity).
+		b = bytes.Clone(gHybrid)
+		b[len(b)-1] += 2
+		_, err = NewIdentityPoint().SetHybridBytes(b)
+		require.ErrorIs(t, err, errPointNotOnCurve, "SetHybridBytes(notOnCurve)")
+
+		_, err = NewIdentityPoint().SetHybridBytes(gHybrid[:64])
+		require.ErrorIs(t, err, errInvalidEncoding, "SetHybridBytes(truncated)")
+
+		// The uncompressed routine does not accept the hybrid encoding.
+		_, err = NewIdentityPoint().SetUncompressedBytes(gHybrid)
+		require.ErrorIs(t, err, errInvalidPrefix, "SetUncompressedBytes(gHybrid)")
+	})
 	t.Run("Identity", func(t *testing.T) {
 		secIDBytes := []byte{prefixIdentity}
 
@@ -73,11 +221,133 @@ func testPointS11n(t *testing.T) {
 		_, err = newRcvr().SetBytes([]byte{69})
 		require.ErrorIs(t, err, errInvalidPrefix, "SetBytes(69)")
 	})
+	t.Run("BatchUncompressedBytes", func(t *testing.T) {
+		require.Nil(t, batchUncompressedBytes(nil), "batchUncompressedBytes(nil)")
+
+		points := []*Point{
+			NewGeneratorPoint(),
+			NewIdentityPoint(),
+			GeneratorMultiple(2),
+			NewIdentityPoint().Add(GeneratorMultiple(3), GeneratorMultiple(4)),
+			NewIdentityPoint(),
+		}
+		bufs := batchUncompressedBytes(points)
+		require.Len(t, bufs, len(points), "batchUncompressedBytes")
+		for i, p := range points {
+			require.Equal(t, p.UncompressedBytes(), bufs[i], "[%d]: batchUncompressedBytes == UncompressedBytes", i)
+		}
+		require.Equal(t, bufs, pointbatch.UncompressedBytes(points), "pointbatch.UncompressedBytes")
+	})
+	t.Run("Identity/AllowIdentity", func(t *testing.T) {
+		secIDBytes := []byte{prefixIdentity}
+
+		// The identity MUST round-trip through all of the encoders
+		// and decoders.
+		id := NewIdentityPoint()
+		for _, idBytes := range [][]byte{
+			id.CompressedBytes(),
+			id.UncompressedBytes(),
+			id.MarshalBinaryAllowIdentity(),
+		} {
+			require.Equal(t, secIDBytes, idBytes, "Identity")
+
+			p := NewGeneratorPoint()
+			err := p.UnmarshalBinaryAllowIdentity(idBytes)
+			require.NoError(t, err, "UnmarshalBinaryAllowIdentity(id)")
+			require.EqualValues(t, 1, p.IsIdentity(), "UnmarshalBinaryAllowIdentity(id)")
+
+			p = NewGeneratorPoint()
+			_, err = p.SetBytes(idBytes)
+			require.NoError(t, err, "SetBytes(id)")
+			requirePointDeepEquals(t, NewIdentityPoint(), p, "SetBytes(id)")
+		}
+
+		// Non-identity points must also round-trip.
+		g := NewGeneratorPoint()
+		gBytes := g.MarshalBinaryAllowIdentity()
+		require.Equal(t, g.CompressedBytes(), gBytes, "G")
+
+		p := NewIdentityPoint()
+		err := p.UnmarshalBinaryAllowIdentity(gBytes)
+		require.NoError(t, err, "UnmarshalBinaryAllowIdentity(G)")
+		requirePointEquals(t, g, p, "UnmarshalBinaryAllowIdentity(G)")
+
+		// Only the compressed and identity encodings are accepted.
+		p = NewGeneratorPoint()
+		err = p.UnmarshalBinaryAllowIdentity(g.UncompressedBytes())
+		require.ErrorIs(t, err, errInvalidEncoding, "UnmarshalBinaryAllowIdentity(uncompressed)")
+		err = p.UnmarshalBinaryAllowIdentity([]byte{69})
+		require.ErrorIs(t, err, errInvalidPrefix, "UnmarshalBinaryAllowIdentity(69)")
+		err = p.UnmarshalBinaryAllowIdentity(nil)
+		require.ErrorIs(t, err, errInvalidEncoding, "UnmarshalBinaryAllowIdentity(nil)")
+		requirePointDeepEquals(t, NewGeneratorPoint(), p, "UnmarshalBinaryAllowIdentity(bad) - unchanged")
+	})
+	t.Run("BinaryMarshaler", func(t *testing.T) {
+		type wrapper struct {
+			P, ID *Point
+		}
+
+		in := &wrapper{
+			P:  NewIdentityPoint().ScalarBaseMult(NewScalar().DebugMustRandomizeNonZero()),
+			ID: NewIdentityPoint(),
+		}
+
+		var buf bytes.Buffer
+		err := gob.NewEncoder(&buf).Encode(in)
+		require.NoError(t, err, "gob.Encode")
+
+		var out wrapper
+		err = gob.NewDecoder(&buf).Decode(&out)
+		require.NoError(t, err, "gob.Decode")
+		require.EqualValues(t, 1, in.P.Equal(out.P), "gob round-trip")
+		require.EqualValues(t, 1, out.ID.IsIdentity(), "gob round-trip - identity")
+
+		p := NewGeneratorPoint()
+		err = p.UnmarshalBinary(in.P.UncompressedBytes())
+		require.ErrorIs(t, err, errInvalidEncoding, "UnmarshalBinary(uncompressed)")
+		err = p.UnmarshalBinary([]byte{prefixCompressedEven})
+		require.Error(t, err, "UnmarshalBinary(bad identity)")
+		require.EqualValues(t, 1, p.Equal(NewGeneratorPoint()), "UnmarshalBinary - receiver unchanged")
+	})
 	t.Run("NewPointFromCoords", func(t *testing.T) {
 		p, err := NewPointFromCoords((*[CoordSize]byte)(feGX.Bytes()), (*[CoordSize]byte)(feGY.Bytes()))
 		require.NoError(t, err, "NewPointFromCoords(gX, gY)")
 
 		requirePointEquals(t, NewGeneratorPoint(), p, "NewPointFromCoords(gX, gY)")
+
+		var zero [CoordSize]byte
+		p, err = NewPointFromCoords(&zero, &zero)
+		require.Nil(t, p, "NewPointFromCoords(0, 0)")
+		require.ErrorIs(t, err, errPointNotOnCurve, "NewPointFromCoords(0, 0)")
+
+		gYPlusOne := field.NewElement().Add(feGY, field.NewElementFromUint64(1))
+		_, err = NewPointFromCoords((*[CoordSize]byte)(feGX.Bytes()), (*[CoordSize]byte)(gYPlusOne.Bytes()))
+		require.ErrorIs(t, err, errPointNotOnCurve, "NewPointFromCoords(gX, gY + 1)")
+
+		// p is not a canonical field element.
+		pBytes := helpers.Must256BitsFromHex("fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f")
+		_, err = NewPointFromCoords(pBytes, (*[CoordSize]byte)(feGY.Bytes()))
+		require.Error(t, err, "NewPointFromCoords(p, gY)")
+		_, err = NewPointFromCoords((*[CoordSize]byte)(feGX.Bytes()), pBytes)
+		require.Error(t, err, "NewPointFromCoords(gX, p)")
+	})
+	t.Run("EqualCompressedBytes", func(t *testing.T) {
+		g := NewGeneratorPoint()
+		gBytes := g.CompressedBytes()
+
+		// Use a non-normalized representation to exercise the rescale.
+		g2 := NewIdentityPoint().Double(g)
+		g2.Subtract(g2, g)
+		require.True(t, g2.EqualCompressedBytes(gBytes), "g.EqualCompressedBytes(g)")
+
+		negG := NewIdentityPoint().Negate(g)
+		require.False(t, negG.EqualCompressedBytes(gBytes), "-g.EqualCompressedBytes(g)")
+		require.False(t, g.EqualCompressedBytes(g.UncompressedBytes()), "g.EqualCompressedBytes(uncompressed)")
+		require.False(t, g.EqualCompressedBytes(gBytes[:CompressedPointSize-1]), "g.EqualCompressedBytes(truncated)")
+
+		id := NewIdentityPoint()
+		require.True(t, id.EqualCompressedBytes([]byte{prefixIdentity}), "id.EqualCompressedBytes(id)")
+		require.False(t, id.EqualCompressedBytes(gBytes), "id.EqualCompressedBytes(g)")
 	})
 	t.Run("XBytes", func(t *testing.T) {
 		g := NewGeneratorPoint()
@@ -254,11 +524,46 @@ func testPointScalarMult(t *testing.T) {
 		require.NoError(t, err, "NewPointFromBytes(bUncompressed)")
 
 		aXn := newRcvr().ScalarMult(xn, a)
-		aXnV := newRcvr().scalarMultVartimeGLV(xn, a)
+		aXnV := newRcvr().ScalarMultVartime(xn, a)
 
 		requirePointEquals(t, bExpected, aXn, "xn * a == b")
 		requirePointEquals(t, bExpected, aXnV, "xn * a (vartime) == b")
 	})
+	t.Run("ScalarMultVartime/Uninitialized", func(t *testing.T) {
+		require.Panics(t, func() {
+			NewIdentityPoint().ScalarMultVartime(NewScalar(), &Point{})
+		})
+	})
+	t.Run("ScalarMultBytes", func(t *testing.T) {
+		s := NewScalar().DebugMustRandomizeNonZero()
+		p := newRcvr().DebugMustRandomize()
+
+		check := newRcvr().ScalarMult(s, p)
+		q, err := newRcvr().ScalarMultBytes((*[ScalarSize]byte)(s.Bytes()), p)
+		require.NoError(t, err, "ScalarMultBytes")
+		requirePointEquals(t, check, q, "s * P (bytes) == s * P")
+
+		// Non-canonical scalars are reduced mod n.
+		for _, sHex := range []string{
+			"0xfffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", // N
+			"0xfffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364142", // N + 1
+			"0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff", // 2^256 - 1
+		} {
+			sBytes := helpers.Must256BitsFromHex(sHex)
+			s, _ := NewScalarFromBytes(sBytes)
+			expected := newRcvr().ScalarMult(s, p)
+
+			q, err := NewGeneratorPoint().ScalarMultBytes(sBytes, p)
+			require.NoError(t, err, "ScalarMultBytes(%s)", sHex)
+			requirePointEquals(t, expected, q, "ScalarMultBytes(non-canonical)")
+		}
+		q, err = newRcvr().ScalarMultBytes(helpers.Must256BitsFromHex("0xfffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141"), p)
+		require.NoError(t, err, "ScalarMultBytes(N)")
+		require.EqualValues(t, 1, q.IsIdentity(), "ScalarMultBytes(N) == identity")
+		q, err = newRcvr().ScalarMultBytes(helpers.Must256BitsFromHex("0xfffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364142"), p)
+		require.NoError(t, err, "ScalarMultBytes(N + 1)")
+		requirePointEquals(t, p, q, "ScalarMultBytes(N + 1) == p")
+	})
 	t.Run("Consistency", func(t *testing.T) {
 		var s Scalar
 		check := newRcvr().DebugMustRandomize()
@@ -268,7 +573,7 @@ func testPointScalarMult(t *testing.T) {
 			s.DebugMustRandomizeNonZero()
 			check := check.scalarMultTrivial(&s, check)
 			p1.ScalarMult(&s, p1)
-			p2.scalarMultVartimeGLV(&s, p2)
+			p2.ScalarMultVartime(&s, p2) // Aliased.
 
 			requirePointEquals(t, check, p1, fmt.Sprintf("[%d]: s * check (trivial) == s * p1 (ct)", i))
 			requirePointEquals(t, p1, p2, fmt.Sprintf("[%d]: s * p1 (ct) == s * p2 (vartime)", i))
@@ -346,6 +651,44 @@ func testPointDoubleScalarMultBasepointVartime(t *testing.T) {
 	})
 }
 
+func testPointFixedBasePoint(t *testing.T) {
+	t.Run("Identity", func(t *testing.T) {
+		fb := NewFixedBasePoint(NewIdentityPoint())
+		s := NewScalar().DebugMustRandomizeNonZero()
+
+		q := fb.ScalarMult(s)
+		require.EqualValues(t, 1, q.IsIdentity(), "s * id == id, got %+v", q)
+
+		q = fb.ScalarMultVartime(s)
+		require.EqualValues(t, 1, q.IsIdentity(), "s * id == id (vartime), got %+v", q)
+	})
+	t.Run("0 * P", func(t *testing.T) {
+		fb := NewFixedBasePoint(newRcvr().DebugMustRandomize())
+
+		q := fb.ScalarMult(NewScalar())
+		require.EqualValues(t, 1, q.IsIdentity(), "0 * P == id, got %+v", q)
+
+		q = fb.ScalarMultVartime(NewScalar())
+		require.EqualValues(t, 1, q.IsIdentity(), "0 * P == id (vartime), got %+v", q)
+	})
+	t.Run("Consistency", func(t *testing.T) {
+		var s Scalar
+		p := newRcvr().DebugMustRandomize().DebugMustRandomizeZ()
+		fb := NewFixedBasePoint(p)
+		check := newRcvr()
+		for i := 0; i < randomTestIters; i++ {
+			s.DebugMustRandomizeNonZero()
+			check.ScalarMult(&s, p)
+			q := fb.ScalarMult(&s)
+
+			requirePointEquals(t, check, q, fmt.Sprintf("[%d]: s * P (ct) != s * P (fixed base)", i))
+
+			q = fb.ScalarMultVartime(&s)
+			requirePointEquals(t, check, q, fmt.Sprintf("[%d]: s * P (ct) != s * P (fixed base, vartime)", i))
+		}
+	})
+}
+
 func (v *Point) DebugMustRandomize() *Point {
 	for {
 		s := NewScalar().DebugMustRandomizeNonZero()
@@ -424,7 +767,7 @@ func BenchmarkPoint(b *testing.B) {
 		b.ResetTimer()
 
 		for i := 0; i < b.N; i++ {
-			_, _ = s.splitGLV()
+			_, _ = s.splitGLV(NewScalar(), NewScalar())
 		}
 	})
 	b.Run("GLV/ScalarMult", func(b *testing.B) {
@@ -455,6 +798,17 @@ func BenchmarkPoint(b *testing.B) {
 		}
 	})
 
+	b.Run("GLV/ScalarMultBytes", func(b *testing.B) {
+		q := NewGeneratorPoint()
+		sBytes := (*[ScalarSize]byte)(NewScalar().DebugMustRandomizeNonZero().Bytes())
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			_, _ = q.ScalarMultBytes(sBytes, q)
+		}
+	})
+
 	b.Run("Add", func(b *testing.B) {
 		p := NewGeneratorPoint()
 		b.ReportAllocs()
@@ -496,6 +850,34 @@ func BenchmarkPoint(b *testing.B) {
 			q.ScalarBaseMult(&s)
 		}
 	})
+	b.Run("FixedBasePoint/ScalarMult", func(b *testing.B) {
+		var s Scalar
+		fb := NewFixedBasePoint(newRcvr().DebugMustRandomize())
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			s.DebugMustRandomizeNonZero()
+			b.StartTimer()
+
+			_ = fb.ScalarMult(&s)
+		}
+	})
+	b.Run("FixedBasePoint/ScalarMultVartime", func(b *testing.B) {
+		var s Scalar
+		fb := NewFixedBasePoint(newRcvr().DebugMustRandomize())
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			s.DebugMustRandomizeNonZero()
+			b.StartTimer()
+
+			_ = fb.ScalarMultVartime(&s)
+		}
+	})
 	b.Run("ScalarBaseMult/Vartime", func(b *testing.B) {
 		var s Scalar
 		q := NewGeneratorPoint()
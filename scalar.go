@@ -38,6 +38,8 @@ var (
 	}
 
 	errNonCanonicalEncoding = errors.New("secp256k1: scalar value out of range")
+	errVectorLengthMismatch = errors.New("secp256k1: scalar vector length mismatch")
+	errInvalidScalarLength  = errors.New("secp256k1: invalid scalar length")
 )
 
 // Scalar is an integer modulo `n = 2^256 - 432420386565659656852420866394968145599`.
@@ -92,6 +94,101 @@ func (s *Scalar) Square(a *Scalar) *Scalar {
 	return s
 }
 
+// Pow sets `s = a ^ e` and returns `s`.  This is constant time with
+// respect to both `a` and `e`.
+func (s *Scalar) Pow(a, e *Scalar) *Scalar {
+	var (
+		base, tmp Scalar
+		eBytes    [ScalarSize]byte
+	)
+	base.Set(a)
+	e.getBytes(&eBytes)
+
+	// Left-to-right binary exponentiation, always doing the multiply.
+	s.One()
+	for _, b := range eBytes {
+		for i := 7; i >= 0; i-- {
+			s.Square(s)
+			tmp.Multiply(s, &base)
+			s.ConditionalSelect(s, &tmp, uint64((b>>i)&1))
+		}
+	}
+
+	return s
+}
+
+// PowVartime sets `s = a ^ e` and returns `s`.
+//
+// WARNING: This is variable time with respect to `e`, and MUST only be
+// used with public exponents.
+func (s *Scalar) PowVartime(a, e *Scalar) *Scalar {
+	var (
+		base   Scalar
+		eBytes [ScalarSize]byte
+	)
+	base.Set(a)
+	e.getBytes(&eBytes)
+
+	// Left-to-right binary exponentiation, skipping the leading zeros.
+	s.One()
+	started := false
+	for _, b := range eBytes {
+		for i := 7; i >= 0; i-- {
+			if started {
+				s.Square(s)
+			}
+			if (b>>i)&1 == 1 {
+				s.Multiply(s, &base)
+				started = true
+			}
+		}
+	}
+
+	return s
+}
+
+// InvertVartime sets `s = 1 / a` and returns `s`.  If `a == 0`, `s`
+// is set to `0`.
+//
+// WARNING: This is variable time with respect to `a`, and MUST only be
+// used with public values.
+func (s *Scalar) InvertVartime(a *Scalar) *Scalar {
+	// Binary extended Euclidean algorithm, per "Guide to Elliptic Curve
+	// Cryptography", Algorithm 2.22.  As `n` is prime, `gcd(a, n) = 1`
+	// for all non-zero `a`, so `u` and `v` never reach 0.
+	u := a.Saturated()
+	if u == [4]uint64{} {
+		return s.Zero()
+	}
+	v := *(*[4]uint64)(nSat[:4])
+	x1, x2 := [4]uint64{1, 0, 0, 0}, [4]uint64{}
+
+	for !satIsOne(&u) && !satIsOne(&v) {
+		for u[0]&1 == 0 {
+			satShr1(&u, 0)
+			satHalveModN(&x1)
+		}
+		for v[0]&1 == 0 {
+			satShr1(&v, 0)
+			satHalveModN(&x2)
+		}
+
+		var tmp [4]uint64
+		if satSub(&tmp, &u, &v) == 0 {
+			u = tmp
+			satSubModN(&x1, &x2)
+		} else {
+			satSub(&v, &v, &u)
+			satSubModN(&x2, &x1)
+		}
+	}
+
+	if satIsOne(&u) {
+		return s.uncheckedSetSaturated(&x1)
+	}
+	return s.uncheckedSetSaturated(&x2)
+}
+
 // Sum sets `s = vec[0] + ... + vec[n]` and returns `s`.
 func (s *Scalar) Sum(vec ...*Scalar) *Scalar {
 	sum := NewScalar()
@@ -111,12 +208,86 @@ func (s *Scalar) Product(vec ...*Scalar) *Scalar {
 	return s.Set(product)
 }
 
+// InnerProduct returns `a[0] * b[0] + ... + a[n] * b[n]`.  If `a` and
+// `b` are empty, the result will be `0`.  If the length of `a` and `b`
+// differ, InnerProduct returns nil and an error.
+func InnerProduct(a, b []*Scalar) (*Scalar, error) {
+	if len(a) != len(b) {
+		return nil, errVectorLengthMismatch
+	}
+
+	var tmp Scalar
+	sum := NewScalar()
+	for i := range a {
+		fiat.Mul(&tmp.m, &a[i].m, &b[i].m)
+		fiat.Add(&sum.m, &sum.m, &tmp.m)
+	}
+
+	return sum, nil
+}
+
+// BatchInvert sets `out[i] = 1/in[i]` for each `i`, using Montgomery's
+// trick (1 inversion, and `3(n-1)` multiplications).  If `in[i] == 0`,
+// `out[i]` is set to `0`, without affecting the other results.  If the
+// length of `out` and `in` differ, BatchInvert returns an error.
+//
+// Note: This is constant time with respect to the values of `in`,
+// including which (if any) are `0`.  `out` and `in` are allowed to
+// alias.
+func BatchInvert(out, in []*Scalar) error {
+	if len(out) != len(in) {
+		return errVectorLengthMismatch
+	}
+	n := len(in)
+	if n == 0 {
+		return nil
+	}
+
+	// Substitute 1 for 0, so that a single zero does not zero the
+	// running product, and remember which entries were 0.
+	var one, zero Scalar
+	one.One()
+
+	vals := make([]Scalar, n)
+	isZero := make([]uint64, n)
+	for i, v := range in {
+		isZero[i] = v.IsZero()
+		vals[i].ConditionalSelect(v, &one, isZero[i])
+	}
+
+	// acc[i] = vals[0] * ... * vals[i]
+	acc := make([]Scalar, n)
+	acc[0].Set(&vals[0])
+	for i := 1; i < n; i++ {
+		acc[i].Multiply(&acc[i-1], &vals[i])
+	}
+
+	var inv Scalar
+	inv.Invert(&acc[n-1])
+	for i := n - 1; i > 0; i-- {
+		out[i].Multiply(&inv, &acc[i-1])
+		inv.Multiply(&inv, &vals[i])
+	}
+	out[0].Set(&inv)
+
+	for i, v := range out {
+		v.ConditionalSelect(v, &zero, isZero[i])
+	}
+
+	return nil
+}
+
 // Set sets `s = a` and returns `s`.
 func (s *Scalar) Set(a *Scalar) *Scalar {
 	copy(s.m[:], a.m[:])
 	return s
 }
 
+// SetUint64 sets `s = l0` and returns `s`.
+func (s *Scalar) SetUint64(l0 uint64) *Scalar {
+	return s.uncheckedSetSaturated(&[4]uint64{l0, 0, 0, 0})
+}
+
 // SetBytes sets `s = src`, where `src` is a 32-byte big-endian encoding
 // of `s`, and returns `s, 0`.  If `src` is not a canonical encoding of
 // `s`, `src` is reduced modulo n, and SetBytes returns `s, 1`.
@@ -151,12 +322,39 @@ func (s *Scalar) Bytes() []byte {
 	return s.getBytes(&dst)
 }
 
+// MarshalBinary returns the canonical big-endian encoding of `s`.  This
+// implements the [encoding.BinaryMarshaler] interface.
+func (s *Scalar) MarshalBinary() ([]byte, error) {
+	return s.Bytes(), nil
+}
+
+// UnmarshalBinary sets `s = src`, where `src` is a 32-byte canonical
+// big-endian encoding of `s`.  If `src` is not a canonical encoding of
+// `s`, UnmarshalBinary returns an error, and the receiver is unchanged.
+// This implements the [encoding.BinaryUnmarshaler] interface.
+func (s *Scalar) UnmarshalBinary(src []byte) error {
+	if len(src) != ScalarSize {
+		return errInvalidScalarLength
+	}
+
+	_, err := s.SetCanonicalBytes((*[ScalarSize]byte)(src))
+	return err
+}
+
 func (s *Scalar) getBytes(dst *[ScalarSize]byte) []byte {
 	var nm fiat.NonMontgomeryDomainFieldElement
 	fiat.FromMontgomery(&nm, &s.m)
 	return helpers.PutSaturatedToBytes(dst, (*[4]uint64)(&nm))
 }
 
+// Saturated returns the canonical saturated little-endian 64-bit limb
+// representation of `s` (ie: not in the Montgomery domain).
+func (s *Scalar) Saturated() [4]uint64 {
+	var nm fiat.NonMontgomeryDomainFieldElement
+	fiat.FromMontgomery(&nm, &s.m)
+	return nm
+}
+
 // ConditionalNegate sets `s = a` iff `ctrl == 0`, `s = -a` otherwise,
 // and returns `s`.
 func (s *Scalar) ConditionalNegate(a *Scalar, ctrl uint64) *Scalar {
@@ -172,6 +370,11 @@ func (s *Scalar) ConditionalSelect(a, b *Scalar, ctrl uint64) *Scalar {
 	return s
 }
 
+// ConditionalSwap swaps `s` and `a` iff `ctrl != 0`.
+func (s *Scalar) ConditionalSwap(a *Scalar, ctrl uint64) {
+	helpers.FiatLimbsConditionalSwap((*[4]uint64)(&s.m), (*[4]uint64)(&a.m), ctrl)
+}
+
 // Equal returns 1 iff `s == a`, 0 otherwise.
 func (s *Scalar) Equal(a *Scalar) uint64 {
 	return helpers.FiatLimbsAreEqual((*[4]uint64)(&s.m), (*[4]uint64)(&a.m))
@@ -205,6 +408,32 @@ func (s *Scalar) IsGreaterThanHalfN() uint64 {
 	return helpers.Uint64IsZero(borrow) & helpers.Uint64IsNonzero(diff[0]|diff[1]|diff[2]|diff[3])
 }
 
+// Cmp compares `s` and `a`, treated as integers in the range `[0, n)`,
+// and returns -1 if `s < a`, 0 if `s == a`, and 1 if `s > a`.
+//
+// Note: The comparison itself is constant time, however the return
+// value is an ordinary integer, and by definition reveals the relative
+// order of `s` and `a` to any code that branches on it.
+func (s *Scalar) Cmp(a *Scalar) int {
+	var sNm, aNm fiat.NonMontgomeryDomainFieldElement
+	fiat.FromMontgomery(&sNm, &s.m)
+	fiat.FromMontgomery(&aNm, &a.m)
+
+	var (
+		borrow uint64
+		diff   [4]uint64
+	)
+	diff[0], borrow = bits.Sub64(sNm[0], aNm[0], borrow)
+	diff[1], borrow = bits.Sub64(sNm[1], aNm[1], borrow)
+	diff[2], borrow = bits.Sub64(sNm[2], aNm[2], borrow)
+	diff[3], borrow = bits.Sub64(sNm[3], aNm[3], borrow)
+
+	// if borrow == 1, s < a (and diff != 0)
+	// if borrow == 0 && diff == 0, s = a
+	isNonzero := helpers.Uint64IsNonzero(diff[0] | diff[1] | diff[2] | diff[3])
+	return int(isNonzero) - 2*int(borrow)
+}
+
 func (s *Scalar) uncheckedSetSaturated(a *[4]uint64) *Scalar {
 	fiat.ToMontgomery(&s.m, (*fiat.NonMontgomeryDomainFieldElement)(a))
 	return s
@@ -238,7 +467,7 @@ func NewScalarFrom(other *Scalar) *Scalar {
 
 // NewScalarFromUint64 creates a new Scalar from a uint64.
 func NewScalarFromUint64(l0 uint64) *Scalar {
-	return NewScalar().uncheckedSetSaturated(&[4]uint64{l0, 0, 0, 0})
+	return NewScalar().SetUint64(l0)
 }
 
 // NewScalarFromBytes creates a new Scalar from the 32-byte big-endian
@@ -249,6 +478,18 @@ func NewScalarFromBytes(src *[ScalarSize]byte) (*Scalar, uint64) {
 	return NewScalar().SetBytes(src)
 }
 
+// NewScalarConstantTime creates a new Scalar from the 32-byte big-endian
+// encoding of `s`, and returns `s, 1` iff `src` is a canonical encoding
+// of a scalar in the range `[1, n)`.  Otherwise `src` is reduced modulo
+// n, and NewScalarConstantTime returns `s, 0`.  This is done in constant
+// time, unlike NewScalarFromCanonicalBytes.
+func NewScalarConstantTime(src *[ScalarSize]byte) (*Scalar, uint64) {
+	s, didReduce := NewScalarFromBytes(src)
+	isValid := helpers.Uint64IsZero(didReduce) & helpers.Uint64IsZero(s.IsZero())
+
+	return s, isValid
+}
+
 // NewScalarFromCanonicalBytes creates a new Scalar from the canonical
 // 32-byte big-endian byte representation.
 func NewScalarFromCanonicalBytes(src *[ScalarSize]byte) (*Scalar, error) {
@@ -260,6 +501,16 @@ func NewScalarFromCanonicalBytes(src *[ScalarSize]byte) (*Scalar, error) {
 	return s, nil
 }
 
+// NewScalarFromSaturated creates a new Scalar from the canonical
+// saturated little-endian 64-bit limb representation.
+func NewScalarFromSaturated(limbs [4]uint64) (*Scalar, error) {
+	if reduceSaturated(&limbs, &limbs) != 0 {
+		return nil, errNonCanonicalEncoding
+	}
+
+	return NewScalar().uncheckedSetSaturated(&limbs), nil
+}
+
 func newScalarFromCanonicalHex(str string) *Scalar {
 	s, err := NewScalarFromCanonicalBytes(helpers.Must256BitsFromHex(str))
 	if err != nil {
@@ -290,3 +541,51 @@ func reduceSaturated(dst, src *[4]uint64) uint64 {
 
 	return didReduce
 }
+
+func satIsOne(a *[4]uint64) bool {
+	return a[0] == 1 && a[1]|a[2]|a[3] == 0
+}
+
+// satShr1 sets `a = (carry || a) >> 1`.
+func satShr1(a *[4]uint64, carry uint64) {
+	a[0] = a[0]>>1 | a[1]<<63
+	a[1] = a[1]>>1 | a[2]<<63
+	a[2] = a[2]>>1 | a[3]<<63
+	a[3] = a[3]>>1 | carry<<63
+}
+
+// satSub sets `dst = a - b`, and returns the borrow.
+func satSub(dst, a, b *[4]uint64) uint64 {
+	var borrow uint64
+	dst[0], borrow = bits.Sub64(a[0], b[0], 0)
+	dst[1], borrow = bits.Sub64(a[1], b[1], borrow)
+	dst[2], borrow = bits.Sub64(a[2], b[2], borrow)
+	dst[3], borrow = bits.Sub64(a[3], b[3], borrow)
+	return borrow
+}
+
+// satAddN sets `a = a + n`, and returns the carry.
+func satAddN(a *[4]uint64) uint64 {
+	var carry uint64
+	a[0], carry = bits.Add64(a[0], nSat[0], 0)
+	a[1], carry = bits.Add64(a[1], nSat[1], carry)
+	a[2], carry = bits.Add64(a[2], nSat[2], carry)
+	a[3], carry = bits.Add64(a[3], nSat[3], carry)
+	return carry
+}
+
+// satHalveModN sets `a = a / 2 mod n`, for `a` in `[0, n)`.
+func satHalveModN(a *[4]uint64) {
+	var carry uint64
+	if a[0]&1 == 1 {
+		carry = satAddN(a)
+	}
+	satShr1(a, carry)
+}
+
+// satSubModN sets `a = a - b mod n`, for `a`, `b` in `[0, n)`.
+func satSubModN(a, b *[4]uint64) {
+	if satSub(a, a, b) != 0 {
+		_ = satAddN(a)
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2023 Yawning Angel
+//
+// SPDX-License-Identifier: BSD-3-Clause
+
+package secp256k1
+
+import "gitlab.com/yawning/secp256k1-voi/internal/helpers"
+
+// WideScalarSize is the maximum size of a wide scalar in bytes.
+const WideScalarSize = 64
+
+var (
+	scTwo192 = newScalarFromCanonicalHex("0x1000000000000000000000000000000000000000000000000")                // 2^192 mod n
+	scTwo384 = newScalarFromCanonicalHex("0x4551231950b75fc4402da1732fc9bec04551231950b75fc4402da1732fc9bebf") // 2^384 mod n
+)
+
+// SetWideBytes sets `s = src % n`, where `src` is a big-endian encoding
+// of `s` with a length in the range `[32,64]`-bytes, and returns `s`.
+// When `src` is 64-bytes of uniformly random data, the result is
+// uniformly distributed modulo `n` with negligible bias, making this
+// suitable for `hash_to_scalar` style constructions.
+func (s *Scalar) SetWideBytes(src []byte) *Scalar {
+	sLen := len(src)
+	switch {
+	case sLen < ScalarSize:
+		panic("secp256k1: wide scalar too short")
+	case sLen == ScalarSize:
+		// When possible, call the simpler routine.
+		s.SetBytes((*[ScalarSize]byte)(src))
+		return s
+	case sLen <= WideScalarSize:
+		// Use Frank Denis' trick, as documented by Filippo Valsorda
+		// at https://words.filippo.io/dispatches/wide-reduction/
+		//
+		// "I represent the value as a+b*2^192+c*2^384"
+
+		// Zero extend to 512-bits.
+		var src512 [WideScalarSize]byte
+		copy(src512[WideScalarSize-sLen:], src)
+
+		s.setShortBytes(src512[40:])                  // a
+		b := NewScalar().setShortBytes(src512[16:40]) // b
+		c := NewScalar().setShortBytes(src512[:16])   // c
+		s.Add(s, b.Multiply(b, scTwo192))
+		s.Add(s, c.Multiply(c, scTwo384))
+
+		return s
+	default:
+		panic("secp256k1: wide scalar too large")
+	}
+}
+
+func (s *Scalar) setShortBytes(src []byte) *Scalar {
+	// Invariant: sLen < ScalarSize, so src < n.
+	sLen := len(src)
+	if sLen >= ScalarSize {
+		panic("secp256k1: short scalar too wide")
+	}
+
+	// Zero extend to 256-bits.
+	var src256 [ScalarSize]byte
+	copy(src256[ScalarSize-sLen:], src)
+
+	// Unchecked set (s < n).
+	sat := helpers.BytesToSaturated(&src256)
+	return s.uncheckedSetSaturated(&sat)
+}
@@ -5,8 +5,12 @@
 package secp256k1
 
 import (
+	"bytes"
 	"crypto/rand"
+	"encoding/gob"
 	"encoding/hex"
+	"fmt"
+	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -52,7 +56,104 @@ func TestScalar(t *testing.T) {
 			require.Nil(t, s, "[%d]: SetCanonicalBytes(largerThanN)", i)
 		}
 	})
+	t.Run("BinaryMarshaler", func(t *testing.T) {
+		type wrapper struct {
+			S *Scalar
+		}
+
+		in := &wrapper{S: NewScalar().DebugMustRandomizeNonZero()}
+
+		var buf bytes.Buffer
+		err := gob.NewEncoder(&buf).Encode(in)
+		require.NoError(t, err, "gob.Encode")
+
+		var out wrapper
+		err = gob.NewDecoder(&buf).Decode(&out)
+		require.NoError(t, err, "gob.Decode")
+		require.EqualValues(t, 1, in.S.Equal(out.S), "gob round-trip")
+
+		s := NewScalar()
+		for i, raw := range geqN {
+			err = s.UnmarshalBinary(raw)
+			require.ErrorIs(t, err, errNonCanonicalEncoding, "[%d]: UnmarshalBinary(largerThanN)", i)
+		}
+		err = s.UnmarshalBinary(geqN[0][1:])
+		require.ErrorIs(t, err, errInvalidScalarLength, "UnmarshalBinary(truncated)")
+		require.EqualValues(t, 1, s.IsZero(), "UnmarshalBinary - receiver unchanged")
+	})
+	t.Run("SetWideBytes", func(t *testing.T) {
+		nBig, _ := new(big.Int).SetString(nStr[2:], 16)
+		checkBig := func(raw []byte) *Scalar {
+			var dst [ScalarSize]byte
+			v := new(big.Int).SetBytes(raw)
+			v.Mod(v, nBig)
+			s, err := NewScalarFromCanonicalBytes((*[ScalarSize]byte)(v.FillBytes(dst[:])))
+			require.NoError(t, err, "NewScalarFromCanonicalBytes(big)")
+			return s
+		}
+
+		huge := bytes.Repeat([]byte{0xff}, WideScalarSize)                                                             // 2^512-1
+		hugeReduced := newScalarFromCanonicalHex("0x9d671cd581c69bc5e697f5e45bcd07c6741496c20e7cf878896cf21467d7d13f") // From python
+		s := NewScalar().SetWideBytes(huge)
+		require.EqualValues(t, 1, hugeReduced.Equal(s), "SetWideBytes(huge)")
+		require.EqualValues(t, 1, checkBig(huge).Equal(s), "SetWideBytes(huge) - big.Int")
+
+		for i, raw := range geqN {
+			s.SetWideBytes(raw)
+			require.EqualValues(t, 1, geqNReduced[i].Equal(s), "[%d]: SetWideBytes(largerThanN)", i)
+
+			wide := append(make([]byte, WideScalarSize-ScalarSize), raw...)
+			s.SetWideBytes(wide)
+			require.EqualValues(t, 1, geqNReduced[i].Equal(s), "[%d]: SetWideBytes(0 || largerThanN)", i)
+		}
+
+		for i := 0; i < 16; i++ {
+			var raw [WideScalarSize]byte
+			_, _ = rand.Read(raw[:])
+			l := ScalarSize + i*2
+			s.SetWideBytes(raw[:l])
+			require.EqualValues(t, 1, checkBig(raw[:l]).Equal(s), "[%d]: SetWideBytes(rand) - big.Int", i)
+		}
+
+		require.Panics(t, func() {
+			NewScalar().SetWideBytes([]byte("not all that wide"))
+		})
+		require.Panics(t, func() {
+			tooHuge := append([]byte{0xff}, huge...)
+			NewScalar().SetWideBytes(tooHuge)
+		})
+	})
+	t.Run("NewScalarConstantTime", func(t *testing.T) {
+		for i, raw := range geqN {
+			s, isValid := NewScalarConstantTime((*[ScalarSize]byte)(raw))
+			require.EqualValues(t, 0, isValid, "[%d]: isValid NewScalarConstantTime(largerThanN)", i)
+			require.EqualValues(t, 1, geqNReduced[i].Equal(s), "[%d]: NewScalarConstantTime(largerThanN)", i)
+		}
+
+		var zero [ScalarSize]byte
+		s, isValid := NewScalarConstantTime(&zero)
+		require.EqualValues(t, 0, isValid, "isValid NewScalarConstantTime(0)")
+		require.EqualValues(t, 1, s.IsZero(), "NewScalarConstantTime(0)")
+
+		r := NewScalar().DebugMustRandomizeNonZero()
+		s, isValid = NewScalarConstantTime((*[ScalarSize]byte)(r.Bytes()))
+		require.EqualValues(t, 1, isValid, "isValid NewScalarConstantTime(rand)")
+		require.EqualValues(t, 1, r.Equal(s), "NewScalarConstantTime(rand)")
+	})
 
+	t.Run("SetUint64", func(t *testing.T) {
+		var s Scalar // The zero value MUST work.
+		s.SetUint64(0)
+		require.EqualValues(t, 1, s.IsZero(), "SetUint64(0)")
+
+		s.SetUint64(0xffffffffffffffff)
+		require.Equal(t, "000000000000000000000000000000000000000000000000ffffffffffffffff", s.String(), "SetUint64(max)")
+		require.EqualValues(t, 1, NewScalarFromUint64(0xffffffffffffffff).Equal(&s), "NewScalarFromUint64(max)")
+
+		s.SetUint64(3)
+		expected := NewScalar().Sum(scOne, scOne, scOne)
+		require.EqualValues(t, 1, expected.Equal(&s), "SetUint64(3)")
+	})
 	t.Run("Sum", func(t *testing.T) {
 		// Test the empty case.
 		s := NewScalar().Sum()
@@ -73,6 +174,148 @@ func TestScalar(t *testing.T) {
 		require.EqualValues(t, 1, scSix.Equal(s))
 	})
 
+	t.Run("Pow", func(t *testing.T) {
+		a := NewScalar().DebugMustRandomizeNonZero()
+
+		// a^0 = 1, a^1 = a, a^2 = a * a
+		s := NewScalar().Pow(a, NewScalar())
+		require.EqualValues(t, 1, scOne.Equal(s), "a^0")
+		s.Pow(a, scOne)
+		require.EqualValues(t, 1, a.Equal(s), "a^1")
+		s.Pow(a, NewScalarFromUint64(2))
+		require.EqualValues(t, 1, NewScalar().Square(a).Equal(s), "a^2")
+
+		// Fermat: a^(n-1) = 1
+		nMinusOne := NewScalar().Negate(scOne)
+		s.Pow(a, nMinusOne)
+		require.EqualValues(t, 1, scOne.Equal(s), "a^(n-1)")
+
+		// a^(n-2) = a^-1
+		nMinusTwo := NewScalar().Subtract(nMinusOne, scOne)
+		s.Pow(a, nMinusTwo)
+		require.EqualValues(t, 1, NewScalar().Invert(a).Equal(s), "a^(n-2)")
+
+		// Aliasing.
+		s.Set(a)
+		s.Pow(s, s)
+		e := NewScalarFrom(a)
+		require.EqualValues(t, 1, NewScalar().Pow(a, e).Equal(s), "s^s (aliased)")
+
+		// KAT, cross-checked against big.Int.Exp.
+		nBig, _ := new(big.Int).SetString(nStr[2:], 16)
+		for i := 0; i < 16; i++ {
+			a.DebugMustRandomizeNonZero()
+			e := NewScalar().DebugMustRandomizeNonZero()
+			if i == 0 {
+				e = NewScalarFromUint64(0x10001)
+			}
+
+			var dst [ScalarSize]byte
+			expectedBig := new(big.Int).Exp(new(big.Int).SetBytes(a.Bytes()), new(big.Int).SetBytes(e.Bytes()), nBig)
+			expected, err := NewScalarFromCanonicalBytes((*[ScalarSize]byte)(expectedBig.FillBytes(dst[:])))
+			require.NoError(t, err, "NewScalarFromCanonicalBytes(big)")
+
+			require.EqualValues(t, 1, expected.Equal(NewScalar().Pow(a, e)), "[%d]: Pow - big.Int", i)
+			require.EqualValues(t, 1, expected.Equal(NewScalar().PowVartime(a, e)), "[%d]: PowVartime - big.Int", i)
+		}
+	})
+	t.Run("PowVartime", func(t *testing.T) {
+		a := NewScalar().DebugMustRandomizeNonZero()
+
+		s := NewScalar().PowVartime(a, NewScalar())
+		require.EqualValues(t, 1, scOne.Equal(s), "a^0")
+		s.PowVartime(a, scOne)
+		require.EqualValues(t, 1, a.Equal(s), "a^1")
+
+		nMinusOne := NewScalar().Negate(scOne)
+		s.PowVartime(a, nMinusOne)
+		require.EqualValues(t, 1, scOne.Equal(s), "a^(n-1)")
+
+		// Aliasing.
+		s.Set(a)
+		s.PowVartime(s, s)
+		require.EqualValues(t, 1, NewScalar().Pow(a, a).Equal(s), "s^s (aliased)")
+	})
+
+	t.Run("InvertVartime", func(t *testing.T) {
+		s := NewScalar().InvertVartime(NewScalar())
+		require.EqualValues(t, 1, s.IsZero(), "1/0")
+
+		nMinusOne := NewScalar().Negate(scOne)
+		for i, a := range []*Scalar{
+			scOne,
+			NewScalarFromUint64(2),
+			nMinusOne,
+			NewScalarFrom(nMinusOne).Subtract(nMinusOne, scOne),
+		} {
+			expected := NewScalar().Invert(a)
+			s.InvertVartime(a)
+			require.EqualValues(t, 1, expected.Equal(s), "[%d]: InvertVartime", i)
+		}
+
+		for i := 0; i < randomTestIters; i++ {
+			a := NewScalar().DebugMustRandomizeNonZero()
+			expected := NewScalar().Invert(a)
+			s.InvertVartime(a)
+			require.EqualValues(t, 1, expected.Equal(s), "[%d]: InvertVartime(random)", i)
+
+			// Aliasing.
+			a.InvertVartime(a)
+			require.EqualValues(t, 1, expected.Equal(a), "[%d]: InvertVartime(random) - aliased", i)
+		}
+	})
+
+	t.Run("InnerProduct", func(t *testing.T) {
+		// Test the empty case.
+		s, err := InnerProduct(nil, nil)
+		require.NoError(t, err, "InnerProduct(nil, nil)")
+		require.EqualValues(t, 1, s.IsZero())
+
+		a := []*Scalar{NewScalarFromUint64(2), NewScalarFromUint64(3), NewScalarFromUint64(5)}
+		b := []*Scalar{NewScalarFromUint64(7), NewScalarFromUint64(11), NewScalarFromUint64(13)}
+		s, err = InnerProduct(a, b)
+		require.NoError(t, err, "InnerProduct(a, b)")
+		require.EqualValues(t, 1, NewScalarFromUint64(2*7+3*11+5*13).Equal(s))
+
+		s, err = InnerProduct(a, b[:2])
+		require.Nil(t, s, "InnerProduct(a, truncated)")
+		require.ErrorIs(t, err, errVectorLengthMismatch, "InnerProduct(a, truncated)")
+	})
+
+	t.Run("BatchInvert", func(t *testing.T) {
+		require.NoError(t, BatchInvert(nil, nil), "BatchInvert(nil, nil)")
+
+		in := make([]*Scalar, 0, 8)
+		for i := 0; i < 8; i++ {
+			s := NewScalar()
+			if i != 0 && i != 5 {
+				s.DebugMustRandomizeNonZero()
+			}
+			in = append(in, s)
+		}
+
+		out := make([]*Scalar, len(in))
+		for i := range out {
+			out[i] = NewScalar()
+		}
+		err := BatchInvert(out, in)
+		require.NoError(t, err, "BatchInvert")
+		for i, s := range in {
+			expected := NewScalar().Invert(s)
+			require.EqualValues(t, 1, expected.Equal(out[i]), "[%d]: BatchInvert", i)
+		}
+
+		// Aliased (in-place).
+		err = BatchInvert(out, out)
+		require.NoError(t, err, "BatchInvert - aliased")
+		for i, s := range in {
+			require.EqualValues(t, 1, s.Equal(out[i]), "[%d]: BatchInvert - aliased", i)
+		}
+
+		err = BatchInvert(out[:2], in)
+		require.ErrorIs(t, err, errVectorLengthMismatch, "BatchInvert - truncated")
+	})
+
 	t.Run("IsGreaterThanHalfN", func(t *testing.T) {
 		// N/2 = 7fffffffffffffffffffffffffffffff5d576e7357a4501ddfe92f46681b20a0
 		leqHalfN := []*Scalar{
@@ -94,6 +337,71 @@ func TestScalar(t *testing.T) {
 		}
 	})
 
+	t.Run("Cmp", func(t *testing.T) {
+		nMinusOne := NewScalar().Negate(scOne)
+		halfN := newScalarFromCanonicalHex("0x7fffffffffffffffffffffffffffffff5d576e7357a4501ddfe92f46681b20a0")
+		ordered := []*Scalar{
+			NewScalar(),
+			scOne,
+			NewScalarFromUint64(2),
+			newScalarFromCanonicalHex("0x100000000000000000000000000000000"),
+			halfN,
+			NewScalar().Add(halfN, scOne),
+			nMinusOne,
+		}
+		for i, a := range ordered {
+			for j, b := range ordered {
+				var expected int
+				switch {
+				case i < j:
+					expected = -1
+				case i > j:
+					expected = 1
+				}
+				require.Equal(t, expected, a.Cmp(b), "[%d].Cmp([%d])", i, j)
+			}
+		}
+	})
+
+	t.Run("ConditionalSwap", func(t *testing.T) {
+		a, b := NewScalarFromUint64(69), NewScalarFromUint64(420)
+		a2, b2 := NewScalarFrom(a), NewScalarFrom(b)
+
+		a2.ConditionalSwap(b2, 0)
+		require.EqualValues(t, 1, a2.Equal(a), "ConditionalSwap(0) - a")
+		require.EqualValues(t, 1, b2.Equal(b), "ConditionalSwap(0) - b")
+
+		for _, ctrl := range []uint64{1, 0xdeadbeef} {
+			a2.Set(a)
+			b2.Set(b)
+			a2.ConditionalSwap(b2, ctrl)
+			require.EqualValues(t, 1, a2.Equal(b), "ConditionalSwap(%x) - a", ctrl)
+			require.EqualValues(t, 1, b2.Equal(a), "ConditionalSwap(%x) - b", ctrl)
+		}
+	})
+	t.Run("Saturated", func(t *testing.T) {
+		s := newScalarFromCanonicalHex("0x0123456789abcdeffedcba98765432100011223344556677deadbeefcafebabe")
+		limbs := s.Saturated()
+		require.Equal(t, [4]uint64{
+			0xdeadbeefcafebabe,
+			0x0011223344556677,
+			0xfedcba9876543210,
+			0x0123456789abcdef,
+		}, limbs, "Saturated")
+
+		s2, err := NewScalarFromSaturated(limbs)
+		require.NoError(t, err, "NewScalarFromSaturated")
+		require.EqualValues(t, 1, s.Equal(s2), "NewScalarFromSaturated(s.Saturated())")
+
+		nMinusOne := NewScalar().Negate(scOne)
+		s2, err = NewScalarFromSaturated(nMinusOne.Saturated())
+		require.NoError(t, err, "NewScalarFromSaturated(n - 1)")
+		require.EqualValues(t, 1, nMinusOne.Equal(s2), "NewScalarFromSaturated(n - 1)")
+
+		s2, err = NewScalarFromSaturated(*(*[4]uint64)(nSat[:4]))
+		require.Nil(t, s2, "NewScalarFromSaturated(n)")
+		require.ErrorIs(t, err, errNonCanonicalEncoding, "NewScalarFromSaturated(n)")
+	})
 	t.Run("Zero", func(t *testing.T) {
 		s := NewScalar().DebugMustRandomizeNonZero()
 		require.EqualValues(t, 0, s.IsZero(), "(rand).IsZero()")
@@ -120,6 +428,39 @@ func BenchmarkScalar(b *testing.B) {
 			s.Invert(s)
 		}
 	})
+	b.Run("Invert/Vartime", func(b *testing.B) {
+		s := NewScalar().DebugMustRandomizeNonZero()
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			s.InvertVartime(s)
+		}
+	})
+	for _, n := range []int{1, 2, 4, 8, 64} {
+		in := make([]*Scalar, n)
+		for i := range in {
+			in[i] = NewScalar().DebugMustRandomizeNonZero()
+		}
+		b.Run(fmt.Sprintf("Invert/Individual/%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				for _, s := range in {
+					s.Invert(s)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("Invert/Batch/%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				_ = BatchInvert(in, in)
+			}
+		})
+	}
 }
 
 func (s *Scalar) DebugMustRandomizeNonZero() *Scalar {
@@ -0,0 +1,114 @@
+// Copyright (c) 2023 Yawning Angel
+//
+// SPDX-License-Identifier: BSD-3-Clause
+
+package secec
+
+import (
+	csrand "crypto/rand"
+
+	"gitlab.com/yawning/secp256k1-voi"
+)
+
+// BatchVerify verifies the `(r, s)` signatures `sigs[i]` of `hashes[i]`,
+// using the PublicKeys `keys[i]`, using the verification procedure as
+// specified in SEC 1, Version 2.0, Section 4.1.4.  It returns true iff
+// every signature is valid, and the validity of each signature.  If
+// the lengths of `keys`, `hashes`, `sigs` and (non-nil) `recoveryIDs`
+// differ, BatchVerify returns false and nil.
+//
+// The batch is checked with a random linear combination, by picking
+// random scalars `a_i`, and checking that:
+//
+//	sum(a_i * u1_i) * G + sum(a_i * u2_i * Q_i) - sum(a_i * R_i) = O
+//
+// As ECDSA signatures only encode `r = x(R) mod n`, each `R_i` is
+// reconstructed from `r_i` and the recovery ID `recoveryIDs[i]`, which
+// MUST be in the range `[0,3]`.  Without the recovery ID, the sign of
+// `R_i` is ambiguous, and there is no efficient way to batch (trying
+// every candidate is exponential in the batch size).
+//
+// WARNING: If `recoveryIDs` is nil (eg: for plain ASN.1 signatures),
+// each signature is checked individually, and batching provides no
+// speedup.  If the batch check fails (including due to an incorrect
+// recovery ID), each signature is checked individually to determine
+// the per-signature result, and the recovery IDs do not influence
+// which signatures are considered valid.
+func BatchVerify(keys []*PublicKey, hashes [][]byte, sigs [][2]*secp256k1.Scalar, recoveryIDs []byte) (bool, []bool) {
+	n := len(keys)
+	if n != len(hashes) || n != len(sigs) || (recoveryIDs != nil && n != len(recoveryIDs)) {
+		return false, nil
+	}
+
+	if n > 1 && recoveryIDs != nil && batchVerify(keys, hashes, sigs, recoveryIDs) {
+		results := make([]bool, n)
+		for i := range results {
+			results[i] = true
+		}
+		return true, results
+	}
+
+	allOk := true
+	results := make([]bool, n)
+	for i, k := range keys {
+		r, s := sigs[i][0], sigs[i][1]
+		results[i] = k != nil && r != nil && s != nil && k.VerifyRaw(hashes[i], r, s)
+		allOk = allOk && results[i]
+	}
+
+	return allOk, results
+}
+
+func batchVerify(keys []*PublicKey, hashes [][]byte, sigs [][2]*secp256k1.Scalar, recoveryIDs []byte) bool {
+	n := len(keys)
+
+	sInvs := make([]*secp256k1.Scalar, 0, n)
+	for i, k := range keys {
+		r, s := sigs[i][0], sigs[i][1]
+		if k == nil || r == nil || s == nil || r.IsZero() != 0 || s.IsZero() != 0 {
+			return false
+		}
+		sInvs = append(sInvs, secp256k1.NewScalarFrom(s))
+	}
+	if err := secp256k1.BatchInvert(sInvs, sInvs); err != nil {
+		return false
+	}
+
+	scalars := make([]*secp256k1.Scalar, 0, 2*n+1)
+	points := make([]*secp256k1.Point, 0, 2*n+1)
+
+	gCoeff := secp256k1.NewScalar()
+	scalars = append(scalars, gCoeff)
+	points = append(points, secp256k1.NewGeneratorPoint())
+
+	for i, k := range keys {
+		r, sInv := sigs[i][0], sInvs[i]
+
+		e, err := hashToScalar(hashes[i])
+		if err != nil {
+			return false
+		}
+
+		R, err := secp256k1.RecoverPoint(r, recoveryIDs[i])
+		if err != nil {
+			return false
+		}
+
+		a, err := sampleRandomScalar(csrand.Reader)
+		if err != nil {
+			return false
+		}
+
+		// u1 = e(s^−1), u2 = r(s^-1)
+		u1 := e.Multiply(e, sInv)
+		u2 := sInv.Multiply(r, sInv)
+
+		gCoeff.Add(gCoeff, u1.Multiply(u1, a))
+		scalars = append(scalars, u2.Multiply(u2, a), a.Negate(a))
+		points = append(points, k.point, R)
+	}
+
+	sum := secp256k1.NewIdentityPoint().MultiScalarMultVartime(scalars, points)
+
+	return sum.IsIdentity() == 1
+}
@@ -0,0 +1,302 @@
+// Copyright (c) 2023 Yawning Angel
+//
+// SPDX-License-Identifier: BSD-3-Clause
+
+package bitcoin
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"math"
+
+	"golang.org/x/crypto/ripemd160" //nolint:staticcheck
+
+	"gitlab.com/yawning/secp256k1-voi/secec"
+	"gitlab.com/yawning/secp256k1-voi/secec/ethereum"
+)
+
+const (
+	// Hash160Size is the size of a HASH160 digest (and P2PKH address
+	// payload) in bytes.
+	Hash160Size = 20
+
+	// MessageSignatureSize is the size of a BIP-0137 "signmessage"
+	// signature in bytes.
+	MessageSignatureSize = 65
+
+	// TaprootOutputKeySize is the size of a P2TR address payload (the
+	// x-only Taproot output key) in bytes.
+	TaprootOutputKeySize = 32
+
+	headerP2PKHUncompressed = 27
+	headerP2PKHCompressed   = 31
+	headerP2PKHMax          = 34
+	headerSegwitBech32      = 39
+	headerSegwitMax         = 42
+
+	witnessVersionP2WPKH = 0
+	witnessVersionP2TR   = 1
+
+	maxRecoveryID = 3
+
+	signedMessageMagic = "Bitcoin Signed Message:\n"
+)
+
+var (
+	errNoCandidates          = errors.New("secp256k1/secec/bitcoin: no candidate public keys")
+	errInvalidWitnessProgram = errors.New("secp256k1/secec/bitcoin: invalid witness program")
+	errInvalidMessageSig     = errors.New("secp256k1/secec/bitcoin: invalid message signature")
+)
+
+// VerifyRecoverP2PKH recovers the public key from the BIP-0137
+// `[Header | R | S]` recoverable signature `sig` of `hash`, and
+// returns true iff the P2PKH address payload (HASH160) derived from
+// the recovered public key is `expected`.
+//
+// Note: Only the P2PKH header values (`[27,34]`) are accepted, and
+// the header determines if the compressed or uncompressed public key
+// encoding is hashed.  Signatures where `s > n / 2` are rejected.
+func VerifyRecoverP2PKH(expected [Hash160Size]byte, hash [32]byte, sig []byte) bool {
+	if len(sig) != MessageSignatureSize {
+		return false
+	}
+
+	header := sig[0]
+	if header < headerP2PKHUncompressed || header > headerP2PKHMax {
+		return false
+	}
+	isCompressed := header >= headerP2PKHCompressed
+	recoveryID := (header - headerP2PKHUncompressed) & 3
+
+	r, s, err := secec.ParseCompactSignature(sig[1:])
+	if err != nil {
+		return false
+	}
+	if s.IsGreaterThanHalfN() != 0 {
+		return false
+	}
+
+	pk, err := secec.RecoverPublicKey(hash[:], r, s, recoveryID)
+	if err != nil {
+		return false
+	}
+
+	pkBytes := pk.Bytes()
+	if isCompressed {
+		pkBytes = pk.CompressedBytes()
+	}
+	h := hash160(pkBytes)
+
+	return subtle.ConstantTimeCompare(expected[:], h[:]) == 1
+}
+
+// VerifyRecoverWitnessAddress recovers the public key from the BIP-0137
+// `[Header | R | S]` recoverable signature `sig` of `hash`, and returns
+// true iff the segwit address derived from the recovered public key is
+// the Bech32(m) encoded address `expectedAddr` for the network `net`.
+// The derivation is selected by the witness version of `expectedAddr`,
+// with version 0 being P2WPKH (the HASH160 of the compressed public
+// key), and version 1 being P2TR (the BIP-0086 key-path only Taproot
+// output key).
+//
+// Note: The header MUST match the address type, with P2WPKH requiring
+// the BIP-0137 segwit Bech32 header values (`[39,42]`).  As BIP-0137
+// predates Taproot, P2TR requires the compressed P2PKH header values
+// (`[31,34]`).  Signatures where `s > n / 2` are rejected.
+func VerifyRecoverWitnessAddress(expectedAddr string, hash, sig []byte, net Network) (bool, error) {
+	params, err := net.params()
+	if err != nil {
+		return false, err
+	}
+
+	witnessVersion, witnessProgram, err := decodeSegwitAddress(params.hrp, expectedAddr)
+	if err != nil {
+		return false, err
+	}
+
+	var headerMin, headerMax byte
+	switch {
+	case witnessVersion == witnessVersionP2WPKH && len(witnessProgram) == Hash160Size:
+		headerMin, headerMax = headerSegwitBech32, headerSegwitMax
+	case witnessVersion == witnessVersionP2TR && len(witnessProgram) == TaprootOutputKeySize:
+		headerMin, headerMax = headerP2PKHCompressed, headerP2PKHMax
+	default:
+		return false, errInvalidWitnessProgram
+	}
+
+	if len(sig) != MessageSignatureSize {
+		return false, errInvalidMessageSig
+	}
+	header := sig[0]
+	if header < headerMin || header > headerMax {
+		return false, errInvalidMessageSig
+	}
+	recoveryID := header - headerMin
+
+	r, s, err := secec.ParseCompactSignature(sig[1:])
+	if err != nil {
+		return false, err
+	}
+	if s.IsGreaterThanHalfN() != 0 {
+		return false, nil
+	}
+
+	pk, err := secec.RecoverPublicKey(hash, r, s, recoveryID)
+	if err != nil {
+		return false, nil //nolint:nilerr
+	}
+
+	var derived []byte
+	switch witnessVersion {
+	case witnessVersionP2WPKH:
+		h := hash160(pk.CompressedBytes())
+		derived = h[:]
+	case witnessVersionP2TR:
+		outputKey, _, err := NewSchnorrPublicKeyFromECDSA(pk).TweakKeyPathOnly()
+		if err != nil {
+			return false, err
+		}
+		derived = outputKey.Bytes()
+	}
+
+	return subtle.ConstantTimeCompare(witnessProgram, derived) == 1, nil
+}
+
+// RecoverCandidateAddresses recovers every candidate public key from
+// the signature `sig` of the BIP-0137 "signmessage" message `msg`
+// (SEC 1, Version 2.0, Section 4.1.6), and returns the P2PKH addresses
+// for the network `net`, of the compressed and uncompressed encoding
+// of each candidate.  `sig` may either be a `[R | S]` compact
+// signature, or a BIP-0137 `[Header | R | S]` recoverable signature,
+// in which case the header is ignored.
+//
+// Note: This is intended for forensic use, and `s` in the range
+// `[1, n)` is accepted.
+func RecoverCandidateAddresses(msg, sig []byte, net Network) ([]string, error) {
+	params, err := net.params()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sig) == MessageSignatureSize {
+		sig = sig[1:]
+	}
+
+	r, s, err := secec.ParseCompactSignature(sig)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := signedMessageHash(msg)
+
+	var candidates []string
+	for recoveryID := byte(0); recoveryID <= maxRecoveryID; recoveryID++ {
+		pk, err := secec.RecoverPublicKey(hash[:], r, s, recoveryID)
+		if err != nil {
+			continue
+		}
+
+		hCompressed, hUncompressed := hash160(pk.CompressedBytes()), hash160(pk.Bytes())
+		candidates = append(
+			candidates,
+			base58CheckEncode(params.p2pkhVersion, hCompressed[:]),
+			base58CheckEncode(params.p2pkhVersion, hUncompressed[:]),
+		)
+	}
+	if len(candidates) == 0 {
+		return nil, errNoCandidates
+	}
+
+	return candidates, nil
+}
+
+// signedMessageHash returns the BIP-0137 "signmessage" digest of `msg`,
+// which is the double-SHA256 of the length prefixed magic string and
+// the length prefixed message.
+func signedMessageHash(msg []byte) [32]byte {
+	h := NewDoubleSHA256()
+	_, _ = h.Write(appendCompactSize([]byte{}, uint64(len(signedMessageMagic))))
+	_, _ = h.Write([]byte(signedMessageMagic))
+	_, _ = h.Write(appendCompactSize([]byte{}, uint64(len(msg))))
+	_, _ = h.Write(msg)
+
+	var digest [32]byte
+	h.Sum(digest[:0])
+	return digest
+}
+
+// appendCompactSize appends the bitcoin variable length integer
+// encoding of `v` to `b`.
+func appendCompactSize(b []byte, v uint64) []byte {
+	switch {
+	case v < 0xfd:
+		return append(b, byte(v))
+	case v <= math.MaxUint16:
+		return binary.LittleEndian.AppendUint16(append(b, 0xfd), uint16(v))
+	case v <= math.MaxUint32:
+		return binary.LittleEndian.AppendUint32(append(b, 0xfe), uint32(v))
+	default:
+		return binary.LittleEndian.AppendUint64(append(b, 0xff), v)
+	}
+}
+
+// AddressSet is the set of common addresses derived from a single
+// public key.
+type AddressSet struct {
+	// P2PKH is the Base58Check encoded HASH160 of the compressed
+	// public key.
+	P2PKH string
+
+	// P2WPKH is the Bech32 encoded version 0 witness program, which
+	// is the HASH160 of the compressed public key.
+	P2WPKH string
+
+	// P2TR is the Bech32m encoded version 1 witness program, which is
+	// the BIP-0086 key-path only Taproot output key.
+	P2TR string
+
+	// Ethereum is the EIP-55 mixed-case checksum encoded ethereum
+	// address.
+	Ethereum string
+}
+
+// Addresses derives the P2PKH, P2WPKH, P2TR and ethereum addresses
+// for the public key `k`, with the bitcoin addresses encoded for the
+// network `net`.
+//
+// Note: This is not a method on [secec.PublicKey], as that would
+// introduce an import cycle.
+func Addresses(k *secec.PublicKey, net Network) (*AddressSet, error) {
+	params, err := net.params()
+	if err != nil {
+		return nil, err
+	}
+
+	outputKey, _, err := NewSchnorrPublicKeyFromECDSA(k).TweakKeyPathOnly()
+	if err != nil {
+		return nil, err
+	}
+
+	h := hash160(k.CompressedBytes())
+
+	return &AddressSet{
+		P2PKH:    base58CheckEncode(params.p2pkhVersion, h[:]),
+		P2WPKH:   encodeSegwitAddress(params.hrp, witnessVersionP2WPKH, h[:]),
+		P2TR:     encodeSegwitAddress(params.hrp, witnessVersionP2TR, outputKey.Bytes()),
+		Ethereum: ethereum.Address(k),
+	}, nil
+}
+
+// hash160 returns `RIPEMD160(SHA256(b))`.
+func hash160(b []byte) [Hash160Size]byte {
+	innerDigest := sha256.Sum256(b)
+
+	h := ripemd160.New()
+	_, _ = h.Write(innerDigest[:])
+
+	var digest [Hash160Size]byte
+	copy(digest[:], h.Sum(nil))
+	return digest
+}
@@ -0,0 +1,294 @@
+// Copyright (c) 2023 Yawning Angel
+//
+// SPDX-License-Identifier: BSD-3-Clause
+
+package bitcoin
+
+import (
+	"crypto/sha256"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gitlab.com/yawning/secp256k1-voi/internal/helpers"
+	"gitlab.com/yawning/secp256k1-voi/secec"
+)
+
+func TestAddress(t *testing.T) {
+	t.Run("Hash160/KAT", func(t *testing.T) {
+		// The private key `1` is the textbook example.
+		priv, err := secec.NewPrivateKey(helpers.MustBytesFromHex("0000000000000000000000000000000000000000000000000000000000000001"))
+		require.NoError(t, err, "NewPrivateKey")
+
+		pub := priv.PublicKey()
+
+		h := hash160(pub.CompressedBytes())
+		require.EqualValues(t, helpers.MustBytesFromHex("751e76e8199196d454941c45d1b3a323f1433bd6"), h[:], "hash160(compressed)")
+
+		h = hash160(pub.Bytes())
+		require.EqualValues(t, helpers.MustBytesFromHex("91b24bf9f5288532960ac687abb035127b1d28a5"), h[:], "hash160(uncompressed)")
+	})
+	t.Run("SignedMessageHash", func(t *testing.T) {
+		msg := []byte("Hello, world!")
+
+		h := sha256.New()
+		_, _ = h.Write(append([]byte{24}, "Bitcoin Signed Message:\n"...))
+		_, _ = h.Write(append([]byte{byte(len(msg))}, msg...))
+		innerDigest := h.Sum(nil)
+		expected := sha256.Sum256(innerDigest)
+
+		require.Equal(t, expected, signedMessageHash(msg), "signedMessageHash")
+
+		for _, vec := range []struct {
+			v        uint64
+			expected string
+		}{
+			{0xfc, "fc"},
+			{0xfd, "fdfd00"},
+			{0xffff, "fdffff"},
+			{0x10000, "fe00000100"},
+			{0x100000000, "ff0000000001000000"},
+		} {
+			require.Equal(t, helpers.MustBytesFromHex(vec.expected), appendCompactSize(nil, vec.v), "appendCompactSize(%x)", vec.v)
+		}
+	})
+	t.Run("RecoverCandidateAddresses", func(t *testing.T) {
+		priv, err := secec.GenerateKey()
+		require.NoError(t, err, "GenerateKey")
+
+		pub := priv.PublicKey()
+		hCompressed := hash160(pub.CompressedBytes())
+		hUncompressed := hash160(pub.Bytes())
+
+		msgHash := signedMessageHash([]byte(testMessage))
+
+		r, s, v, err := priv.SignRaw(nil, msgHash[:])
+		require.NoError(t, err, "SignRaw")
+
+		compactSig := secec.BuildCompactSignature(r, s)
+		sig := append([]byte{headerP2PKHCompressed + v}, compactSig...)
+
+		for _, net := range []Network{Mainnet, Testnet} {
+			params, err := net.params()
+			require.NoError(t, err, "params")
+
+			for _, tc := range [][]byte{compactSig, sig} {
+				candidates, err := RecoverCandidateAddresses([]byte(testMessage), tc, net)
+				require.NoError(t, err, "RecoverCandidateAddresses")
+				require.GreaterOrEqual(t, len(candidates), 4, "RecoverCandidateAddresses - candidates")
+				require.Contains(t, candidates, base58CheckEncode(params.p2pkhVersion, hCompressed[:]), "RecoverCandidateAddresses - compressed")
+				require.Contains(t, candidates, base58CheckEncode(params.p2pkhVersion, hUncompressed[:]), "RecoverCandidateAddresses - uncompressed")
+			}
+		}
+
+		as, err := Addresses(pub, Mainnet)
+		require.NoError(t, err, "Addresses")
+		candidates, err := RecoverCandidateAddresses([]byte(testMessage), sig, Mainnet)
+		require.NoError(t, err, "RecoverCandidateAddresses")
+		require.Contains(t, candidates, as.P2PKH, "RecoverCandidateAddresses - Addresses")
+
+		_, err = RecoverCandidateAddresses([]byte(testMessage), compactSig[1:], Mainnet)
+		require.Error(t, err, "RecoverCandidateAddresses - truncated")
+		_, err = RecoverCandidateAddresses([]byte(testMessage), compactSig, Network(69))
+		require.ErrorIs(t, err, errInvalidNetwork, "RecoverCandidateAddresses - bad network")
+	})
+	t.Run("VerifyRecoverWitnessAddress", func(t *testing.T) {
+		priv, err := secec.GenerateKey()
+		require.NoError(t, err, "GenerateKey")
+
+		as, err := Addresses(priv.PublicKey(), Testnet)
+		require.NoError(t, err, "Addresses")
+
+		msgHash := signedMessageHash([]byte(testMessage))
+
+		r, s, v, err := priv.SignRaw(nil, msgHash[:])
+		require.NoError(t, err, "SignRaw")
+
+		sig := append([]byte{headerSegwitBech32 + v}, secec.BuildCompactSignature(r, s)...)
+
+		ok, err := VerifyRecoverWitnessAddress(as.P2WPKH, msgHash[:], sig, Testnet)
+		require.NoError(t, err, "VerifyRecoverWitnessAddress - P2WPKH")
+		require.True(t, ok, "VerifyRecoverWitnessAddress - P2WPKH")
+
+		// Upper case addresses are valid.
+		ok, err = VerifyRecoverWitnessAddress(strings.ToUpper(as.P2WPKH), msgHash[:], sig, Testnet)
+		require.NoError(t, err, "VerifyRecoverWitnessAddress - P2WPKH, upper case")
+		require.True(t, ok, "VerifyRecoverWitnessAddress - P2WPKH, upper case")
+
+		// The header must match the address type.
+		_, err = VerifyRecoverWitnessAddress(as.P2TR, msgHash[:], sig, Testnet)
+		require.ErrorIs(t, err, errInvalidMessageSig, "VerifyRecoverWitnessAddress - P2TR, segwit header")
+
+		sig[0] = headerP2PKHCompressed + v
+		ok, err = VerifyRecoverWitnessAddress(as.P2TR, msgHash[:], sig, Testnet)
+		require.NoError(t, err, "VerifyRecoverWitnessAddress - P2TR")
+		require.True(t, ok, "VerifyRecoverWitnessAddress - P2TR")
+
+		_, err = VerifyRecoverWitnessAddress(as.P2WPKH, msgHash[:], sig, Testnet)
+		require.ErrorIs(t, err, errInvalidMessageSig, "VerifyRecoverWitnessAddress - P2WPKH, P2PKH header")
+
+		sig[0] = headerP2PKHUncompressed + v
+		_, err = VerifyRecoverWitnessAddress(as.P2TR, msgHash[:], sig, Testnet)
+		require.ErrorIs(t, err, errInvalidMessageSig, "VerifyRecoverWitnessAddress - P2TR, uncompressed header")
+
+		// Mismatched address.
+		other, err := secec.GenerateKey()
+		require.NoError(t, err, "GenerateKey - other")
+		otherAs, err := Addresses(other.PublicKey(), Testnet)
+		require.NoError(t, err, "Addresses - other")
+
+		sig[0] = headerSegwitBech32 + v
+		ok, err = VerifyRecoverWitnessAddress(otherAs.P2WPKH, msgHash[:], sig, Testnet)
+		require.NoError(t, err, "VerifyRecoverWitnessAddress - wrong address")
+		require.False(t, ok, "VerifyRecoverWitnessAddress - wrong address")
+
+		// Wrong network, and undecodable addresses.
+		_, err = VerifyRecoverWitnessAddress(as.P2WPKH, msgHash[:], sig, Mainnet)
+		require.ErrorIs(t, err, errInvalidBech32, "VerifyRecoverWitnessAddress - wrong network")
+		_, err = VerifyRecoverWitnessAddress(as.P2PKH, msgHash[:], sig, Testnet)
+		require.ErrorIs(t, err, errInvalidBech32, "VerifyRecoverWitnessAddress - P2PKH address")
+		_, err = VerifyRecoverWitnessAddress(as.P2WPKH, msgHash[:], sig, Network(69))
+		require.ErrorIs(t, err, errInvalidNetwork, "VerifyRecoverWitnessAddress - bad network")
+
+		// Witness programs that can not be derived from a public key.
+		p2wsh := encodeSegwitAddress("tb", 0, make([]byte, 32))
+		_, err = VerifyRecoverWitnessAddress(p2wsh, msgHash[:], sig, Testnet)
+		require.ErrorIs(t, err, errInvalidWitnessProgram, "VerifyRecoverWitnessAddress - P2WSH")
+		v2 := encodeSegwitAddress("tb", 2, make([]byte, 32))
+		_, err = VerifyRecoverWitnessAddress(v2, msgHash[:], sig, Testnet)
+		require.ErrorIs(t, err, errInvalidWitnessProgram, "VerifyRecoverWitnessAddress - version 2")
+
+		_, err = VerifyRecoverWitnessAddress(as.P2WPKH, msgHash[:], sig[:64], Testnet)
+		require.ErrorIs(t, err, errInvalidMessageSig, "VerifyRecoverWitnessAddress - truncated")
+	})
+	t.Run("Addresses", func(t *testing.T) {
+		// The private key `1` is the textbook example.
+		priv, err := secec.NewPrivateKey(helpers.MustBytesFromHex("0000000000000000000000000000000000000000000000000000000000000001"))
+		require.NoError(t, err, "NewPrivateKey")
+
+		pub := priv.PublicKey()
+
+		as, err := Addresses(pub, Mainnet)
+		require.NoError(t, err, "Addresses")
+		require.Equal(t, "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH", as.P2PKH, "P2PKH")
+		require.Equal(t, "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", as.P2WPKH, "P2WPKH")
+		require.Equal(t, "0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf", as.Ethereum, "Ethereum")
+
+		outputKey, _, err := NewSchnorrPublicKeyFromECDSA(pub).TweakKeyPathOnly()
+		require.NoError(t, err, "TweakKeyPathOnly")
+		require.Equal(t, encodeSegwitAddress("bc", witnessVersionP2TR, outputKey.Bytes()), as.P2TR, "P2TR")
+
+		as, err = Addresses(pub, Testnet)
+		require.NoError(t, err, "Addresses - Testnet")
+		require.Equal(t, "mrCDrCybB6J1vRfbwM5hemdJz73FwDBC8r", as.P2PKH, "P2PKH - Testnet")
+		require.Equal(t, "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx", as.P2WPKH, "P2WPKH - Testnet")
+
+		as, err = Addresses(pub, Regtest)
+		require.NoError(t, err, "Addresses - Regtest")
+		require.Equal(t, "mrCDrCybB6J1vRfbwM5hemdJz73FwDBC8r", as.P2PKH, "P2PKH - Regtest")
+		require.True(t, strings.HasPrefix(as.P2WPKH, "bcrt1q"), "P2WPKH - Regtest")
+
+		_, err = Addresses(pub, Network(69))
+		require.ErrorIs(t, err, errInvalidNetwork, "Addresses - bad network")
+
+		// BIP-0086 test vector (m/86'/0'/0'/0/0).
+		pub, err = secec.NewPublicKey(helpers.MustBytesFromHex("02cc8a4bc64d897bddc5fbc2f670f7a8ba0b386779106cf1223c6fc5d7cd6fc115"))
+		require.NoError(t, err, "NewPublicKey")
+
+		as, err = Addresses(pub, Mainnet)
+		require.NoError(t, err, "Addresses - BIP-0086")
+		require.Equal(t, "bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr", as.P2TR, "P2TR - BIP-0086")
+	})
+	t.Run("Bech32", func(t *testing.T) {
+		// BIP-0173 and BIP-0350 test vectors.
+		for i, vec := range []struct {
+			hrp     string
+			addr    string
+			version byte
+			program string
+		}{
+			{"bc", "BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4", 0, "751e76e8199196d454941c45d1b3a323f1433bd6"},
+			{"tb", "tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sl5k7", 0, "1863143c14c5166804bd19203356da136c985678cd4d27a1b8c6329604903262"},
+			{"bc", "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0", 1, "79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"},
+			{"bc", "bc1zw508d6qejxtdg4y5r3zarvaryvaxxpcs", 2, "751e76e8199196d454941c45d1b3a323"},
+		} {
+			version, program, err := decodeSegwitAddress(vec.hrp, vec.addr)
+			require.NoError(t, err, "[%d]: decodeSegwitAddress", i)
+			require.Equal(t, vec.version, version, "[%d]: version", i)
+			require.Equal(t, helpers.MustBytesFromHex(vec.program), program, "[%d]: program", i)
+
+			addr := encodeSegwitAddress(vec.hrp, version, program)
+			require.Equal(t, strings.ToLower(vec.addr), addr, "[%d]: encodeSegwitAddress", i)
+		}
+
+		for i, vec := range []struct {
+			hrp  string
+			addr string
+		}{
+			{"tb", "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4"}, // Wrong HRP.
+			{"bc", "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t5"}, // Bad checksum.
+			{"bc", "bc1QW508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4"}, // Mixed case.
+			{"bc", "bc1gmk9yu"}, // Empty data.
+			{"bc", encodeWithConst("bc", 1, make([]byte, 32), bech32Const)},   // Bech32 for v1.
+			{"bc", encodeWithConst("bc", 0, make([]byte, 20), bech32mConst)},  // Bech32m for v0.
+			{"bc", encodeWithConst("bc", 0, make([]byte, 16), bech32Const)},   // Invalid v0 length.
+			{"bc", encodeWithConst("bc", 1, make([]byte, 41), bech32mConst)},  // Invalid program length.
+			{"bc", encodeWithConst("bc", 17, make([]byte, 32), bech32mConst)}, // Invalid version.
+		} {
+			_, _, err := decodeSegwitAddress(vec.hrp, vec.addr)
+			require.ErrorIs(t, err, errInvalidBech32, "[%d]: decodeSegwitAddress - invalid", i)
+		}
+	})
+	t.Run("Base58Check", func(t *testing.T) {
+		// Leading zero bytes are encoded as '1'.
+		require.Equal(t, "1111111111111111111114oLvT2", base58CheckEncode(0x00, make([]byte, Hash160Size)), "base58CheckEncode - zeros")
+	})
+	t.Run("VerifyRecoverP2PKH", func(t *testing.T) {
+		priv, err := secec.GenerateKey()
+		require.NoError(t, err, "GenerateKey")
+
+		pub := priv.PublicKey()
+		hCompressed := hash160(pub.CompressedBytes())
+		hUncompressed := hash160(pub.Bytes())
+
+		msgHash := sha256.Sum256([]byte(testMessage))
+
+		r, s, v, err := priv.SignRaw(nil, msgHash[:])
+		require.NoError(t, err, "SignRaw")
+
+		sig := make([]byte, 0, MessageSignatureSize)
+		sig = append(sig, headerP2PKHCompressed+v)
+		sig = append(sig, secec.BuildCompactSignature(r, s)...)
+
+		ok := VerifyRecoverP2PKH(hCompressed, msgHash, sig)
+		require.True(t, ok, "VerifyRecoverP2PKH - compressed")
+		ok = VerifyRecoverP2PKH(hUncompressed, msgHash, sig)
+		require.False(t, ok, "VerifyRecoverP2PKH - compressed, wrong address")
+
+		sig[0] = headerP2PKHUncompressed + v
+		ok = VerifyRecoverP2PKH(hUncompressed, msgHash, sig)
+		require.True(t, ok, "VerifyRecoverP2PKH - uncompressed")
+		ok = VerifyRecoverP2PKH(hCompressed, msgHash, sig)
+		require.False(t, ok, "VerifyRecoverP2PKH - uncompressed, wrong address")
+
+		sig[0] = headerP2PKHMax + 1
+		ok = VerifyRecoverP2PKH(hUncompressed, msgHash, sig)
+		require.False(t, ok, "VerifyRecoverP2PKH - bad header")
+
+		ok = VerifyRecoverP2PKH(hUncompressed, msgHash, sig[:64])
+		require.False(t, ok, "VerifyRecoverP2PKH - truncated")
+	})
+}
+
+func encodeWithConst(hrp string, version byte, program []byte, constant uint32) string {
+	data := convertBits([]byte{version}, program, 8, 5, true)
+	checksum := bech32CreateChecksum(hrp, data, constant)
+
+	var sb strings.Builder
+	sb.WriteString(hrp + "1")
+	for _, v := range append(data, checksum[:]...) {
+		sb.WriteByte(bech32Charset[v])
+	}
+	return sb.String()
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2023 Yawning Angel
+//
+// SPDX-License-Identifier: BSD-3-Clause
+
+package bitcoin
+
+import "crypto/sha256"
+
+const (
+	base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+	base58CheckSize = 4
+)
+
+// base58CheckEncode returns the Base58Check encoding of `version || payload`.
+func base58CheckEncode(version byte, payload []byte) string {
+	b := make([]byte, 0, 1+len(payload)+base58CheckSize)
+	b = append(b, version)
+	b = append(b, payload...)
+	checksum := base58Checksum(b)
+	b = append(b, checksum[:]...)
+
+	return base58Encode(b)
+}
+
+func base58Checksum(b []byte) [base58CheckSize]byte {
+	innerDigest := sha256.Sum256(b)
+	digest := sha256.Sum256(innerDigest[:])
+
+	var checksum [base58CheckSize]byte
+	copy(checksum[:], digest[:])
+	return checksum
+}
+
+func base58Encode(b []byte) string {
+	// Each leading 0x00 byte is encoded as a '1'.
+	var zeros int
+	for zeros < len(b) && b[zeros] == 0 {
+		zeros++
+	}
+
+	// log(256) / log(58) ~= 1.37, so this is always sufficient.
+	digits := make([]byte, 0, len(b)*138/100+1)
+	for _, v := range b[zeros:] {
+		carry := int(v)
+		for i := range digits {
+			carry += int(digits[i]) << 8
+			digits[i] = byte(carry % 58)
+			carry /= 58
+		}
+		for carry > 0 {
+			digits = append(digits, byte(carry%58))
+			carry /= 58
+		}
+	}
+
+	out := make([]byte, 0, zeros+len(digits))
+	for i := 0; i < zeros; i++ {
+		out = append(out, base58Alphabet[0])
+	}
+	for i := len(digits) - 1; i >= 0; i-- {
+		out = append(out, base58Alphabet[digits[i]])
+	}
+
+	return string(out)
+}
@@ -0,0 +1,188 @@
+// Copyright (c) 2023 Yawning Angel
+//
+// SPDX-License-Identifier: BSD-3-Clause
+
+package bitcoin
+
+import (
+	"errors"
+	"strings"
+)
+
+// See:
+// - https://github.com/bitcoin/bips/blob/master/bip-0173.mediawiki
+// - https://github.com/bitcoin/bips/blob/master/bip-0350.mediawiki
+
+const (
+	bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+	bech32Const  = 1
+	bech32mConst = 0x2bc830a3
+
+	bech32ChecksumSize = 6
+	bech32MaxLength    = 90
+
+	maxWitnessVersion        = 16
+	minWitnessProgramSize    = 2
+	maxWitnessProgramSize    = 40
+	witnessV0ProgramSizeP2SH = 32
+)
+
+var errInvalidBech32 = errors.New("secp256k1/secec/bitcoin: invalid Bech32(m) address")
+
+// encodeSegwitAddress returns the Bech32 (version 0) or Bech32m
+// (version 1+) encoded segwit address for the witness program
+// `program`, with the human readable part `hrp`.
+func encodeSegwitAddress(hrp string, version byte, program []byte) string {
+	data := make([]byte, 0, 1+(len(program)*8+4)/5)
+	data = append(data, version)
+	data = convertBits(data, program, 8, 5, true)
+
+	checksum := bech32CreateChecksum(hrp, data, bech32ConstFor(version))
+
+	var sb strings.Builder
+	sb.Grow(len(hrp) + 1 + len(data) + len(checksum))
+	sb.WriteString(hrp)
+	sb.WriteByte('1')
+	for _, v := range data {
+		sb.WriteByte(bech32Charset[v])
+	}
+	for _, v := range checksum {
+		sb.WriteByte(bech32Charset[v])
+	}
+
+	return sb.String()
+}
+
+// decodeSegwitAddress decodes the Bech32(m) encoded segwit address
+// `addr`, checks that the human readable part is `hrp`, and returns
+// the witness version and program.
+func decodeSegwitAddress(hrp, addr string) (byte, []byte, error) {
+	if len(addr) > bech32MaxLength {
+		return 0, nil, errInvalidBech32
+	}
+
+	// Mixed case is forbidden, but either case is allowed.
+	lowerAddr := strings.ToLower(addr)
+	if lowerAddr != addr && strings.ToUpper(addr) != addr {
+		return 0, nil, errInvalidBech32
+	}
+	addr = lowerAddr
+
+	sep := strings.LastIndexByte(addr, '1')
+	if sep < 1 || sep+1+bech32ChecksumSize > len(addr) {
+		return 0, nil, errInvalidBech32
+	}
+	if addr[:sep] != hrp {
+		return 0, nil, errInvalidBech32
+	}
+
+	data := make([]byte, 0, len(addr)-sep-1)
+	for i := sep + 1; i < len(addr); i++ {
+		v := strings.IndexByte(bech32Charset, addr[i])
+		if v < 0 {
+			return 0, nil, errInvalidBech32
+		}
+		data = append(data, byte(v))
+	}
+	if len(data) == bech32ChecksumSize {
+		// No witness version.
+		return 0, nil, errInvalidBech32
+	}
+
+	version := data[0]
+	if version > maxWitnessVersion {
+		return 0, nil, errInvalidBech32
+	}
+	if bech32Polymod(hrp, data) != bech32ConstFor(version) {
+		return 0, nil, errInvalidBech32
+	}
+
+	program := convertBits(nil, data[1:len(data)-bech32ChecksumSize], 5, 8, false)
+	if program == nil || len(program) < minWitnessProgramSize || len(program) > maxWitnessProgramSize {
+		return 0, nil, errInvalidBech32
+	}
+	if version == 0 && len(program) != Hash160Size && len(program) != witnessV0ProgramSizeP2SH {
+		return 0, nil, errInvalidBech32
+	}
+
+	return version, program, nil
+}
+
+func bech32ConstFor(version byte) uint32 {
+	if version == 0 {
+		return bech32Const
+	}
+	return bech32mConst
+}
+
+func bech32Polymod(hrp string, data []byte) uint32 {
+	gen := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+	chk := uint32(1)
+	update := func(v byte) {
+		b := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := range gen {
+			if (b>>i)&1 == 1 {
+				chk ^= gen[i]
+			}
+		}
+	}
+
+	for i := 0; i < len(hrp); i++ {
+		update(hrp[i] >> 5)
+	}
+	update(0)
+	for i := 0; i < len(hrp); i++ {
+		update(hrp[i] & 31)
+	}
+	for _, v := range data {
+		update(v)
+	}
+
+	return chk
+}
+
+func bech32CreateChecksum(hrp string, data []byte, constant uint32) [bech32ChecksumSize]byte {
+	values := make([]byte, 0, len(data)+bech32ChecksumSize)
+	values = append(values, data...)
+	values = append(values, make([]byte, bech32ChecksumSize)...)
+	polymod := bech32Polymod(hrp, values) ^ constant
+
+	var checksum [bech32ChecksumSize]byte
+	for i := range checksum {
+		checksum[i] = byte(polymod>>(5*(5-i))) & 31
+	}
+	return checksum
+}
+
+// convertBits regroups `data` from `fromBits` to `toBits` groups,
+// appending to `dst`.  It returns nil on invalid padding when `pad`
+// is false.
+func convertBits(dst, data []byte, fromBits, toBits uint, pad bool) []byte {
+	var (
+		acc  uint32
+		bits uint
+	)
+	maxV := uint32(1)<<toBits - 1
+	for _, v := range data {
+		acc = acc<<fromBits | uint32(v)
+		bits += fromBits
+		for bits >= toBits {
+			bits -= toBits
+			dst = append(dst, byte((acc>>bits)&maxV))
+		}
+	}
+
+	switch {
+	case pad:
+		if bits > 0 {
+			dst = append(dst, byte((acc<<(toBits-bits))&maxV))
+		}
+	case bits >= fromBits || (acc<<(toBits-bits))&maxV != 0:
+		return nil
+	}
+
+	return dst
+}
@@ -0,0 +1,39 @@
+// Copyright (c) 2023 Yawning Angel
+//
+// SPDX-License-Identifier: BSD-3-Clause
+
+package bitcoin
+
+import (
+	"crypto"
+
+	"gitlab.com/yawning/secp256k1-voi/secec"
+)
+
+// VerifyDual verifies both the ASN.1 encoded ECDSA signature `ecdsaSig`
+// of `msg` hashed with `h`, and the BIP-0340 Schnorr signature
+// `schnorrSig` of `msg`, using the PublicKey `pub`.  Its return value
+// records whether both signatures are valid.
+//
+// Note: The Schnorr signature is verified against the x-only public
+// key derived from `pub` (ie: with the Y-coordinate fixed up to be
+// even), so a signature by either `d` or `-d` is accepted, as is
+// required by BIP-0340.
+func VerifyDual(pub *secec.PublicKey, msg, ecdsaSig, schnorrSig []byte, h crypto.Hash) bool {
+	if !h.Available() {
+		return false
+	}
+
+	hh := h.New()
+	_, _ = hh.Write(msg)
+	digest := hh.Sum(nil)
+
+	// Evaluate both regardless of the outcome of the first check.
+	ecdsaOk := pub.Verify(digest, ecdsaSig, &secec.ECDSAOptions{
+		Hash:     h,
+		Encoding: secec.EncodingASN1,
+	})
+	schnorrOk := NewSchnorrPublicKeyFromECDSA(pub).Verify(msg, schnorrSig)
+
+	return ecdsaOk && schnorrOk
+}
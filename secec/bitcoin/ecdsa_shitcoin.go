@@ -7,11 +7,15 @@ package bitcoin
 
 import (
 	"crypto"
-	_ "crypto/sha256" // Pull in SHA256
+	"crypto/sha256"
+	"errors"
+	"io"
 
 	"gitlab.com/yawning/secp256k1-voi/secec"
 )
 
+var errInvalidSighash = errors.New("secp256k1/secec/bitcoin: invalid sighash")
+
 var optsShitcoin = &secec.ECDSAOptions{
 	Hash:            crypto.SHA256,
 	Encoding:        secec.EncodingASN1,
@@ -33,3 +37,26 @@ func VerifyASN1(k *secec.PublicKey, digest, sig []byte) bool {
 
 	return k.Verify(digest, sig[:len(sig)-1], optsShitcoin)
 }
+
+// SignTxInput signs `sighash` using the PrivateKey `k`, and returns the
+// BIP-0066 encoded signature, with the trailing `sighashType` byte
+// appended, suitable for inclusion in a transaction input.
+//
+// Notes: If `rand` is nil, [crypto/rand.Reader] will be used.
+// `s` will always be less than or equal to `n / 2`.
+func SignTxInput(k *secec.PrivateKey, rand io.Reader, sighash []byte, sighashType byte) ([]byte, error) {
+	if len(sighash) != sha256.Size {
+		return nil, errInvalidSighash
+	}
+
+	r, s, _, err := k.SignRaw(rand, sighash)
+	if err != nil {
+		return nil, err
+	}
+
+	// BuildASN1Signature always produces the minimal DER encoding.
+	sig := secec.BuildASN1Signature(r, s)
+	sig = append(sig, sighashType)
+
+	return sig, nil
+}
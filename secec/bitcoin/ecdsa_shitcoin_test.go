@@ -49,4 +49,22 @@ func TestECDSA(t *testing.T) {
 	sigBytes = append(sigBytes, 69)
 	ok = VerifyASN1(pub, hBytes, sigBytes)
 	require.False(t, ok, "Verify - large S")
+
+	t.Run("SignTxInput", func(t *testing.T) {
+		const sighashAll = 0x01
+
+		for i := 0; i < 100; i++ {
+			sig, err := SignTxInput(priv, nil, hBytes, sighashAll)
+			require.NoError(t, err, "SignTxInput")
+			require.True(t, IsValidSignatureEncodingBIP0066(sig), "SignTxInput - BIP-0066")
+			require.EqualValues(t, sighashAll, sig[len(sig)-1], "SignTxInput - sighash type")
+
+			ok := VerifyASN1(pub, hBytes, sig)
+			require.True(t, ok, "SignTxInput - VerifyASN1")
+		}
+
+		sig, err := SignTxInput(priv, nil, hBytes[:31], sighashAll)
+		require.Nil(t, sig, "SignTxInput - truncated")
+		require.ErrorIs(t, err, errInvalidSighash, "SignTxInput - truncated")
+	})
 }
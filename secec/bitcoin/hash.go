@@ -0,0 +1,45 @@
+// Copyright (c) 2023 Yawning Angel
+//
+// SPDX-License-Identifier: BSD-3-Clause
+
+package bitcoin
+
+import (
+	"crypto/sha256"
+	"hash"
+)
+
+// NewDoubleSHA256 returns a new [hash.Hash] computing `SHA256(SHA256(m))`,
+// which is used extensively by bitcoin (eg: for txids).  The inner hash
+// is computed incrementally, so large inputs need not be buffered.
+func NewDoubleSHA256() hash.Hash {
+	return &doubleSHA256{
+		inner: sha256.New(),
+	}
+}
+
+type doubleSHA256 struct {
+	inner hash.Hash
+}
+
+func (h *doubleSHA256) Write(p []byte) (int, error) {
+	return h.inner.Write(p)
+}
+
+func (h *doubleSHA256) Sum(b []byte) []byte {
+	var innerDigest [sha256.Size]byte
+	outerDigest := sha256.Sum256(h.inner.Sum(innerDigest[:0]))
+	return append(b, outerDigest[:]...)
+}
+
+func (h *doubleSHA256) Reset() {
+	h.inner.Reset()
+}
+
+func (h *doubleSHA256) Size() int {
+	return sha256.Size
+}
+
+func (h *doubleSHA256) BlockSize() int {
+	return sha256.BlockSize
+}
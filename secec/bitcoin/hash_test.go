@@ -0,0 +1,40 @@
+// Copyright (c) 2023 Yawning Angel
+//
+// SPDX-License-Identifier: BSD-3-Clause
+
+package bitcoin
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gitlab.com/yawning/secp256k1-voi/internal/helpers"
+)
+
+func TestDoubleSHA256(t *testing.T) {
+	h := NewDoubleSHA256()
+	require.Equal(t, sha256.Size, h.Size(), "Size")
+	require.Equal(t, sha256.BlockSize, h.BlockSize(), "BlockSize")
+
+	// SHA256(SHA256(""))
+	require.Equal(t, helpers.MustBytesFromHex("5df6e0e2761359d30a8275058e299fcc0381534545f55cf43e41983f5d4c9456"), h.Sum(nil), "Sum(empty)")
+
+	msg := []byte(testMessage)
+	inner := sha256.Sum256(msg)
+	expected := sha256.Sum256(inner[:])
+
+	// Write in pieces to exercise the streaming behavior.
+	_, _ = h.Write(msg[:10])
+	_, _ = h.Write(msg[10:])
+	require.Equal(t, expected[:], h.Sum(nil), "Sum")
+	require.Equal(t, expected[:], h.Sum(nil), "Sum - idempotent")
+
+	prefix := []byte("prefix")
+	require.Equal(t, append(prefix, expected[:]...), h.Sum(prefix), "Sum(prefix)")
+
+	h.Reset()
+	_, _ = h.Write(msg)
+	require.Equal(t, expected[:], h.Sum(nil), "Sum - after Reset")
+}
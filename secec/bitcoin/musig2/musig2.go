@@ -0,0 +1,545 @@
+// Copyright (c) 2023 Yawning Angel
+//
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package musig2 implements the MuSig2 multi-signature scheme for
+// BIP-0340 Schnorr signatures, as specified in BIP-0327.
+//
+// Note: Key aggregation tweaking (plain and x-only) is not supported.
+package musig2
+
+import (
+	"bytes"
+	csrand "crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"gitlab.com/yawning/secp256k1-voi"
+	"gitlab.com/yawning/secp256k1-voi/internal/disalloweq"
+	"gitlab.com/yawning/secp256k1-voi/internal/taggedhash"
+	"gitlab.com/yawning/secp256k1-voi/secec"
+	"gitlab.com/yawning/secp256k1-voi/secec/bitcoin"
+)
+
+const (
+	// PublicNonceSize is the size of a byte-encoded public nonce in bytes.
+	PublicNonceSize = 2 * secp256k1.CompressedPointSize
+	// AggregateNonceSize is the size of a byte-encoded aggregate nonce
+	// in bytes.
+	AggregateNonceSize = PublicNonceSize
+	// PartialSignatureSize is the size of a byte-encoded partial
+	// signature in bytes.
+	PartialSignatureSize = secp256k1.ScalarSize
+
+	entropySize = 32
+
+	tagKeyAggList        = "KeyAgg list"
+	tagKeyAggCoefficient = "KeyAgg coefficient"
+	tagAux               = "MuSig/aux"
+	tagNonce             = "MuSig/nonce"
+	tagNonceCoef         = "MuSig/noncecoef"
+	tagChallenge         = "BIP0340/challenge"
+)
+
+var (
+	errNoKeys          = errors.New("secp256k1/secec/bitcoin/musig2: no public keys")
+	errQIsInfinity     = errors.New("secp256k1/secec/bitcoin/musig2: aggregate public key is the point at infinity")
+	errKeyNotInSet     = errors.New("secp256k1/secec/bitcoin/musig2: public key not in aggregate")
+	errKeyMismatch     = errors.New("secp256k1/secec/bitcoin/musig2: private key does not match secret nonce")
+	errEntropySource   = errors.New("secp256k1/secec/bitcoin/musig2: entropy source failure")
+	errKIsZero         = errors.New("secp256k1/secec/bitcoin/musig2: k = 0")
+	errNonceReuse      = errors.New("secp256k1/secec/bitcoin/musig2: secret nonce already used")
+	errInvalidNonce    = errors.New("secp256k1/secec/bitcoin/musig2: invalid nonce")
+	errInvalidPartial  = errors.New("secp256k1/secec/bitcoin/musig2: invalid partial signature")
+	errNoPartialSigs   = errors.New("secp256k1/secec/bitcoin/musig2: no partial signatures")
+	errUninitialized   = errors.New("secp256k1/secec/bitcoin/musig2: uninitialized context")
+	errExtraInTooLarge = errors.New("secp256k1/secec/bitcoin/musig2: extra input too large")
+	errZeroizedKey     = errors.New("secp256k1/secec/bitcoin/musig2: private key has been zeroized")
+)
+
+// KeyAggContext is the key aggregation context for a set of public keys.
+type KeyAggContext struct {
+	_ disalloweq.DisallowEqual
+
+	pks       [][]byte // Compressed SEC 1 encoding
+	pkHash    []byte   // L = hash_KeyAgg list(pk_1 || ... || pk_u)
+	secondKey []byte
+
+	q     *secp256k1.Point
+	qX    []byte
+	aggPk *bitcoin.SchnorrPublicKey
+}
+
+// PublicKey returns the x-only aggregate public key.
+func (ctx *KeyAggContext) PublicKey() *bitcoin.SchnorrPublicKey {
+	return ctx.aggPk
+}
+
+func (ctx *KeyAggContext) coefficient(pk []byte) *secp256k1.Scalar {
+	if bytes.Equal(pk, ctx.secondKey) {
+		return secp256k1.NewScalar().One()
+	}
+
+	aBytes := taggedhash.Sum(tagKeyAggCoefficient, ctx.pkHash, pk)
+	a, _ := secp256k1.NewScalarFromBytes((*[secp256k1.ScalarSize]byte)(aBytes))
+	return a
+}
+
+func (ctx *KeyAggContext) sessionCoefficient(pk []byte) (*secp256k1.Scalar, error) {
+	for _, v := range ctx.pks {
+		if bytes.Equal(v, pk) {
+			return ctx.coefficient(pk), nil
+		}
+	}
+
+	return nil, errKeyNotInSet
+}
+
+// NewKeyAggContext aggregates the ordered set of public keys `keys`,
+// using the KeyAgg algorithm as specified in BIP-0327, and returns the
+// key aggregation context.
+//
+// Note: The order of `keys` is significant.  Callers that require an
+// order-independent aggregate MUST sort the keys first (KeySort).
+func NewKeyAggContext(keys []*secec.PublicKey) (*KeyAggContext, error) {
+	if len(keys) == 0 {
+		return nil, errNoKeys
+	}
+
+	ctx := &KeyAggContext{
+		pks:       make([][]byte, 0, len(keys)),
+		secondKey: make([]byte, secp256k1.CompressedPointSize),
+	}
+	for _, k := range keys {
+		ctx.pks = append(ctx.pks, k.CompressedBytes())
+	}
+	ctx.pkHash = taggedhash.Sum(tagKeyAggList, ctx.pks...)
+	for _, pk := range ctx.pks[1:] {
+		if !bytes.Equal(pk, ctx.pks[0]) {
+			ctx.secondKey = pk
+			break
+		}
+	}
+
+	// Q = a_1 * P_1 + ... + a_u * P_u
+	scalars := make([]*secp256k1.Scalar, 0, len(keys))
+	points := make([]*secp256k1.Point, 0, len(keys))
+	for i, k := range keys {
+		scalars = append(scalars, ctx.coefficient(ctx.pks[i]))
+		points = append(points, k.Point())
+	}
+	q := secp256k1.NewIdentityPoint().MultiScalarMultVartime(scalars, points)
+	if q.IsIdentity() != 0 {
+		return nil, errQIsInfinity
+	}
+
+	ctx.q = q
+	ctx.qX, _ = q.XBytes() // Can't fail, Q != Inf.
+	ctx.aggPk, _ = bitcoin.NewSchnorrPublicKeyFromPoint(q)
+
+	return ctx, nil
+}
+
+// AggregatePublicKeys aggregates the ordered set of x-only public keys
+// `keys`, using the KeyAgg algorithm as specified in BIP-0327, and
+// returns the x-only aggregate public key.
+//
+// Note: Each x-only key is treated as the plain public key with an
+// even Y-coordinate (`0x02 || x`).  Signers MUST use the corresponding
+// (negated as required) private key, and the context from
+// `NewKeyAggContext` with the same keys, when signing.
+func AggregatePublicKeys(keys []*bitcoin.SchnorrPublicKey) (*bitcoin.SchnorrPublicKey, error) {
+	pks := make([]*secec.PublicKey, 0, len(keys))
+	for _, k := range keys {
+		pk, err := secec.NewPublicKeyFromPoint(k.Point())
+		if err != nil {
+			return nil, err
+		}
+		pks = append(pks, pk)
+	}
+
+	ctx, err := NewKeyAggContext(pks)
+	if err != nil {
+		return nil, err
+	}
+
+	return ctx.PublicKey(), nil
+}
+
+// SecretNonce is a MuSig2 secret nonce.
+//
+// WARNING: A SecretNonce MUST NOT be used to sign more than once, and
+// MUST NOT be serialized or copied.  It is erased by Sign.
+type SecretNonce struct {
+	_ disalloweq.DisallowEqual
+
+	k1, k2 *secp256k1.Scalar
+	pk     []byte
+	isUsed bool
+}
+
+// GenNonces generates a MuSig2 nonce pair for the PrivateKey `sk`,
+// using the NonceGen algorithm as specified in BIP-0327, and returns
+// the secret nonce, and the byte-encoded public nonce.  The optional
+// `keyAgg`, `msg` and `extraIn` are mixed into the derivation, with a
+// nil `msg` denoting that the message is not present.
+//
+// Note: If `rand` is nil, [crypto/rand.Reader] will be used.
+func GenNonces(rand io.Reader, sk *secec.PrivateKey, keyAgg *KeyAggContext, msg, extraIn []byte) (*SecretNonce, []byte, error) {
+	if rand == nil {
+		rand = csrand.Reader
+	}
+
+	var randPrime [entropySize]byte
+	if _, err := io.ReadFull(rand, randPrime[:]); err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", errEntropySource, err)
+	}
+
+	var aggPk []byte
+	if keyAgg != nil {
+		aggPk = keyAgg.qX
+	}
+
+	return nonceGen(&randPrime, sk.Bytes(), sk.PublicKey().CompressedBytes(), aggPk, msg, extraIn)
+}
+
+func nonceGen(randPrime *[entropySize]byte, sk, pk, aggPk, msg, extraIn []byte) (*SecretNonce, []byte, error) {
+	if uint64(len(extraIn)) > 0xffffffff {
+		return nil, nil, errExtraInTooLarge
+	}
+
+	// If the optional argument sk is present:
+	//   Let rand be the byte-wise xor of sk and hash_MuSig/aux(rand')
+	// Else:
+	//   Let rand = rand'
+	rand := randPrime[:]
+	if sk != nil {
+		rand = taggedhash.Sum(tagAux, randPrime[:])
+		for i := range rand {
+			rand[i] ^= sk[i]
+		}
+	}
+
+	// If the optional argument m is not present:
+	//   Let m_prefixed = bytes(1, 0)
+	// Else:
+	//   Let m_prefixed = bytes(1, 1) || bytes(8, len(m)) || m
+	msgPrefixed := []byte{0}
+	if msg != nil {
+		msgPrefixed = binary.BigEndian.AppendUint64([]byte{1}, uint64(len(msg)))
+		msgPrefixed = append(msgPrefixed, msg...)
+	}
+
+	// Let k_i = int(hash_MuSig/nonce(rand || bytes(1, len(pk)) || pk ||
+	//   bytes(1, len(aggpk)) || aggpk || m_prefixed ||
+	//   bytes(4, len(extra_in)) || extra_in || bytes(1, i - 1))) mod n
+	//   for i = 1,2
+	// Fail if k_1 = 0 or k_2 = 0
+	var ks [2]*secp256k1.Scalar
+	for i := range ks {
+		kBytes := taggedhash.Sum(
+			tagNonce,
+			rand,
+			[]byte{byte(len(pk))}, pk,
+			[]byte{byte(len(aggPk))}, aggPk,
+			msgPrefixed,
+			binary.BigEndian.AppendUint32(nil, uint32(len(extraIn))), extraIn,
+			[]byte{byte(i)},
+		)
+		ks[i], _ = secp256k1.NewScalarFromBytes((*[secp256k1.ScalarSize]byte)(kBytes))
+		if ks[i].IsZero() != 0 {
+			return nil, nil, errKIsZero
+		}
+	}
+
+	// Let R*_1 = k_1 * G, R*_2 = k_2 * G
+	// Let pubnonce = cbytes(R*_1) || cbytes(R*_2)
+	pubNonce := make([]byte, 0, PublicNonceSize)
+	for _, k := range ks {
+		pubNonce = append(pubNonce, secp256k1.NewIdentityPoint().ScalarBaseMult(k).CompressedBytes()...)
+	}
+
+	secNonce := &SecretNonce{
+		k1: ks[0],
+		k2: ks[1],
+		pk: bytes.Clone(pk),
+	}
+
+	return secNonce, pubNonce, nil
+}
+
+// AggregateNonces aggregates the byte-encoded public nonces `pubNonces`,
+// using the NonceAgg algorithm as specified in BIP-0327, and returns
+// the byte-encoded aggregate nonce.
+func AggregateNonces(pubNonces [][]byte) ([]byte, error) {
+	if len(pubNonces) == 0 {
+		return nil, errInvalidNonce
+	}
+
+	aggNonce := make([]byte, 0, AggregateNonceSize)
+	for j := 0; j < 2; j++ {
+		// Let R_j = sum(cpoint(pubnonce_i[(j-1)*33:j*33]))
+		r := secp256k1.NewIdentityPoint()
+		for i, pubNonce := range pubNonces {
+			if len(pubNonce) != PublicNonceSize {
+				return nil, fmt.Errorf("%w: pubnonce[%d]", errInvalidNonce, i)
+			}
+
+			off := j * secp256k1.CompressedPointSize
+			pt, err := secp256k1.NewIdentityPoint().SetCompressedBytes(pubNonce[off : off+secp256k1.CompressedPointSize])
+			if err != nil {
+				return nil, fmt.Errorf("%w: pubnonce[%d]: %w", errInvalidNonce, i, err)
+			}
+			r.Add(r, pt)
+		}
+
+		// Return aggnonce = cbytes_ext(R_1) || cbytes_ext(R_2)
+		aggNonce = appendCBytesExt(aggNonce, r)
+	}
+
+	return aggNonce, nil
+}
+
+// Session is a MuSig2 signing session, for a specific aggregate nonce
+// and message.
+type Session struct {
+	_ disalloweq.DisallowEqual
+
+	keyAgg *KeyAggContext
+
+	b, e *secp256k1.Scalar
+	r    *secp256k1.Point
+	rX   []byte
+}
+
+// NewSession creates a new signing session for the message `msg`,
+// using the key aggregation context `keyAgg`, and the byte-encoded
+// aggregate nonce `aggNonce`.
+func NewSession(keyAgg *KeyAggContext, aggNonce, msg []byte) (*Session, error) {
+	if keyAgg == nil || keyAgg.q == nil {
+		return nil, errUninitialized
+	}
+	if len(aggNonce) != AggregateNonceSize {
+		return nil, errInvalidNonce
+	}
+
+	// Let R'_1 = cpoint_ext(aggnonce[0:33]), R'_2 = cpoint_ext(aggnonce[33:66])
+	r1, err := cpointExt(aggNonce[:secp256k1.CompressedPointSize])
+	if err != nil {
+		return nil, err
+	}
+	r2, err := cpointExt(aggNonce[secp256k1.CompressedPointSize:])
+	if err != nil {
+		return nil, err
+	}
+
+	// Let b = int(hash_MuSig/noncecoef(aggnonce || xbytes(Q) || m)) mod n
+	bBytes := taggedhash.Sum(tagNonceCoef, aggNonce, keyAgg.qX, msg)
+	b, _ := secp256k1.NewScalarFromBytes((*[secp256k1.ScalarSize]byte)(bBytes))
+
+	// Let R' = R'_1 + b * R'_2
+	// If is_infinite(R'): Let final nonce R = G
+	// Else: Let final nonce R = R'
+	r := secp256k1.NewIdentityPoint().DoubleScalarMultBasepointVartime(secp256k1.NewScalar(), b, r2)
+	r.Add(r, r1)
+	if r.IsIdentity() != 0 {
+		r.Generator()
+	}
+	rX, _ := r.XBytes() // Can't fail, R != Inf.
+
+	// Let e = int(hash_BIP0340/challenge(xbytes(R) || xbytes(Q) || m)) mod n
+	eBytes := taggedhash.Sum(tagChallenge, rX, keyAgg.qX, msg)
+	e, _ := secp256k1.NewScalarFromBytes((*[secp256k1.ScalarSize]byte)(eBytes))
+
+	return &Session{
+		keyAgg: keyAgg,
+		b:      b,
+		e:      e,
+		r:      r,
+		rX:     rX,
+	}, nil
+}
+
+// Sign produces a partial signature for the session, using the secret
+// nonce `secNonce`, and the PrivateKey `sk`, using the Sign algorithm
+// as specified in BIP-0327.  It returns the byte-encoded partial
+// signature.
+//
+// Note: `secNonce` is erased regardless of success, and can not be
+// used again.
+func Sign(session *Session, secNonce *SecretNonce, sk *secec.PrivateKey) ([]byte, error) {
+	if secNonce.isUsed {
+		return nil, errNonceReuse
+	}
+	defer func() {
+		secNonce.k1.Zero()
+		secNonce.k2.Zero()
+		secNonce.isUsed = true
+	}()
+
+	// Let k'_1 = int(secnonce[0:32]), k'_2 = int(secnonce[32:64])
+	// Fail if k'_i = 0 or k'_i >= n for i = 1..2
+	if secNonce.k1.IsZero()|secNonce.k2.IsZero() != 0 {
+		return nil, errKIsZero
+	}
+
+	// Let k_1 = k'_1 if has_even_y(R) else n - k'_1
+	// Let k_2 = k'_2 if has_even_y(R) else n - k'_2
+	negateK := session.r.IsYOdd()
+	k1 := secp256k1.NewScalar().ConditionalNegate(secNonce.k1, negateK)
+	k2 := secp256k1.NewScalar().ConditionalNegate(secNonce.k2, negateK)
+
+	// Let d' = int(sk)
+	// Let P = d' * G
+	// Let pk = cbytes(P)
+	// Fail if pk != secnonce[64:97]
+	pk := sk.PublicKey().CompressedBytes()
+	if !bytes.Equal(pk, secNonce.pk) {
+		return nil, errKeyMismatch
+	}
+
+	// Let a = GetSessionKeyAggCoeff(session_ctx, P); fail if that fails
+	a, err := session.keyAgg.sessionCoefficient(pk)
+	if err != nil {
+		return nil, err
+	}
+
+	// Let g = 1 if has_even_y(Q), otherwise let g = -1 mod n
+	// Let d = g * gacc * d' mod n (gacc = 1, as tweaking is unsupported)
+	d := sk.Scalar()
+	if d.IsZero() != 0 {
+		return nil, errZeroizedKey
+	}
+	d.ConditionalNegate(d, session.keyAgg.q.IsYOdd())
+
+	// Let s = (k_1 + b * k_2 + e * a * d) mod n
+	s := secp256k1.NewScalar().Multiply(session.b, k2)
+	s.Add(s, k1)
+	ead := secp256k1.NewScalar().Multiply(session.e, a)
+	ead.Multiply(ead, d)
+	s.Add(s, ead)
+
+	d.Zero()
+	k1.Zero()
+	k2.Zero()
+
+	// Let psig = bytes(32, s)
+	return s.Bytes(), nil
+}
+
+// PartialSigVerify verifies the byte-encoded partial signature `psig`
+// for the session, produced by the signer with the public key `pk`
+// and the byte-encoded public nonce `pubNonce`, using the
+// PartialSigVerify algorithm as specified in BIP-0327.  Its return
+// value records whether the partial signature is valid.
+//
+// Note: This is variable-time, as all of the inputs are assumed to be
+// public.
+func PartialSigVerify(session *Session, psig, pubNonce []byte, pk *secec.PublicKey) bool {
+	if session == nil || session.keyAgg == nil || len(psig) != PartialSignatureSize || len(pubNonce) != PublicNonceSize {
+		return false
+	}
+
+	// Let s = int(psig); fail if s >= n
+	s, err := secp256k1.NewScalarFromCanonicalBytes((*[secp256k1.ScalarSize]byte)(psig))
+	if err != nil {
+		return false
+	}
+
+	// Let R*_1 = cpoint(pubnonce[0:33]), R*_2 = cpoint(pubnonce[33:66])
+	r1, err := secp256k1.NewIdentityPoint().SetCompressedBytes(pubNonce[:secp256k1.CompressedPointSize])
+	if err != nil {
+		return false
+	}
+	r2, err := secp256k1.NewIdentityPoint().SetCompressedBytes(pubNonce[secp256k1.CompressedPointSize:])
+	if err != nil {
+		return false
+	}
+
+	// Let a = GetSessionKeyAggCoeff(session_ctx, P); fail if that fails
+	pkBytes := pk.CompressedBytes()
+	a, err := session.keyAgg.sessionCoefficient(pkBytes)
+	if err != nil {
+		return false
+	}
+
+	// Let Re*' = R*_1 + b * R*_2
+	// Let Re* = Re*' if has_even_y(R), otherwise let Re* = -Re*'
+	re := secp256k1.NewIdentityPoint().DoubleScalarMultBasepointVartime(secp256k1.NewScalar(), session.b, r2)
+	re.Add(re, r1)
+	re.ConditionalNegate(re, session.r.IsYOdd())
+
+	// Let g = 1 if has_even_y(Q), otherwise let g = -1 mod n
+	// Let g' = g * gacc mod n (gacc = 1, as tweaking is unsupported)
+	// Fail if s * G != Re* + e * a * g' * P
+	//
+	// Note/yawning: This is checked as `-s * G + (e * a * g') * P + Re* == O`.
+	eag := secp256k1.NewScalar().Multiply(session.e, a)
+	eag.ConditionalNegate(eag, session.keyAgg.q.IsYOdd())
+	negS := secp256k1.NewScalar().Negate(s)
+	sum := secp256k1.NewIdentityPoint().DoubleScalarMultBasepointVartime(negS, eag, pk.Point())
+	sum.Add(sum, re)
+
+	return sum.IsIdentity() == 1
+}
+
+// PartialSigAgg aggregates the byte-encoded partial signatures `psigs`
+// for the session, using the PartialSigAgg algorithm as specified in
+// BIP-0327, and returns the BIP-0340 Schnorr signature, which can be
+// verified with the aggregate public key.
+//
+// Note: PartialSigAgg does not verify the partial signatures, so an
+// invalid partial signature will result in an invalid signature.
+// Callers that need to identify the signer that produced an invalid
+// partial signature should use `PartialSigVerify`.
+func PartialSigAgg(session *Session, psigs [][]byte) ([]byte, error) {
+	if len(psigs) == 0 {
+		return nil, errNoPartialSigs
+	}
+
+	// Let s_i = int(psig_i); fail if s_i >= n
+	// Let s = s_1 + ... + s_u + e * g * tacc mod n (tacc = 0)
+	s := secp256k1.NewScalar()
+	for i, psig := range psigs {
+		if len(psig) != PartialSignatureSize {
+			return nil, fmt.Errorf("%w: psig[%d]", errInvalidPartial, i)
+		}
+		si, err := secp256k1.NewScalarFromCanonicalBytes((*[secp256k1.ScalarSize]byte)(psig))
+		if err != nil {
+			return nil, fmt.Errorf("%w: psig[%d]", errInvalidPartial, i)
+		}
+		s.Add(s, si)
+	}
+
+	// Return sig = xbytes(R) || bytes(32, s)
+	sig := make([]byte, 0, bitcoin.SchnorrSignatureSize)
+	sig = append(sig, session.rX...)
+	sig = append(sig, s.Bytes()...)
+
+	return sig, nil
+}
+
+func cpointExt(b []byte) (*secp256k1.Point, error) {
+	var zero [secp256k1.CompressedPointSize]byte
+	if bytes.Equal(b, zero[:]) {
+		return secp256k1.NewIdentityPoint(), nil
+	}
+
+	pt, err := secp256k1.NewIdentityPoint().SetCompressedBytes(b)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidNonce, err)
+	}
+
+	return pt, nil
+}
+
+func appendCBytesExt(dst []byte, pt *secp256k1.Point) []byte {
+	if pt.IsIdentity() != 0 {
+		var zero [secp256k1.CompressedPointSize]byte
+		return append(dst, zero[:]...)
+	}
+
+	return append(dst, pt.CompressedBytes()...)
+}
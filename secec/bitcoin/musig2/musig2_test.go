@@ -0,0 +1,431 @@
+// Copyright (c) 2023 Yawning Angel
+//
+// SPDX-License-Identifier: BSD-3-Clause
+
+package musig2
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gitlab.com/yawning/secp256k1-voi"
+	"gitlab.com/yawning/secp256k1-voi/internal/helpers"
+	"gitlab.com/yawning/secp256k1-voi/secec"
+	"gitlab.com/yawning/secp256k1-voi/secec/bitcoin"
+)
+
+const testMessage = "Blockchain is a 10 year-old technology that has yet to find a use case that is not a scam."
+
+func TestMuSig2(t *testing.T) {
+	t.Run("KeyAgg/BIP-0327", func(t *testing.T) {
+		pks := make([]*secec.PublicKey, 0, 3)
+		for _, s := range []string{
+			"02F9308A019258C31049344F85F89D5229B531C845836F99B08601F113BCE036F9",
+			"03DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659",
+			"023590A94E768F8E1815C2F24B4D80A8E3149316C3518CE7B7AD338368D038CA66",
+		} {
+			pk, err := secec.NewPublicKey(helpers.MustBytesFromHex(s))
+			require.NoError(t, err, "NewPublicKey")
+			pks = append(pks, pk)
+		}
+
+		for i, vec := range []struct {
+			indexes  []int
+			expected string
+		}{
+			{[]int{0, 1, 2}, "90539EEDE565F5D054F32CC0C220126889ED1E5D193BAF15AEF344FE59D4610C"},
+			{[]int{2, 1, 0}, "6204DE8B083426DC6EAF9502D27024D53FC826BF7D2012148A0575435DF54B2B"},
+			{[]int{0, 0, 0}, "B436E3BAD62B8CD409969A224731C193D051162D8C5AE8B109306127DA3AA935"},
+			{[]int{0, 0, 1, 1}, "69BC22BFA5D106306E48A20679DE1D7389386124D07571D0D872686028C26A3E"},
+		} {
+			keys := make([]*secec.PublicKey, 0, len(vec.indexes))
+			for _, idx := range vec.indexes {
+				keys = append(keys, pks[idx])
+			}
+
+			ctx, err := NewKeyAggContext(keys)
+			require.NoError(t, err, "[%d]: NewKeyAggContext", i)
+			require.Equal(t, helpers.MustBytesFromHex(vec.expected), ctx.PublicKey().Bytes(), "[%d]: PublicKey", i)
+		}
+
+		_, err := NewKeyAggContext(nil)
+		require.ErrorIs(t, err, errNoKeys, "NewKeyAggContext - no keys")
+	})
+
+	t.Run("NonceGen/BIP-0327", func(t *testing.T) {
+		// The BIP-0327 nonce_gen_vectors.json inputs.  The expected
+		// values were cross-checked against an independent port of the
+		// BIP-0327 reference NonceGen.
+		var randPrime [entropySize]byte
+		for i, vec := range []struct {
+			sk, pk, aggPk, msg, extraIn []byte
+			expectedSecNonce            string
+			expectedPubNonce            string
+		}{
+			{
+				sk:               bytes.Repeat([]byte{0x02}, 32),
+				pk:               helpers.MustBytesFromHex("024D4B6CD1361032CA9BD2AEB9D900AA4D45D9EAD80AC9423374C451A7254D0766"),
+				aggPk:            bytes.Repeat([]byte{0x07}, 32),
+				msg:              bytes.Repeat([]byte{0x01}, 32),
+				extraIn:          bytes.Repeat([]byte{0x08}, 32),
+				expectedSecNonce: "227243DCB40EF2A13A981DB188FA433717B506BDFA14B1AE47D5DC027C9C3B9EF2370B2AD206E724243215137C86365699361126991E6FEC816845F837BDDAC3",
+				expectedPubNonce: "020A25526B002885996358B3EE5092F2F2F197393E59C06CDFC7A92A91931E20C3024C9FECC6795D5D761F96968D871A1F3BAC605F6ECC4E52E1EBF49E1FF9208AD0",
+			},
+			{
+				sk:               bytes.Repeat([]byte{0x02}, 32),
+				pk:               helpers.MustBytesFromHex("024D4B6CD1361032CA9BD2AEB9D900AA4D45D9EAD80AC9423374C451A7254D0766"),
+				aggPk:            bytes.Repeat([]byte{0x07}, 32),
+				msg:              []byte{}, // Empty, but present.
+				extraIn:          bytes.Repeat([]byte{0x08}, 32),
+				expectedSecNonce: "CD0F47FE471D6788FF3243F47345EA0A179AEF69476BE8348322EF39C2723318870C2065AFB52DEDF02BF4FDBF6D2F442E608692F50C2374C08FFFE57042A61C",
+				expectedPubNonce: "0283D01F92F2B6A8540867AD8C7E725E420BBE27D8A949B67F1602219A3218EDE3034EDB05E0FCC6A1AF733DA418D47F863C874ED150B0F92821BF38B9C1835958E5",
+			},
+			{
+				sk:               bytes.Repeat([]byte{0x02}, 32),
+				pk:               helpers.MustBytesFromHex("024D4B6CD1361032CA9BD2AEB9D900AA4D45D9EAD80AC9423374C451A7254D0766"),
+				aggPk:            bytes.Repeat([]byte{0x07}, 32),
+				msg:              bytes.Repeat([]byte{0x26}, 38),
+				extraIn:          bytes.Repeat([]byte{0x08}, 32),
+				expectedSecNonce: "011F8BC60EF061DEEF4D72A0A87200D9994B3F0CD9867910085C38D5366E3E6B9FF03BC0124E56B24069E91EC3F162378983F194E8BD0ED89BE3059649EAE262",
+				expectedPubNonce: "036C9E0851CCC4C93589C870EF67ECAD52CF883FBAFAA27C1D980199B33407D7D3023AFDDECC096613B4A8B3288FC7A2918F5014674E9F8A80A24572D68CA5506AA8",
+			},
+			{
+				// All optional arguments absent.
+				pk:               helpers.MustBytesFromHex("02F9308A019258C31049344F85F89D5229B531C845836F99B08601F113BCE036F9"),
+				expectedSecNonce: "890E83616A3BC4640AB9B6374F21C81FF89CDDDBAFAA7475AE2A102A92E3EDB29FD7E874E23342813A60D9646948242646B7951CA046B4B36D7D6078506D3C94",
+				expectedPubNonce: "02237A448A2848DD07B3C01C618EB926DFA2F5C294ADC68CBAADA183F016E1EB0E03CA63E5E8EB6DA599C5605FC9340BE1AFAAAFED278500844132B562DB2B1E1ED3",
+			},
+		} {
+			secNonce, pubNonce, err := nonceGen(&randPrime, vec.sk, vec.pk, vec.aggPk, vec.msg, vec.extraIn)
+			require.NoError(t, err, "[%d]: nonceGen", i)
+
+			secNonceBytes := append(secNonce.k1.Bytes(), secNonce.k2.Bytes()...)
+			require.Equal(t, helpers.MustBytesFromHex(vec.expectedSecNonce), secNonceBytes, "[%d]: secnonce", i)
+			require.Equal(t, vec.pk, secNonce.pk, "[%d]: secnonce pk", i)
+			require.Equal(t, helpers.MustBytesFromHex(vec.expectedPubNonce), pubNonce, "[%d]: pubnonce", i)
+		}
+	})
+
+	t.Run("NonceAgg/BIP-0327", func(t *testing.T) {
+		pubNonces := [][]byte{
+			helpers.MustBytesFromHex("020151C80F435648DF67A22B749CD798CE54E0321D034B92B709B567D60A42E66603BA47FBC1834437B3212E89A84D8425E7BF12E0245D98262268EBDCB385D50641"),
+			helpers.MustBytesFromHex("03FF406FFD8ADB9CD29877E4985014F66A59F6CD01C0E88CAA8E5F3166B1F676A60248C264CDD57D3C24D79990B0F865674EB62A0F9018277A95011B41BFC193B833"),
+			helpers.MustBytesFromHex("020151C80F435648DF67A22B749CD798CE54E0321D034B92B709B567D60A42E6660279BE667EF9DCBBAC55A06295CE870B07029BFCDB2DCE28D959F2815B16F81798"),
+			helpers.MustBytesFromHex("03FF406FFD8ADB9CD29877E4985014F66A59F6CD01C0E88CAA8E5F3166B1F676A60379BE667EF9DCBBAC55A06295CE870B07029BFCDB2DCE28D959F2815B16F81798"),
+			helpers.MustBytesFromHex("04FF406FFD8ADB9CD29877E4985014F66A59F6CD01C0E88CAA8E5F3166B1F676A60248C264CDD57D3C24D79990B0F865674EB62A0F9018277A95011B41BFC193B833"),
+			helpers.MustBytesFromHex("03FF406FFD8ADB9CD29877E4985014F66A59F6CD01C0E88CAA8E5F3166B1F676A60248C264CDD57D3C24D79990B0F865674EB62A0F9018277A95011B41BFC193B831"),
+			helpers.MustBytesFromHex("03FF406FFD8ADB9CD29877E4985014F66A59F6CD01C0E88CAA8E5F3166B1F676A602FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEFFFFFC30"),
+		}
+
+		for i, vec := range []struct {
+			indexes  []int
+			expected string
+		}{
+			{[]int{0, 1}, "035FE1873B4F2967F52FEA4A06AD5A8ECCBE9D0FD73068012C894E2E87CCB5804B024725377345BDE0E9C33AF3C43C0A29A9249F2F2956FA8CFEB55C8573D0262DC8"},
+			// The sum of the second points is the point at infinity.
+			{[]int{2, 3}, "035FE1873B4F2967F52FEA4A06AD5A8ECCBE9D0FD73068012C894E2E87CCB5804B000000000000000000000000000000000000000000000000000000000000000000"},
+		} {
+			aggNonce, err := AggregateNonces(selectBytes(pubNonces, vec.indexes))
+			require.NoError(t, err, "[%d]: AggregateNonces", i)
+			require.Equal(t, helpers.MustBytesFromHex(vec.expected), aggNonce, "[%d]: AggregateNonces", i)
+		}
+
+		for i, indexes := range [][]int{
+			{0, 4}, // Invalid public nonce (prefix).
+			{5, 1}, // Invalid public nonce (not on curve).
+			{6, 1}, // Invalid public nonce (x >= p).
+		} {
+			_, err := AggregateNonces(selectBytes(pubNonces, indexes))
+			require.ErrorIs(t, err, errInvalidNonce, "[%d]: AggregateNonces - invalid", i)
+		}
+	})
+
+	t.Run("SignVerify/BIP-0327", func(t *testing.T) {
+		sk, err := secec.NewPrivateKey(helpers.MustBytesFromHex("7FB9E0E687ADA1EEBF7ECFE2F21E73EBDB51A7D450948DFE8D76D7F2D1007671"))
+		require.NoError(t, err, "NewPrivateKey")
+
+		pks := make([]*secec.PublicKey, 0, 3)
+		for _, s := range []string{
+			"03935F972DA013F80AE011890FA89B67A27B7BE6CCB24D3274D18B2D4067F261A9",
+			"02F9308A019258C31049344F85F89D5229B531C845836F99B08601F113BCE036F9",
+			"02DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA661",
+		} {
+			pk, err := secec.NewPublicKey(helpers.MustBytesFromHex(s))
+			require.NoError(t, err, "NewPublicKey")
+			pks = append(pks, pk)
+		}
+		require.True(t, sk.PublicKey().Equal(pks[0]), "sk matches pk[0]")
+
+		// Invalid public key (not on curve).
+		_, err = secec.NewPublicKey(helpers.MustBytesFromHex("020000000000000000000000000000000000000000000000000000000000000007"))
+		require.Error(t, err, "NewPublicKey - invalid")
+
+		secNonce := helpers.MustBytesFromHex("508B81A611F100A6B2B6B29656590898AF488BCF2E1F55CF22E5CFB84421FE61FA27FD49B1D50085B481285E1CA205D55C82CC1B31FF5CD54A489829355901F7")
+		newSecNonce := func(b []byte) *SecretNonce {
+			k1, err := secp256k1.NewScalarFromCanonicalBytes((*[secp256k1.ScalarSize]byte)(b[:32]))
+			require.NoError(t, err, "k1")
+			k2, err := secp256k1.NewScalarFromCanonicalBytes((*[secp256k1.ScalarSize]byte)(b[32:]))
+			require.NoError(t, err, "k2")
+			return &SecretNonce{
+				k1: k1,
+				k2: k2,
+				pk: sk.PublicKey().CompressedBytes(),
+			}
+		}
+
+		pubNonces := [][]byte{
+			helpers.MustBytesFromHex("0337C87821AFD50A8644D820A8F3E02E499C931865C2360FB43D0A0D20DAFE07EA0287BF891D2A6DEAEBADC909352AA9405D1428C15F4B75F04DAE642A95C2548480"),
+			helpers.MustBytesFromHex("0279BE667EF9DCBBAC55A06295CE870B07029BFCDB2DCE28D959F2815B16F817980279BE667EF9DCBBAC55A06295CE870B07029BFCDB2DCE28D959F2815B16F81798"),
+			helpers.MustBytesFromHex("032DE2662628C90B03F5E720284EB52FF7D71F4284F627B68A853D78C78E1FFE9303E4C5524E83FFE1493B9077CF1CA6BEB2090C93D930321071AD40B2F44E599046"),
+			helpers.MustBytesFromHex("0237C87821AFD50A8644D820A8F3E02E499C931865C2360FB43D0A0D20DAFE07EA0387BF891D2A6DEAEBADC909352AA9405D1428C15F4B75F04DAE642A95C2548480"),
+			helpers.MustBytesFromHex("0200000000000000000000000000000000000000000000000000000000000000090287BF891D2A6DEAEBADC909352AA9405D1428C15F4B75F04DAE642A95C2548480"),
+		}
+		aggNonces := [][]byte{
+			helpers.MustBytesFromHex("028465FCF0BBDBCF443AABCCE533D42B4B5A10966AC09A49655E8C42DAAB8FCD61037496A3CC86926D452CAFCFD55D25972CA1675D549310DE296BFF42F72EEEA8C9"),
+			make([]byte, AggregateNonceSize),
+			helpers.MustBytesFromHex("048465FCF0BBDBCF443AABCCE533D42B4B5A10966AC09A49655E8C42DAAB8FCD61037496A3CC86926D452CAFCFD55D25972CA1675D549310DE296BFF42F72EEEA8C9"),
+			helpers.MustBytesFromHex("028465FCF0BBDBCF443AABCCE533D42B4B5A10966AC09A49655E8C42DAAB8FCD61020000000000000000000000000000000000000000000000000000000000000009"),
+			helpers.MustBytesFromHex("028465FCF0BBDBCF443AABCCE533D42B4B5A10966AC09A49655E8C42DAAB8FCD6102FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEFFFFFC30"),
+		}
+		msg := helpers.MustBytesFromHex("F95466D086770E689964664219266FE5ED215C92AE20BAB5C9D79ADDDDF3C0CF")
+
+		newSession := func(keyIndexes []int, aggNonce []byte) *Session {
+			keyAgg, err := NewKeyAggContext(selectKeys(pks, keyIndexes))
+			require.NoError(t, err, "NewKeyAggContext")
+			session, err := NewSession(keyAgg, aggNonce, msg)
+			require.NoError(t, err, "NewSession")
+			return session
+		}
+
+		for i, vec := range []struct {
+			keyIndexes   []int
+			nonceIndexes []int
+			aggNonce     int
+			signer       int
+			expected     string
+		}{
+			{[]int{0, 1, 2}, []int{0, 1, 2}, 0, 0, "012ABBCB52B3016AC03AD82395A1A415C48B93DEF78718E62A7A90052FE224FB"},
+			{[]int{1, 0, 2}, []int{1, 0, 2}, 0, 1, "9FF2F7AAA856150CC8819254218D3ADEEB0535269051897724F9DB3789513A52"},
+			{[]int{1, 2, 0}, []int{1, 2, 0}, 0, 2, "FA23C359F6FAC4E7796BB93BC9F0532A95468C539BA20FF86D7C76ED92227900"},
+			// Both halves of the aggregate nonce are the point at infinity.
+			{[]int{0, 1}, []int{0, 3}, 1, 0, "AE386064B26105404798F75DE2EB9AF5EDA5387B064B83D049CB7C5E08879531"},
+		} {
+			aggNonce, err := AggregateNonces(selectBytes(pubNonces, vec.nonceIndexes))
+			require.NoError(t, err, "[%d]: AggregateNonces", i)
+			require.Equal(t, aggNonces[vec.aggNonce], aggNonce, "[%d]: AggregateNonces", i)
+
+			session := newSession(vec.keyIndexes, aggNonce)
+			psig, err := Sign(session, newSecNonce(secNonce), sk)
+			require.NoError(t, err, "[%d]: Sign", i)
+			require.Equal(t, helpers.MustBytesFromHex(vec.expected), psig, "[%d]: Sign", i)
+
+			pubNonce := pubNonces[vec.nonceIndexes[vec.signer]]
+			require.True(t, PartialSigVerify(session, psig, pubNonce, sk.PublicKey()), "[%d]: PartialSigVerify", i)
+		}
+
+		// Sign errors.
+		session := newSession([]int{1, 2}, aggNonces[0])
+		_, err = Sign(session, newSecNonce(secNonce), sk)
+		require.ErrorIs(t, err, errKeyNotInSet, "Sign - signer not in key set")
+
+		for i, idx := range []int{2, 3, 4} {
+			keyAgg, err := NewKeyAggContext(selectKeys(pks, []int{0, 1, 2}))
+			require.NoError(t, err, "NewKeyAggContext")
+			_, err = NewSession(keyAgg, aggNonces[idx], msg)
+			require.ErrorIs(t, err, errInvalidNonce, "[%d]: NewSession - invalid aggnonce", i)
+		}
+
+		session = newSession([]int{0, 1, 2}, aggNonces[0])
+		_, err = Sign(session, newSecNonce(make([]byte, 64)), sk)
+		require.ErrorIs(t, err, errKIsZero, "Sign - zero secnonce")
+
+		// Verify failures.
+		validPsig := helpers.MustBytesFromHex("012ABBCB52B3016AC03AD82395A1A415C48B93DEF78718E62A7A90052FE224FB")
+		for i, vec := range []struct {
+			psig   string
+			signer int
+		}{
+			// Negation of a valid partial signature.
+			{"FED54434AD4CFE953FC527DC6A5E5BE8F6234907B7C187559557CE87A0541C46", 0},
+			// Wrong signer.
+			{"012ABBCB52B3016AC03AD82395A1A415C48B93DEF78718E62A7A90052FE224FB", 1},
+			// Partial signature exceeds group size.
+			{"FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEBAAEDCE6AF48A03BBFD25E8CD0364141", 0},
+		} {
+			ok := PartialSigVerify(session, helpers.MustBytesFromHex(vec.psig), pubNonces[vec.signer], pks[vec.signer])
+			require.False(t, ok, "[%d]: PartialSigVerify - invalid", i)
+		}
+
+		// Verify errors.
+		require.False(t, PartialSigVerify(session, validPsig, pubNonces[4], pks[0]), "PartialSigVerify - invalid pubnonce")
+		require.False(t, PartialSigVerify(newSession([]int{1, 2}, aggNonces[0]), validPsig, pubNonces[0], pks[0]), "PartialSigVerify - key not in set")
+	})
+
+	t.Run("SigAgg/BIP-0327", func(t *testing.T) {
+		pks := make([]*secec.PublicKey, 0, 2)
+		for _, s := range []string{
+			"03935F972DA013F80AE011890FA89B67A27B7BE6CCB24D3274D18B2D4067F261A9",
+			"02D2DC6F5DF7C56ACF38C7FA0AE7A759AE30E19B37359DFDE015872324C7EF6E05",
+		} {
+			pk, err := secec.NewPublicKey(helpers.MustBytesFromHex(s))
+			require.NoError(t, err, "NewPublicKey")
+			pks = append(pks, pk)
+		}
+
+		aggNonce, err := AggregateNonces([][]byte{
+			helpers.MustBytesFromHex("036E5EE6E28824029FEA3E8A9DDD2C8483F5AF98F7177C3AF3CB6F47CAF8D94AE902DBA67E4A1F3680826172DA15AFB1A8CA85C7C5CC88900905C8DC8C328511B53E"),
+			helpers.MustBytesFromHex("03E4F798DA48A76EEC1C9CC5AB7A880FFBA201A5F064E627EC9CB0031D1D58FC5103E06180315C5A522B7EC7C08B69DCD721C313C940819296D0A7AB8E8795AC1F00"),
+		})
+		require.NoError(t, err, "AggregateNonces")
+
+		keyAgg, err := NewKeyAggContext(pks)
+		require.NoError(t, err, "NewKeyAggContext")
+		msg := helpers.MustBytesFromHex("599C67EA410D005B9DA90817CF03ED3B1C868E4DA4EDF00A5880B0082C237869")
+		session, err := NewSession(keyAgg, aggNonce, msg)
+		require.NoError(t, err, "NewSession")
+
+		psigs := [][]byte{
+			helpers.MustBytesFromHex("B15D2CD3C3D22B04DAE438CE653F6B4ECF042F42CFDED7C41B64AAF9B4AF53FB"),
+			helpers.MustBytesFromHex("6193D6AC61B354E9105BBDC8937A3454A6D705B6D57322A5A472A02CE99FCB64"),
+		}
+		sig, err := PartialSigAgg(session, psigs)
+		require.NoError(t, err, "PartialSigAgg")
+		require.Equal(t, helpers.MustBytesFromHex("041DA22223CE65C92C9A0D6C2CAC828AAF1EEE56304FEC371DDF91EBB2B9EF0912F1038025857FEDEB3FF696F8B99FA4BB2C5812F6095A2E0004EC99CE18DE1E"), sig, "PartialSigAgg")
+		require.True(t, keyAgg.PublicKey().Verify(msg, sig), "Verify")
+
+		// Partial signature exceeds group size.
+		_, err = PartialSigAgg(session, [][]byte{
+			psigs[0],
+			helpers.MustBytesFromHex("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEBAAEDCE6AF48A03BBFD25E8CD0364141"),
+		})
+		require.ErrorIs(t, err, errInvalidPartial, "PartialSigAgg - psig >= n")
+	})
+
+	t.Run("Sign", func(t *testing.T) {
+		const numSigners = 3
+
+		msg := []byte(testMessage)
+
+		sks := make([]*secec.PrivateKey, 0, numSigners)
+		pks := make([]*secec.PublicKey, 0, numSigners)
+		for i := 0; i < numSigners; i++ {
+			sk, err := secec.GenerateKey()
+			require.NoError(t, err, "GenerateKey")
+			sks = append(sks, sk)
+			pks = append(pks, sk.PublicKey())
+		}
+
+		keyAgg, err := NewKeyAggContext(pks)
+		require.NoError(t, err, "NewKeyAggContext")
+
+		secNonces := make([]*SecretNonce, 0, numSigners)
+		pubNonces := make([][]byte, 0, numSigners)
+		for i, sk := range sks {
+			secNonce, pubNonce, err := GenNonces(nil, sk, keyAgg, msg, []byte(fmt.Sprintf("signer %d", i)))
+			require.NoError(t, err, "GenNonces")
+			require.Len(t, pubNonce, PublicNonceSize, "pubNonce")
+			secNonces = append(secNonces, secNonce)
+			pubNonces = append(pubNonces, pubNonce)
+		}
+
+		aggNonce, err := AggregateNonces(pubNonces)
+		require.NoError(t, err, "AggregateNonces")
+
+		session, err := NewSession(keyAgg, aggNonce, msg)
+		require.NoError(t, err, "NewSession")
+
+		_, err = Sign(session, secNonces[0], sks[1])
+		require.ErrorIs(t, err, errKeyMismatch, "Sign - wrong key")
+		_, err = Sign(session, secNonces[0], sks[0])
+		require.ErrorIs(t, err, errNonceReuse, "Sign - nonce reuse")
+
+		// Regenerate the nonce that was consumed by the failure tests.
+		secNonces[0], pubNonces[0], err = GenNonces(nil, sks[0], keyAgg, msg, nil)
+		require.NoError(t, err, "GenNonces")
+		aggNonce, err = AggregateNonces(pubNonces)
+		require.NoError(t, err, "AggregateNonces")
+		session, err = NewSession(keyAgg, aggNonce, msg)
+		require.NoError(t, err, "NewSession")
+
+		psigs := make([][]byte, 0, numSigners)
+		for i, sk := range sks {
+			psig, err := Sign(session, secNonces[i], sk)
+			require.NoError(t, err, "Sign")
+			require.Len(t, psig, PartialSignatureSize, "psig")
+			psigs = append(psigs, psig)
+		}
+
+		for i, psig := range psigs {
+			require.True(t, PartialSigVerify(session, psig, pubNonces[i], pks[i]), "PartialSigVerify(%d)", i)
+		}
+
+		sig, err := PartialSigAgg(session, psigs)
+		require.NoError(t, err, "PartialSigAgg")
+		require.True(t, keyAgg.PublicKey().Verify(msg, sig), "Verify")
+		require.False(t, keyAgg.PublicKey().Verify([]byte("wrong message"), sig), "Verify - wrong message")
+
+		// A bad partial signature can be attributed to its signer.
+		badPsigs := append([][]byte{}, psigs...)
+		badPsigs[1] = bytes.Clone(psigs[1])
+		badPsigs[1][0] ^= 0x01
+		sig, err = PartialSigAgg(session, badPsigs)
+		require.NoError(t, err, "PartialSigAgg - bad psig")
+		require.False(t, keyAgg.PublicKey().Verify(msg, sig), "Verify - bad psig")
+		for i, psig := range badPsigs {
+			require.Equal(t, i != 1, PartialSigVerify(session, psig, pubNonces[i], pks[i]), "PartialSigVerify(%d) - bad psig", i)
+		}
+
+		sig, err = PartialSigAgg(session, psigs[1:])
+		require.NoError(t, err, "PartialSigAgg - missing")
+		require.False(t, keyAgg.PublicKey().Verify(msg, sig), "Verify - missing partial sig")
+	})
+
+	t.Run("AggregatePublicKeys", func(t *testing.T) {
+		keys := make([]*bitcoin.SchnorrPublicKey, 0, 2)
+		pks := make([]*secec.PublicKey, 0, 2)
+		for i := 0; i < 2; i++ {
+			sk, err := bitcoin.GenerateSchnorrKey()
+			require.NoError(t, err, "GenerateSchnorrKey")
+			keys = append(keys, sk.PublicKey())
+
+			pk, err := secec.NewPublicKeyFromPoint(sk.PublicKey().Point())
+			require.NoError(t, err, "NewPublicKeyFromPoint")
+			pks = append(pks, pk)
+		}
+
+		aggPk, err := AggregatePublicKeys(keys)
+		require.NoError(t, err, "AggregatePublicKeys")
+
+		keyAgg, err := NewKeyAggContext(pks)
+		require.NoError(t, err, "NewKeyAggContext")
+		require.True(t, aggPk.Equal(keyAgg.PublicKey()), "aggregate keys should match")
+	})
+
+	t.Run("AggregateNonces/Invalid", func(t *testing.T) {
+		_, err := AggregateNonces(nil)
+		require.ErrorIs(t, err, errInvalidNonce, "AggregateNonces - empty")
+		_, err = AggregateNonces([][]byte{make([]byte, PublicNonceSize)})
+		require.ErrorIs(t, err, errInvalidNonce, "AggregateNonces - bad point")
+		_, err = AggregateNonces([][]byte{make([]byte, 1)})
+		require.ErrorIs(t, err, errInvalidNonce, "AggregateNonces - bad length")
+	})
+}
+
+func selectBytes(vals [][]byte, indexes []int) [][]byte {
+	ret := make([][]byte, 0, len(indexes))
+	for _, idx := range indexes {
+		ret = append(ret, vals[idx])
+	}
+	return ret
+}
+
+func selectKeys(keys []*secec.PublicKey, indexes []int) []*secec.PublicKey {
+	ret := make([]*secec.PublicKey, 0, len(indexes))
+	for _, idx := range indexes {
+		ret = append(ret, keys[idx])
+	}
+	return ret
+}
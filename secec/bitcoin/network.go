@@ -0,0 +1,49 @@
+// Copyright (c) 2023 Yawning Angel
+//
+// SPDX-License-Identifier: BSD-3-Clause
+
+package bitcoin
+
+import "errors"
+
+// Network is a bitcoin network, which determines the address encoding.
+type Network int
+
+const (
+	// Mainnet is the bitcoin main network.
+	Mainnet Network = iota
+	// Testnet is the bitcoin test network (also used by signet).
+	Testnet
+	// Regtest is the bitcoin regression test network.
+	Regtest
+)
+
+var errInvalidNetwork = errors.New("secp256k1/secec/bitcoin: invalid network")
+
+type networkParams struct {
+	p2pkhVersion byte
+	hrp          string
+}
+
+var networks = map[Network]*networkParams{
+	Mainnet: {
+		p2pkhVersion: 0x00,
+		hrp:          "bc",
+	},
+	Testnet: {
+		p2pkhVersion: 0x6f,
+		hrp:          "tb",
+	},
+	Regtest: {
+		p2pkhVersion: 0x6f,
+		hrp:          "bcrt",
+	},
+}
+
+func (net Network) params() (*networkParams, error) {
+	params, ok := networks[net]
+	if !ok {
+		return nil, errInvalidNetwork
+	}
+	return params, nil
+}
@@ -8,16 +8,19 @@ import (
 	"bytes"
 	"crypto"
 	csrand "crypto/rand"
-	"crypto/sha256"
 	"crypto/subtle"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
+	"runtime"
 	"strings"
 
 	"gitlab.com/yawning/secp256k1-voi"
 	"gitlab.com/yawning/secp256k1-voi/internal/disalloweq"
 	"gitlab.com/yawning/secp256k1-voi/internal/field"
+	"gitlab.com/yawning/secp256k1-voi/internal/pointbatch"
+	"gitlab.com/yawning/secp256k1-voi/internal/taggedhash"
 	"gitlab.com/yawning/secp256k1-voi/secec"
 )
 
@@ -28,22 +31,50 @@ const (
 	// SchnorrSignatureSize is the size of a BIP-0340 Schnorr signature
 	// in bytes.
 	SchnorrSignatureSize = 64
+	// SchnorrPreHashSize is the size of a pre-hashed message digest
+	// in bytes.
+	SchnorrPreHashSize = 32
 
 	schnorrEntropySize = 32
 
 	schnorrTagAux       = "BIP0340/aux"
 	schnorrTagNonce     = "BIP0340/nonce"
 	schnorrTagChallenge = "BIP0340/challenge"
+
+	schnorrTagBatchAux = "secp256k1-voi/BIP0340/batch-aux"
 )
 
 var (
 	errAIsInfinity      = errors.New("secp256k1/secec/bitcoin: public key is the point at infinity")
 	errAIsUninitialized = errors.New("secp256k1/secec/bitcoin: uninitialized public key")
 	errEntropySource    = errors.New("secp256k1/secec/bitcoin: entropy source failure")
+	errInvalidDigest    = errors.New("secp256k1/secec/bitcoin: invalid digest")
 	errInvalidDomainSep = errors.New("secp256k1/secec/bitcoin: invalid domain separator")
 	errInvalidPublicKey = errors.New("secp256k1/secec/bitcoin: invalid public key")
 	errKPrimeIsZero     = errors.New("secp256k1/secec/bitcoin: k' = 0")
 	errSigCheckFailed   = errors.New("secp256k1/secec/bitcoin: failed to verify new sig")
+	errZeroizedKey      = errors.New("secp256k1/secec/bitcoin: private key has been zeroized")
+
+	// ErrBadSchnorrSigLength is the error returned by `VerifyError`
+	// when the signature is not `SchnorrSignatureSize` bytes.
+	ErrBadSchnorrSigLength = errors.New("secp256k1/secec/bitcoin: invalid Schnorr signature length")
+
+	// ErrNonCanonicalR is the error returned by `VerifyError` when
+	// `r >= p`.
+	ErrNonCanonicalR = errors.New("secp256k1/secec/bitcoin: r >= p")
+
+	// ErrOutOfRangeS is the error returned by `VerifyError` when
+	// `s >= n`.
+	ErrOutOfRangeS = errors.New("secp256k1/secec/bitcoin: s >= n")
+
+	// ErrSchnorrVerificationFailed is the error returned by `VerifyError`
+	// when the well-formed signature is invalid.
+	ErrSchnorrVerificationFailed = errors.New("secp256k1/secec/bitcoin: Schnorr signature verification failed")
+
+	schnorrAuxMidstate       = taggedhash.NewMidstate(schnorrTagAux)
+	schnorrNonceMidstate     = taggedhash.NewMidstate(schnorrTagNonce)
+	schnorrChallengeMidstate = taggedhash.NewMidstate(schnorrTagChallenge)
+	schnorrBatchAuxMidstate  = taggedhash.NewMidstate(schnorrTagBatchAux)
 )
 
 // PreHashSchnorrMessage pre-hashes the message `msg`, with the
@@ -59,7 +90,7 @@ func PreHashSchnorrMessage(name string, msg []byte) ([]byte, error) {
 		return nil, errInvalidDomainSep
 	}
 
-	return schnorrTaggedHash(name, msg), nil
+	return taggedhash.Sum(name, msg), nil
 }
 
 // SchnorrPrivateKey is a private key for sigining BIP-0340 Schnorr signatures.
@@ -72,14 +103,40 @@ type SchnorrPrivateKey struct {
 	publicKey *SchnorrPublicKey
 }
 
-// Bytes returns a copy of the encoding of the private key.
+// Bytes returns a copy of the encoding of the private key.  It panics
+// if `k` has been zeroized.
 func (k *SchnorrPrivateKey) Bytes() []byte {
-	return k.dPrime.Bytes()
+	return k.mustDPrime().Bytes()
 }
 
-// Scalar returns a copy of the scalar underlying `k`.
+// Scalar returns a copy of the scalar underlying `k`.  It panics if
+// `k` has been zeroized.
 func (k *SchnorrPrivateKey) Scalar() *secp256k1.Scalar {
-	return secp256k1.NewScalarFrom(k.dPrime)
+	return secp256k1.NewScalarFrom(k.mustDPrime())
+}
+
+// Zeroize overwrites the secret scalars underlying `k` with zero.  After
+// Zeroize has been called, `k` is unusable, all signing operations
+// will return an error, and `Bytes` and `Scalar` will panic.
+//
+// Note: This can not erase copies of the private key (eg: returned by
+// `Bytes` or `Scalar`, or the ECDSA private key `k` was created from).
+func (k *SchnorrPrivateKey) Zeroize() {
+	k.dPrime.Zero()
+	k.d.Zero()
+	runtime.KeepAlive(k)
+}
+
+func (k *SchnorrPrivateKey) isZeroized() bool {
+	// INVARIANT: d' is only ever 0 after Zeroize.
+	return k.dPrime.IsZero() != 0
+}
+
+func (k *SchnorrPrivateKey) mustDPrime() *secp256k1.Scalar {
+	if k.isZeroized() {
+		panic(errZeroizedKey)
+	}
+	return k.dPrime
 }
 
 // Equal returns whether `x` represents the same private key as `k`.
@@ -135,6 +192,143 @@ func (k *SchnorrPrivateKey) Sign(rand io.Reader, msg []byte, _ crypto.SignerOpts
 	return signSchnorr(&auxEntropy, k, msg)
 }
 
+// SignSchnorrPreHashed signs the `SchnorrPreHashSize`-byte pre-hashed
+// message `digest` (eg: as returned by `PreHashSchnorrMessage`) using
+// the SchnorrPrivateKey `k`, using the signing procedure as specified
+// in BIP-0340, with `digest` used directly as the message.  It returns
+// the byte-encoded signature.
+//
+// Note: The resulting signature is identical to what `Sign` would
+// produce for `digest`.  It is the caller's responsibility to ensure
+// that verifiers treat the message as pre-hashed (eg: with
+// `VerifyPreHashed`), as a pre-hashed signature will not verify
+// against the raw message.  If `rand` is nil, [crypto/rand.Reader]
+// will be used.
+func (k *SchnorrPrivateKey) SignSchnorrPreHashed(rand io.Reader, digest []byte) ([]byte, error) {
+	if len(digest) != SchnorrPreHashSize {
+		return nil, errInvalidDigest
+	}
+
+	return k.Sign(rand, digest, nil)
+}
+
+// SignBatch signs each of `msgs` using the SchnorrPrivateKey `k`, using
+// the signing procedure as specified in BIP-0340.  It returns the
+// byte-encoded signatures, in the same order as `msgs`.
+//
+// This is faster than calling `Sign` for each message, as the
+// precomputed signing values are reused across the batch, the nonce
+// commitments (and the self-verification points) are converted to
+// affine coordinates with a single field inversion, and a single
+// 32-byte seed is read from `rand`, from which the per-message
+// auxiliary randomness is derived as
+// `hash_secp256k1-voi/BIP0340/batch-aux(seed || uint64_le(i))`.
+//
+// Note: If `rand` is nil, [crypto/rand.Reader] will be used.
+func (k *SchnorrPrivateKey) SignBatch(rand io.Reader, msgs [][]byte) ([][]byte, error) {
+	if rand == nil {
+		rand = csrand.Reader
+	}
+
+	signer, err := newSchnorrSigner(k)
+	if err != nil {
+		return nil, err
+	}
+	defer signer.zero()
+
+	var seed [schnorrEntropySize]byte
+	if _, err := io.ReadFull(rand, seed[:]); err != nil {
+		return nil, fmt.Errorf("%w: %w", errEntropySource, err)
+	}
+
+	// Derive all of the nonces, and R = k'*G, and convert the Rs to
+	// affine coordinates with a single inversion.
+	kPrimes := make([]*secp256k1.Scalar, 0, len(msgs))
+	defer func() {
+		for _, kPrime := range kPrimes {
+			kPrime.Zero()
+		}
+	}()
+	Rs := make([]*secp256k1.Point, 0, len(msgs))
+	for i, msg := range msgs {
+		kPrime, err := signer.deriveNonce(schnorrBatchAuxRand(&seed, i), msg)
+		if err != nil {
+			return nil, err
+		}
+		kPrimes = append(kPrimes, kPrime)
+		Rs = append(Rs, secp256k1.NewIdentityPoint().ScalarBaseMult(kPrime))
+	}
+	rBytes := pointbatch.UncompressedBytes(Rs)
+
+	// Build the signatures, and the self-verification Rs, which also
+	// get converted to affine coordinates with a single inversion.
+	sigs := make([][]byte, 0, len(msgs))
+	checkRs := Rs[:0]
+	for i, msg := range msgs {
+		sig := signer.finishSignature(kPrimes[i], rBytes[i], msg)
+		checkR, ok := schnorrSelfCheckPoint(signer.d, signer.pBytes, msg, sig)
+		if !ok {
+			return nil, errSigCheckFailed
+		}
+		sigs = append(sigs, sig)
+		checkRs = append(checkRs, checkR)
+	}
+	for i, checkRBytes := range pointbatch.UncompressedBytes(checkRs) {
+		if verifySchnorrSignatureRBytes(sigs[i][:32], checkRBytes) != nil {
+			return nil, errSigCheckFailed
+		}
+	}
+
+	return sigs, nil
+}
+
+func schnorrBatchAuxRand(seed *[schnorrEntropySize]byte, i int) *[schnorrEntropySize]byte {
+	var idx [8]byte
+	binary.LittleEndian.PutUint64(idx[:], uint64(i))
+
+	return (*[schnorrEntropySize]byte)(schnorrBatchAuxMidstate.Sum(seed[:], idx[:]))
+}
+
+// NonceCommit derives the BIP-0340 nonce for signing `msg` with the
+// SchnorrPrivateKey `k` and the auxiliary randomness `auxRand`, for the
+// commit phase of interactive protocols.  It returns the commitment
+// `R = k*G` and the secret nonce `k`, where `k` is negated as needed
+// such that `R` has an even Y-coordinate.  The signature can later be
+// completed with `SignWithNonce`.
+//
+// WARNING: The secret nonce MUST be kept secret, and MUST only ever be
+// used to complete a signature of `msg`, exactly once.  Revealing the
+// nonce, or using it to sign two different messages leaks the private
+// key.
+func (k *SchnorrPrivateKey) NonceCommit(auxRand *[schnorrEntropySize]byte, msg []byte) (*secp256k1.Point, *secp256k1.Scalar, error) {
+	kPrime, err := deriveSchnorrNonce(auxRand, k, msg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	R := secp256k1.NewIdentityPoint().ScalarBaseMult(kPrime)
+	rYIsOdd := R.IsYOdd()
+	R.ConditionalNegate(R, rYIsOdd)
+	kPrime.ConditionalNegate(kPrime, rYIsOdd)
+
+	return R, kPrime, nil
+}
+
+// SignWithNonce signs `msg` using the SchnorrPrivateKey `k`, and the
+// secret nonce `secretNonce` (as returned by `NonceCommit`), using the
+// signing procedure as specified in BIP-0340, skipping nonce derivation.
+// It returns the byte-encoded signature.
+//
+// WARNING: See the warnings on `NonceCommit`.  Using a nonce that was
+// not derived by `NonceCommit` for `msg` is extremely dangerous.
+func (k *SchnorrPrivateKey) SignWithNonce(secretNonce *secp256k1.Scalar, msg []byte) ([]byte, error) {
+	if secretNonce.IsZero() != 0 {
+		return nil, errKPrimeIsZero
+	}
+
+	return signSchnorrWithNonce(secretNonce, k, msg)
+}
+
 // NewSchnorrPrivateKey checks that `key` is valid, and returns a
 // SchnorrPrivateKey.
 func NewSchnorrPrivateKey(key []byte) (*SchnorrPrivateKey, error) {
@@ -196,6 +390,17 @@ func (k *SchnorrPublicKey) Bytes() []byte {
 	return bytes.Clone(k.xBytes)
 }
 
+// CompressedBytes returns a copy of the compressed SEC 1 encoding of
+// the point underlying the public key.  As BIP-0340 public keys always
+// have an even Y-coordinate, this is always `0x02 || x`.
+func (k *SchnorrPublicKey) CompressedBytes() []byte {
+	if k.xBytes == nil {
+		panic(errAIsUninitialized)
+	}
+
+	return k.point.CompressedBytes()
+}
+
 // Point returns a copy of the point underlying `k`.
 func (k *SchnorrPublicKey) Point() *secp256k1.Point {
 	return secp256k1.NewPointFrom(k.point)
@@ -219,10 +424,31 @@ func (k *SchnorrPublicKey) Equal(x crypto.PublicKey) bool {
 // in BIP-0340.  Its return value records whether the signature is
 // valid.
 func (k *SchnorrPublicKey) Verify(msg, sig []byte) bool {
-	if len(sig) != SchnorrSignatureSize {
+	return k.VerifyError(msg, sig) == nil
+}
+
+// VerifyPreHashed verifies the Schnorr signature `sig` of the
+// `SchnorrPreHashSize`-byte pre-hashed message `digest`, using the
+// SchnorrPublicKey `k`, using the verification procedure as specified
+// in BIP-0340, with `digest` used directly as the message.  Its return
+// value records whether the signature is valid.
+//
+// Note: It is the caller's responsibility to ensure that raw and
+// pre-hashed messages are not mixed.
+func (k *SchnorrPublicKey) VerifyPreHashed(digest, sig []byte) bool {
+	if len(digest) != SchnorrPreHashSize {
 		return false
 	}
 
+	return k.Verify(digest, sig)
+}
+
+// VerifyError verifies the Schnorr signature `sig` of `msg`, using the
+// SchnorrPublicKey `k`, using the verification procedure as specified
+// in BIP-0340.  It returns nil iff the signature is valid, and one of
+// `ErrBadSchnorrSigLength`, `ErrNonCanonicalR`, `ErrOutOfRangeS`, or
+// `ErrSchnorrVerificationFailed` otherwise.
+func (k *SchnorrPublicKey) VerifyError(msg, sig []byte) error {
 	// The algorithm Verify(pk, m, sig) is defined as:
 
 	// Let P = lift_x(int(pk)); fail if that fails.
@@ -234,9 +460,9 @@ func (k *SchnorrPublicKey) Verify(msg, sig []byte) bool {
 	// Let s = int(sig[32:64]); fail if s >= n.
 	// Let e = int(hashBIP0340/challenge(bytes(r) || bytes(P) || m)) mod n.
 
-	ok, s, e, sigRXBytes := parseSchnorrSignature(k.xBytes, msg, sig)
-	if !ok {
-		return false
+	s, e, sigRXBytes, err := parseSchnorrSignature(k.xBytes, msg, sig)
+	if err != nil {
+		return err
 	}
 
 	// Let R = s*G - e*P.
@@ -252,6 +478,37 @@ func (k *SchnorrPublicKey) Verify(msg, sig []byte) bool {
 	return verifySchnorrSignatureR(sigRXBytes, R)
 }
 
+// VerifySchnorrAllOrNothing verifies each of the Schnorr signatures
+// `sigs[i]` of `msgs[i]`, using the SchnorrPublicKey `keys[i]`, using
+// the verification procedure as specified in BIP-0340.  It returns
+// -1 and true iff every signature is valid, and the index of the first
+// invalid signature and false otherwise.
+//
+// Note: Verification stops at the first invalid signature, favoring
+// latency on adversarial input over throughput.  If the lengths of
+// `keys`, `msgs`, and `sigs` differ, the index of the first entry that
+// is missing from any of them is treated as invalid.
+func VerifySchnorrAllOrNothing(keys []*SchnorrPublicKey, msgs, sigs [][]byte) (int, bool) {
+	n := len(keys)
+	if len(msgs) < n {
+		n = len(msgs)
+	}
+	if len(sigs) < n {
+		n = len(sigs)
+	}
+
+	for i := 0; i < n; i++ {
+		if keys[i] == nil || !keys[i].Verify(msgs[i], sigs[i]) {
+			return i, false
+		}
+	}
+	if n != len(keys) || n != len(msgs) || n != len(sigs) {
+		return n, false
+	}
+
+	return -1, true
+}
+
 // NewSchnorrPublicKey checks that `key` is valid, and returns a
 // SchnorrPublicKey.
 func NewSchnorrPublicKey(key []byte) (*SchnorrPublicKey, error) {
@@ -306,20 +563,15 @@ func NewSchnorrPublicKeyFromECDSA(pk *secec.PublicKey) *SchnorrPublicKey {
 	return pub
 }
 
-func schnorrTaggedHash(tag string, vals ...[]byte) []byte {
-	hashedTag := sha256.Sum256([]byte(tag))
-
-	h := sha256.New()
-	_, _ = h.Write(hashedTag[:])
-	_, _ = h.Write(hashedTag[:])
-	for _, v := range vals {
-		_, _ = h.Write(v)
-	}
-
-	return h.Sum(nil)
+// schnorrSigner is a SchnorrPrivateKey, with the values that are
+// needed for signing precomputed.
+type schnorrSigner struct {
+	d      *secp256k1.Scalar
+	dBytes []byte
+	pBytes []byte
 }
 
-func signSchnorr(auxRand *[schnorrEntropySize]byte, sk *SchnorrPrivateKey, msg []byte) ([]byte, error) {
+func newSchnorrSigner(sk *SchnorrPrivateKey) (*schnorrSigner, error) {
 	// The algorithm Sign(sk, m) is defined as:
 
 	// Let d' = int(sk)
@@ -329,18 +581,75 @@ func signSchnorr(auxRand *[schnorrEntropySize]byte, sk *SchnorrPrivateKey, msg [
 	//
 	// Note/yawning: sk is a pre-deserialized private key, that is
 	// guaranteed to be valid.  There is no reason not to pre-compute
-	// P and d so we do.
+	// P and d so we do, leaving only the check for a key that has
+	// been zeroized.
 
-	pBytes, d := sk.publicKey.xBytes, sk.d
+	if sk.isZeroized() {
+		return nil, errZeroizedKey
+	}
+
+	return &schnorrSigner{
+		d:      sk.d,
+		dBytes: sk.d.Bytes(),
+		pBytes: sk.publicKey.xBytes,
+	}, nil
+}
+
+func (signer *schnorrSigner) zero() {
+	for i := range signer.dBytes {
+		signer.dBytes[i] = 0
+	}
+}
+
+func signSchnorr(auxRand *[schnorrEntropySize]byte, sk *SchnorrPrivateKey, msg []byte) ([]byte, error) {
+	signer, err := newSchnorrSigner(sk)
+	if err != nil {
+		return nil, err
+	}
+	defer signer.zero()
+
+	return signer.sign(auxRand, msg)
+}
 
+func deriveSchnorrNonce(auxRand *[schnorrEntropySize]byte, sk *SchnorrPrivateKey, msg []byte) (*secp256k1.Scalar, error) {
+	signer, err := newSchnorrSigner(sk)
+	if err != nil {
+		return nil, err
+	}
+	defer signer.zero()
+
+	return signer.deriveNonce(auxRand, msg)
+}
+
+func signSchnorrWithNonce(kPrime *secp256k1.Scalar, sk *SchnorrPrivateKey, msg []byte) ([]byte, error) {
+	signer, err := newSchnorrSigner(sk)
+	if err != nil {
+		return nil, err
+	}
+	defer signer.zero()
+
+	return signer.signWithNonce(kPrime, msg)
+}
+
+func (signer *schnorrSigner) sign(auxRand *[schnorrEntropySize]byte, msg []byte) ([]byte, error) {
+	kPrime, err := signer.deriveNonce(auxRand, msg)
+	if err != nil {
+		return nil, err
+	}
+	defer kPrime.Zero()
+
+	return signer.signWithNonce(kPrime, msg)
+}
+
+func (signer *schnorrSigner) deriveNonce(auxRand *[schnorrEntropySize]byte, msg []byte) (*secp256k1.Scalar, error) {
 	// Let t be the byte-wise xor of bytes(d) and hashBIP0340/aux(a)[11].
 
 	var t [schnorrEntropySize]byte
-	subtle.XORBytes(t[:], schnorrTaggedHash(schnorrTagAux, auxRand[:]), d.Bytes())
+	subtle.XORBytes(t[:], schnorrAuxMidstate.Sum(auxRand[:]), signer.dBytes)
 
 	// Let rand = hashBIP0340/nonce(t || bytes(P) || m)[12].
 
-	rand := schnorrTaggedHash(schnorrTagNonce, t[:], pBytes, msg)
+	rand := schnorrNonceMidstate.Sum(t[:], signer.pBytes, msg)
 
 	// Let k' = int(rand) mod n[13].
 
@@ -354,27 +663,14 @@ func signSchnorr(auxRand *[schnorrEntropySize]byte, sk *SchnorrPrivateKey, msg [
 		return nil, errKPrimeIsZero
 	}
 
+	return kPrime, nil
+}
+
+func (signer *schnorrSigner) signWithNonce(kPrime *secp256k1.Scalar, msg []byte) ([]byte, error) {
 	// Let R = k'*G.
 
 	R := secp256k1.NewIdentityPoint().ScalarBaseMult(kPrime)
-	rXBytes, rYIsOdd := secp256k1.SplitUncompressedPoint(R.UncompressedBytes())
-
-	// Let k = k' if has_even_y(R), otherwise let k = n - k' .
-
-	k := secp256k1.NewScalar().ConditionalNegate(kPrime, rYIsOdd)
-
-	// Let e = int(hashBIP0340/challenge(bytes(R) || bytes(P) || m)) mod n.
-
-	eBytes := schnorrTaggedHash(schnorrTagChallenge, rXBytes, pBytes, msg)
-	e, _ := secp256k1.NewScalarFromBytes((*[secp256k1.ScalarSize]byte)(eBytes))
-
-	// Let sig = bytes(R) || bytes((k + ed) mod n).
-
-	sum := secp256k1.NewScalar().Multiply(e, d) // ed
-	sum.Add(k, sum)                             // k + ed
-	sig := make([]byte, 0, SchnorrSignatureSize)
-	sig = append(sig, rXBytes...)
-	sig = append(sig, sum.Bytes()...)
+	sig := signer.finishSignature(kPrime, R.UncompressedBytes(), msg)
 
 	// If Verify(bytes(P), m, sig) (see below) returns failure, abort[14].
 	//
@@ -390,7 +686,7 @@ func signSchnorr(auxRand *[schnorrEntropySize]byte, sk *SchnorrPrivateKey, msg [
 	// Note: Apart from the faster calculation of R, the verification
 	// process is identical to the normal verify.
 
-	if !verifySchnorrSelf(d, sk.PublicKey().Bytes(), msg, sig) {
+	if !verifySchnorrSelf(signer.d, signer.pBytes, msg, sig) {
 		// This is likely totally untestable, since it requires
 		// generating a signature that doesn't verify.
 		return nil, errSigCheckFailed
@@ -399,12 +695,46 @@ func signSchnorr(auxRand *[schnorrEntropySize]byte, sk *SchnorrPrivateKey, msg [
 	return sig, nil
 }
 
+func (signer *schnorrSigner) finishSignature(kPrime *secp256k1.Scalar, rBytes, msg []byte) []byte {
+	pBytes, d := signer.pBytes, signer.d
+	rXBytes, rYIsOdd := secp256k1.SplitUncompressedPoint(rBytes)
+
+	// Let k = k' if has_even_y(R), otherwise let k = n - k' .
+
+	k := secp256k1.NewScalar().ConditionalNegate(kPrime, rYIsOdd)
+	defer k.Zero()
+
+	// Let e = int(hashBIP0340/challenge(bytes(R) || bytes(P) || m)) mod n.
+
+	eBytes := schnorrChallengeMidstate.Sum(rXBytes, pBytes, msg)
+	e, _ := secp256k1.NewScalarFromBytes((*[secp256k1.ScalarSize]byte)(eBytes))
+
+	// Let sig = bytes(R) || bytes((k + ed) mod n).
+
+	sum := secp256k1.NewScalar().Multiply(e, d) // ed
+	sum.Add(k, sum)                             // k + ed
+	sig := make([]byte, 0, SchnorrSignatureSize)
+	sig = append(sig, rXBytes...)
+	sig = append(sig, sum.Bytes()...)
+
+	return sig
+}
+
 func verifySchnorrSelf(d *secp256k1.Scalar, pkXBytes, msg, sig []byte) bool {
-	ok, s, e, sigRXBytes := parseSchnorrSignature(pkXBytes, msg, sig)
+	R, ok := schnorrSelfCheckPoint(d, pkXBytes, msg, sig)
 	if !ok {
 		return false
 	}
 
+	return verifySchnorrSignatureR(sig[0:32], R) == nil
+}
+
+func schnorrSelfCheckPoint(d *secp256k1.Scalar, pkXBytes, msg, sig []byte) (*secp256k1.Point, bool) {
+	s, e, _, err := parseSchnorrSignature(pkXBytes, msg, sig)
+	if err != nil {
+		return nil, false
+	}
+
 	// Let R = (s - d*e)*G.
 	//
 	// Note/yawning: d is the private key (or it's negation), so deriving
@@ -412,14 +742,14 @@ func verifySchnorrSelf(d *secp256k1.Scalar, pkXBytes, msg, sig []byte) bool {
 
 	factor := secp256k1.NewScalar().Multiply(d, e)
 	factor.Subtract(s, factor)
-	R := secp256k1.NewIdentityPoint().ScalarBaseMult(factor)
+	defer factor.Zero()
 
-	return verifySchnorrSignatureR(sigRXBytes, R)
+	return secp256k1.NewIdentityPoint().ScalarBaseMult(factor), true
 }
 
-func parseSchnorrSignature(pkXBytes, msg, sig []byte) (bool, *secp256k1.Scalar, *secp256k1.Scalar, []byte) {
+func parseSchnorrSignature(pkXBytes, msg, sig []byte) (*secp256k1.Scalar, *secp256k1.Scalar, []byte, error) {
 	if len(sig) != SchnorrSignatureSize {
-		return false, nil, nil, nil
+		return nil, nil, nil, ErrBadSchnorrSigLength
 	}
 
 	// Let r = int(sig[0:32]); fail if r >= p.
@@ -430,40 +760,44 @@ func parseSchnorrSignature(pkXBytes, msg, sig []byte) (bool, *secp256k1.Scalar,
 
 	sigRXBytes := sig[0:32]
 	if !field.BytesAreCanonical((*[field.ElementSize]byte)(sigRXBytes)) {
-		return false, nil, nil, nil
+		return nil, nil, nil, ErrNonCanonicalR
 	}
 
 	// Let s = int(sig[32:64]); fail if s >= n.
 
 	s, err := secp256k1.NewScalarFromCanonicalBytes((*[secp256k1.ScalarSize]byte)(sig[32:64]))
 	if err != nil {
-		return false, nil, nil, nil
+		return nil, nil, nil, ErrOutOfRangeS
 	}
 
 	// Let e = int(hashBIP0340/challenge(bytes(r) || bytes(P) || m)) mod n.
 
-	eBytes := schnorrTaggedHash(schnorrTagChallenge, sigRXBytes, pkXBytes, msg)
+	eBytes := schnorrChallengeMidstate.Sum(sigRXBytes, pkXBytes, msg)
 	e, _ := secp256k1.NewScalarFromBytes((*[secp256k1.ScalarSize]byte)(eBytes))
 
-	return true, s, e, sigRXBytes
+	return s, e, sigRXBytes, nil
 }
 
-func verifySchnorrSignatureR(sigRXBytes []byte, R *secp256k1.Point) bool { //nolint:gocritic
+func verifySchnorrSignatureR(sigRXBytes []byte, R *secp256k1.Point) error { //nolint:gocritic
+	// Note/yawning: Doing it this way saves repeated rescaling, since
+	// the curve implementation always does the inversion.
+
+	return verifySchnorrSignatureRBytes(sigRXBytes, R.UncompressedBytes())
+}
+
+func verifySchnorrSignatureRBytes(sigRXBytes, rBytes []byte) error {
 	// Fail if is_infinite(R).
 
-	if R.IsIdentity() != 0 {
-		return false
+	if len(rBytes) != secp256k1.UncompressedPointSize {
+		return ErrSchnorrVerificationFailed
 	}
 
-	// Note/yawning: Doing it this way saves repeated rescaling, since
-	// the curve implementation always does the inversion.
-
-	rXBytes, rYIsOdd := secp256k1.SplitUncompressedPoint(R.UncompressedBytes())
+	rXBytes, rYIsOdd := secp256k1.SplitUncompressedPoint(rBytes)
 
 	// Fail if not has_even_y(R).
 
 	if rYIsOdd != 0 {
-		return false
+		return ErrSchnorrVerificationFailed
 	}
 
 	// Fail if x(R) != r.
@@ -471,8 +805,8 @@ func verifySchnorrSignatureR(sigRXBytes []byte, R *secp256k1.Point) bool { //nol
 	// Note/yawning: Vartime compare, because this is verification.
 
 	if !bytes.Equal(rXBytes, sigRXBytes) {
-		return false
+		return ErrSchnorrVerificationFailed
 	}
 
-	return true
+	return nil
 }
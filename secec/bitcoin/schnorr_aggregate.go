@@ -0,0 +1,26 @@
+// Copyright (c) 2023 Yawning Angel
+//
+// SPDX-License-Identifier: BSD-3-Clause
+
+package bitcoin
+
+// VerifyAggregatedSchnorr verifies the naively aggregated BIP-0340
+// Schnorr signature `aggSig` of `msg`, using the aggregated public key
+// `aggKey`.  Its return value records whether the signature is valid.
+//
+// The aggregation scheme is the plain sum, where `aggKey = sum(P_i)`,
+// `aggSig = bytes(sum(R_i)) || bytes(sum(s_i))`, and each signer is
+// responsible for negating their private key and nonce as required
+// so that the aggregated key and the aggregated R have even
+// Y-coordinates.  The result is an ordinary BIP-0340 signature, and
+// verification is identical to `SchnorrPublicKey.Verify`.
+//
+// WARNING: The plain-sum scheme is vulnerable to rogue-key attacks,
+// where a malicious participant picks their public key as a function
+// of the other participants' keys, and can then sign alone on behalf
+// of the group.  It is only safe if every public key is known to be
+// honestly generated (eg: a trusted setup, or verified proofs of
+// possession).  Use MuSig2 (BIP-0327) in all other cases.
+func VerifyAggregatedSchnorr(aggKey *SchnorrPublicKey, msg, aggSig []byte) bool {
+	return aggKey.Verify(msg, aggSig)
+}
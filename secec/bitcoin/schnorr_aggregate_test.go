@@ -0,0 +1,78 @@
+// Copyright (c) 2023 Yawning Angel
+//
+// SPDX-License-Identifier: BSD-3-Clause
+
+package bitcoin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gitlab.com/yawning/secp256k1-voi"
+)
+
+func TestVerifyAggregatedSchnorr(t *testing.T) {
+	const n = 3
+
+	msg := []byte(testMessage)
+
+	// Key aggregation: P = sum(d_i * G), with the d_i negated as
+	// required so that P has an even Y-coordinate.
+	ds := make([]*secp256k1.Scalar, 0, n)
+	P := secp256k1.NewIdentityPoint()
+	for i := 0; i < n; i++ {
+		sk, err := GenerateSchnorrKey()
+		require.NoError(t, err, "GenerateSchnorrKey")
+
+		d := sk.Scalar()
+		ds = append(ds, d)
+		P.Add(P, secp256k1.NewIdentityPoint().ScalarBaseMult(d))
+	}
+	negateD := P.IsYOdd()
+	P.ConditionalNegate(P, negateD)
+	for _, d := range ds {
+		d.ConditionalNegate(d, negateD)
+	}
+
+	aggKey, err := NewSchnorrPublicKeyFromPoint(P)
+	require.NoError(t, err, "NewSchnorrPublicKeyFromPoint")
+
+	// Nonce aggregation: R = sum(k_i * G), likewise negated.
+	ks := make([]*secp256k1.Scalar, 0, n)
+	R := secp256k1.NewIdentityPoint()
+	for i := 0; i < n; i++ {
+		kSk, err := GenerateSchnorrKey() // Just used to sample a random scalar.
+		require.NoError(t, err, "GenerateSchnorrKey")
+
+		k := kSk.Scalar()
+		ks = append(ks, k)
+		R.Add(R, secp256k1.NewIdentityPoint().ScalarBaseMult(k))
+	}
+	negateK := R.IsYOdd()
+	for _, k := range ks {
+		k.ConditionalNegate(k, negateK)
+	}
+	rXBytes, err := R.XBytes()
+	require.NoError(t, err, "R.XBytes")
+
+	// s = sum(k_i + e * d_i)
+	eBytes := schnorrChallengeMidstate.Sum(rXBytes, aggKey.Bytes(), msg)
+	e, _ := secp256k1.NewScalarFromBytes((*[secp256k1.ScalarSize]byte)(eBytes))
+	s := secp256k1.NewScalar()
+	for i := 0; i < n; i++ {
+		si := secp256k1.NewScalar().Multiply(e, ds[i])
+		si.Add(si, ks[i])
+		s.Add(s, si)
+	}
+
+	aggSig := append(rXBytes, s.Bytes()...) //nolint:gocritic
+	require.True(t, VerifyAggregatedSchnorr(aggKey, msg, aggSig), "VerifyAggregatedSchnorr")
+	require.False(t, VerifyAggregatedSchnorr(aggKey, []byte("not the message"), aggSig), "VerifyAggregatedSchnorr - wrong message")
+
+	// A single participant's partial signature is not valid.
+	partial := secp256k1.NewScalar().Multiply(e, ds[0])
+	partial.Add(partial, ks[0])
+	partialSig := append(rXBytes[:32:32], partial.Bytes()...)
+	require.False(t, VerifyAggregatedSchnorr(aggKey, msg, partialSig), "VerifyAggregatedSchnorr - partial")
+}
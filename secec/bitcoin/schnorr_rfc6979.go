@@ -0,0 +1,71 @@
+// Copyright (c) 2023 Yawning Angel
+//
+// SPDX-License-Identifier: BSD-3-Clause
+
+package bitcoin
+
+import (
+	"errors"
+	"io"
+
+	"gitlab.com/yawning/secp256k1-voi"
+	"gitlab.com/yawning/secp256k1-voi/internal/rfc6979"
+)
+
+const (
+	maxRFC6979Resamples = 8
+
+	// schnorrRFC6979Algo16 is the RFC 6979 additional data used to
+	// domain separate the Schnorr nonce from the ECDSA nonce.
+	schnorrRFC6979Algo16 = "Schnorr+SHA256  "
+)
+
+var (
+	errInvalidMsgSize    = errors.New("secp256k1/secec/bitcoin: invalid message size")
+	errRejectionSampling = errors.New("secp256k1/secec/bitcoin: failed rejection sampling")
+)
+
+// SignSchnorrRFC6979 signs `msg` using the SchnorrPrivateKey `k`, using
+// the signing procedure as specified in BIP-0340, except that the nonce
+// `k'` is generated as specified in RFC 6979, Section 3.2 with SHA-256,
+// with `int2octets(d')` and `bits2octets(msg)` as the inputs, and
+// `"Schnorr+SHA256  "` as the additional data `k'` (RFC 6979, Section
+// 3.6).  It returns the byte-encoded signature.  `msg` MUST be 32-bytes.
+//
+// The additional data ensures that the nonce differs from the one
+// used by RFC 6979 ECDSA for the same key and digest, as reusing a
+// nonce across the two signature schemes leaks the private key.
+//
+// WARNING: This is a deterministic, RNG-free alternative to `Sign`.
+// The resulting signatures are valid BIP-0340 signatures, but the
+// nonce derivation is specific to this library, and is NOT compatible
+// with any other implementation (including the pre-BIP-0340 Schnorr
+// signers in libsecp256k1, which used a different challenge).  `Sign`
+// is preferred in all cases where a RNG is available.
+func (k *SchnorrPrivateKey) SignSchnorrRFC6979(msg []byte) ([]byte, error) {
+	if len(msg) != secp256k1.ScalarSize {
+		return nil, errInvalidMsgSize
+	}
+
+	// bits2octets(h1) = int2octets(bits2int(h1) mod q)
+	e, _ := secp256k1.NewScalarFromBytes((*[secp256k1.ScalarSize]byte)(msg))
+
+	var (
+		drbg   = rfc6979.NewDRBG(k.dPrime, e, []byte(schnorrRFC6979Algo16))
+		tmp    [secp256k1.ScalarSize]byte
+		kPrime = secp256k1.NewScalar()
+	)
+	for i := 0; i < maxRFC6979Resamples; i++ {
+		_, _ = io.ReadFull(drbg, tmp[:]) // Can't fail.
+
+		// If that value of k is within the [1,q-1] range, ... then
+		// the generation of k is finished.
+		if _, err := kPrime.SetCanonicalBytes(&tmp); err != nil || kPrime.IsZero() != 0 {
+			continue
+		}
+
+		return signSchnorrWithNonce(kPrime, k, msg)
+	}
+
+	return nil, errRejectionSampling
+}
@@ -10,7 +10,10 @@ import (
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -76,6 +79,16 @@ func TestSchnorr(t *testing.T) {
 		_, err = PreHashSchnorrMessage("", []byte(testMessage))
 		require.ErrorIs(t, err, errInvalidDomainSep, "PreHashSchnorrMessage - no domain sep")
 
+		sig, err = priv.SignSchnorrPreHashed(nil, preHashedMsg)
+		require.NoError(t, err, "SignSchnorrPreHashed")
+		require.True(t, pub.VerifyPreHashed(preHashedMsg, sig), "VerifyPreHashed")
+		require.True(t, pub.Verify(preHashedMsg, sig), "Verify - pre-hashed sig")
+		require.False(t, pub.VerifyPreHashed(preHashedMsg[:31], sig), "VerifyPreHashed - truncated digest")
+		require.False(t, pub.Verify([]byte(testMessage), sig), "Verify - raw msg")
+
+		_, err = priv.SignSchnorrPreHashed(nil, []byte(testMessage))
+		require.ErrorIs(t, err, errInvalidDigest, "SignSchnorrPreHashed - bad digest")
+
 		require.False(t, priv.Equal(privNist), "priv.Equal(privNist)")
 		require.False(t, pub.Equal(pubNist), "pub.Equal(pubNist)")
 
@@ -88,6 +101,9 @@ func TestSchnorr(t *testing.T) {
 	})
 
 	t.Run("TestVectors", testSchnorrKAT)
+	t.Run("SelfTest", func(t *testing.T) {
+		require.NoError(t, SelfTest(), "SelfTest")
+	})
 
 	t.Run("PublicKey/Invalid", func(t *testing.T) {
 		k, err := NewSchnorrPublicKey([]byte{0x45, 0x45, 0x45, 0x45})
@@ -101,8 +117,288 @@ func TestSchnorr(t *testing.T) {
 		require.PanicsWithValue(t, errAIsUninitialized, func() {
 			new(SchnorrPublicKey).Bytes()
 		}, "uninitialized.Bytes()")
+		require.PanicsWithValue(t, errAIsUninitialized, func() {
+			new(SchnorrPublicKey).CompressedBytes()
+		}, "uninitialized.CompressedBytes()")
+	})
+
+	t.Run("PublicKey/CompressedBytes", func(t *testing.T) {
+		priv, err := secec.GenerateKey()
+		require.NoError(t, err, "GenerateKey")
+
+		pub := NewSchnorrPublicKeyFromECDSA(priv.PublicKey())
+		b := pub.CompressedBytes()
+		require.Len(t, b, secp256k1.CompressedPointSize, "CompressedBytes - length")
+		require.EqualValues(t, 0x02, b[0], "CompressedBytes - even Y")
+		require.Equal(t, pub.Bytes(), b[1:], "CompressedBytes - x")
+
+		pt, err := secp256k1.NewPointFromBytes(b)
+		require.NoError(t, err, "NewPointFromBytes(CompressedBytes)")
+		require.EqualValues(t, 1, pt.Equal(pub.Point()), "CompressedBytes == Point")
+	})
+
+	t.Run("VerifyDual", func(t *testing.T) {
+		priv, err := secec.GenerateKey()
+		require.NoError(t, err, "GenerateKey")
+
+		pub := priv.PublicKey()
+		schnorrPriv := NewSchnorrPrivateKeyFromECDSA(priv)
+
+		msg := []byte(testMessage)
+		msgHash := sha256.Sum256(msg)
+
+		ecdsaSig, err := priv.Sign(rand.Reader, msgHash[:], nil)
+		require.NoError(t, err, "Sign - ECDSA")
+		schnorrSig, err := schnorrPriv.Sign(rand.Reader, msg, nil)
+		require.NoError(t, err, "Sign - Schnorr")
+
+		ok := VerifyDual(pub, msg, ecdsaSig, schnorrSig, crypto.SHA256)
+		require.True(t, ok, "VerifyDual")
+
+		ok = VerifyDual(pub, msg, ecdsaSig, schnorrSig, crypto.SHA512)
+		require.False(t, ok, "VerifyDual - wrong hash")
+
+		ok = VerifyDual(pub, []byte("not the message"), ecdsaSig, schnorrSig, crypto.SHA256)
+		require.False(t, ok, "VerifyDual - wrong message")
+
+		otherPriv, err := GenerateSchnorrKey()
+		require.NoError(t, err, "GenerateSchnorrKey")
+		otherSig, err := otherPriv.Sign(rand.Reader, msg, nil)
+		require.NoError(t, err, "Sign - Schnorr (other key)")
+
+		ok = VerifyDual(pub, msg, ecdsaSig, otherSig, crypto.SHA256)
+		require.False(t, ok, "VerifyDual - Schnorr key mismatch")
+
+		ok = VerifyDual(pub, msg, schnorrSig, schnorrSig, crypto.SHA256)
+		require.False(t, ok, "VerifyDual - bad ECDSA sig")
+	})
+
+	t.Run("VerifyError", func(t *testing.T) {
+		priv, err := GenerateSchnorrKey()
+		require.NoError(t, err, "GenerateSchnorrKey")
+		pub := priv.PublicKey()
+
+		msg := []byte(testMessage)
+		sig, err := priv.Sign(nil, msg, nil)
+		require.NoError(t, err, "Sign")
+
+		require.NoError(t, pub.VerifyError(msg, sig), "VerifyError")
+
+		err = pub.VerifyError(msg, sig[:SchnorrSignatureSize-1])
+		require.ErrorIs(t, err, ErrBadSchnorrSigLength, "VerifyError - truncated")
+
+		tmp := bytes.Clone(sig)
+		copy(tmp[:32], bytes.Repeat([]byte{0xff}, 32))
+		err = pub.VerifyError(msg, tmp)
+		require.ErrorIs(t, err, ErrNonCanonicalR, "VerifyError - r >= p")
+
+		tmp = bytes.Clone(sig)
+		copy(tmp[32:], bytes.Repeat([]byte{0xff}, 32))
+		err = pub.VerifyError(msg, tmp)
+		require.ErrorIs(t, err, ErrOutOfRangeS, "VerifyError - s >= n")
+
+		err = pub.VerifyError([]byte("not the message"), sig)
+		require.ErrorIs(t, err, ErrSchnorrVerificationFailed, "VerifyError - wrong message")
+	})
+	t.Run("NonceCommit", func(t *testing.T) {
+		priv, err := GenerateSchnorrKey()
+		require.NoError(t, err, "GenerateSchnorrKey")
+		pub := priv.PublicKey()
+
+		msg := []byte(testMessage)
+		for i := 0; i < 8; i++ {
+			var aux [schnorrEntropySize]byte
+			_, _ = rand.Read(aux[:])
+
+			R, nonce, err := priv.NonceCommit(&aux, msg)
+			require.NoError(t, err, "[%d]: NonceCommit", i)
+			require.EqualValues(t, 0, R.IsYOdd(), "[%d]: NonceCommit - R has even Y", i)
+			require.EqualValues(t, 1, R.Equal(secp256k1.NewIdentityPoint().ScalarBaseMult(nonce)), "[%d]: NonceCommit - R = k*G", i)
+
+			sig, err := priv.SignWithNonce(nonce, msg)
+			require.NoError(t, err, "[%d]: SignWithNonce", i)
+			require.True(t, pub.Verify(msg, sig), "[%d]: Verify", i)
+
+			// Identical to Sign, given the same randomness.
+			expected, err := priv.Sign(bytes.NewReader(aux[:]), msg, nil)
+			require.NoError(t, err, "[%d]: Sign", i)
+			require.Equal(t, expected, sig, "[%d]: SignWithNonce == Sign", i)
+			require.Equal(t, R.UncompressedBytes()[1:33], sig[:32], "[%d]: sig.R == R", i)
+		}
+
+		_, err = priv.SignWithNonce(secp256k1.NewScalar(), msg)
+		require.ErrorIs(t, err, errKPrimeIsZero, "SignWithNonce - zero nonce")
 	})
+	t.Run("SignBatch", func(t *testing.T) {
+		priv, err := GenerateSchnorrKey()
+		require.NoError(t, err, "GenerateSchnorrKey")
+		pub := priv.PublicKey()
+
+		msgs := [][]byte{
+			[]byte(testMessage),
+			[]byte("not the message"),
+			{},
+			[]byte(testMessage),
+		}
+		sigs, err := priv.SignBatch(nil, msgs)
+		require.NoError(t, err, "SignBatch")
+		require.Len(t, sigs, len(msgs), "SignBatch")
+		for i, sig := range sigs {
+			require.True(t, pub.Verify(msgs[i], sig), "[%d]: Verify", i)
+		}
+		require.NotEqual(t, sigs[0], sigs[3], "SignBatch - independent aux")
+
+		// Identical to Sign, given the derived auxiliary randomness.
+		var seed [schnorrEntropySize]byte
+		_, _ = rand.Read(seed[:])
+		sigs, err = priv.SignBatch(bytes.NewReader(seed[:]), msgs)
+		require.NoError(t, err, "SignBatch - fixed seed")
+		for i := range sigs {
+			auxRand := schnorrBatchAuxRand(&seed, i)
+			sig, err := priv.Sign(bytes.NewReader(auxRand[:]), msgs[i], nil)
+			require.NoError(t, err, "[%d]: Sign - derived aux", i)
+			require.Equal(t, sig, sigs[i], "[%d]: SignBatch == Sign", i)
+		}
+		require.NotEqual(t, sigs[0], sigs[3], "SignBatch - fixed seed, independent aux")
+
+		sigs, err = priv.SignBatch(bytes.NewReader(seed[:schnorrEntropySize-1]), msgs)
+		require.Nil(t, sigs, "SignBatch - short rand")
+		require.ErrorIs(t, err, errEntropySource, "SignBatch - short rand")
+	})
+
+	t.Run("VerifySchnorrAllOrNothing", func(t *testing.T) {
+		var (
+			keys []*SchnorrPublicKey
+			msgs [][]byte
+			sigs [][]byte
+		)
+		for i := 0; i < 4; i++ {
+			priv, err := GenerateSchnorrKey()
+			require.NoError(t, err, "GenerateSchnorrKey")
+
+			msg := []byte(fmt.Sprintf("%s %d", testMessage, i))
+			sig, err := priv.Sign(nil, msg, nil)
+			require.NoError(t, err, "Sign")
+
+			keys = append(keys, priv.PublicKey())
+			msgs = append(msgs, msg)
+			sigs = append(sigs, sig)
+		}
+
+		idx, ok := VerifySchnorrAllOrNothing(keys, msgs, sigs)
+		require.True(t, ok, "VerifySchnorrAllOrNothing")
+		require.Equal(t, -1, idx, "VerifySchnorrAllOrNothing")
+
+		idx, ok = VerifySchnorrAllOrNothing(nil, nil, nil)
+		require.True(t, ok, "VerifySchnorrAllOrNothing - empty")
+		require.Equal(t, -1, idx, "VerifySchnorrAllOrNothing - empty")
+
+		badSigs := append([][]byte{}, sigs...)
+		badSigs[2], badSigs[3] = sigs[3], sigs[2]
+		idx, ok = VerifySchnorrAllOrNothing(keys, msgs, badSigs)
+		require.False(t, ok, "VerifySchnorrAllOrNothing - bad sig")
+		require.Equal(t, 2, idx, "VerifySchnorrAllOrNothing - bad sig")
+
+		idx, ok = VerifySchnorrAllOrNothing(keys, msgs, sigs[:3])
+		require.False(t, ok, "VerifySchnorrAllOrNothing - length mismatch")
+		require.Equal(t, 3, idx, "VerifySchnorrAllOrNothing - length mismatch")
+	})
+	t.Run("SignSchnorrRFC6979", func(t *testing.T) {
+		ecdsaPriv, err := secec.GenerateKey()
+		require.NoError(t, err, "GenerateKey")
 
+		priv := NewSchnorrPrivateKeyFromECDSA(ecdsaPriv)
+		pub := priv.PublicKey()
+
+		msgHash := sha256.Sum256([]byte(testMessage))
+
+		sig, err := priv.SignSchnorrRFC6979(msgHash[:])
+		require.NoError(t, err, "SignSchnorrRFC6979")
+		require.True(t, pub.Verify(msgHash[:], sig), "Verify")
+
+		sig2, err := priv.SignSchnorrRFC6979(msgHash[:])
+		require.NoError(t, err, "SignSchnorrRFC6979 - again")
+		require.Equal(t, sig, sig2, "SignSchnorrRFC6979 - deterministic")
+
+		// The nonce is domain separated from what RFC 6979 ECDSA would
+		// use, so x(R) MUST NOT match ECDSA's r, as that would leak
+		// the private key.
+		r, _, _, err := ecdsaPriv.SignRaw(secec.RFC6979SHA256(), msgHash[:])
+		require.NoError(t, err, "SignRaw - RFC6979SHA256")
+		require.NotEqual(t, r.Bytes(), sig[:32], "SignSchnorrRFC6979 - R differs from ECDSA r")
+
+		otherHash := sha256.Sum256([]byte("not the message"))
+		sig2, err = priv.SignSchnorrRFC6979(otherHash[:])
+		require.NoError(t, err, "SignSchnorrRFC6979 - other message")
+		require.NotEqual(t, sig, sig2, "SignSchnorrRFC6979 - other message")
+
+		sig, err = priv.SignSchnorrRFC6979([]byte(testMessage))
+		require.Nil(t, sig, "SignSchnorrRFC6979 - not a digest")
+		require.ErrorIs(t, err, errInvalidMsgSize, "SignSchnorrRFC6979 - not a digest")
+
+		// Regression vectors, generated by this implementation, as
+		// there is no other implementation of this nonce derivation.
+		for i, vec := range []struct {
+			secretKey string
+			msg       string
+			sig       string
+		}{
+			{
+				"0000000000000000000000000000000000000000000000000000000000000003",
+				"0000000000000000000000000000000000000000000000000000000000000000",
+				"364F6234CB089107C80F485617C1F4762FF49C6D5AA99D1EE93378E852D48AE146390C44A7D2FD79EACA0ECB166A8873497E369FDE6E1689157853A9ED30556F",
+			},
+			{
+				"B7E151628AED2A6ABF7158809CF4F3C762E7160F38B4DA56A784D9045190CFEF",
+				"243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89",
+				"E87CEC707424360691EBF78B40BE9BF5FBCFCF4CDA8A9E49FB1A550FE00DFB60686E6C386764F9CF94649E0AF3A81A96E077C7EC8C32D0C92BAEED9CEF98C1B9",
+			},
+			{
+				"C90FDAA22168C234C4C6628B80DC1CD129024E088A67CC74020BBEA63B14E5C9",
+				"7E2D58D8B3BCDF1ABADEC7829054F90DDA9805AAB56C77333024B9D0A508B75C",
+				"F1F8CA562DBB4504E1A0290A9E9ECA131D8A61628273E50C1B0F5479335B87AC08A587948A0F171E5BA46260828A522256034B70D9C5F91175CA8E068FA1A6AE",
+			},
+			{
+				"FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEBAAEDCE6AF48A03BBFD25E8CD0364140",
+				"FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF",
+				"A6F805A97E07423FB1A864228850FF023F558F0418F9E8FE1B7457E3A3C4633657B3490848736975E893237C21AB193F3932AB6DD3FD57DE98C0144022ADEAE7",
+			},
+		} {
+			priv, err := NewSchnorrPrivateKey(helpers.MustBytesFromHex(vec.secretKey))
+			require.NoError(t, err, "[%d]: NewSchnorrPrivateKey", i)
+
+			msg := helpers.MustBytesFromHex(vec.msg)
+			sig, err := priv.SignSchnorrRFC6979(msg)
+			require.NoError(t, err, "[%d]: SignSchnorrRFC6979", i)
+			require.Equal(t, helpers.MustBytesFromHex(vec.sig), sig, "[%d]: SignSchnorrRFC6979", i)
+			require.True(t, priv.PublicKey().Verify(msg, sig), "[%d]: Verify", i)
+		}
+	})
+
+	t.Run("Zeroize", func(t *testing.T) {
+		priv, err := GenerateSchnorrKey()
+		require.NoError(t, err, "GenerateSchnorrKey")
+
+		msg := []byte(testMessage)
+		msgHash := sha256.Sum256(msg)
+
+		priv.Zeroize()
+		require.EqualValues(t, 1, priv.dPrime.IsZero(), "Zeroize - d'")
+		require.EqualValues(t, 1, priv.d.IsZero(), "Zeroize - d")
+
+		_, err = priv.Sign(nil, msg, nil)
+		require.ErrorIs(t, err, errZeroizedKey, "Sign")
+		_, err = priv.SignSchnorrRFC6979(msgHash[:])
+		require.ErrorIs(t, err, errZeroizedKey, "SignSchnorrRFC6979")
+		_, _, err = priv.NonceCommit(&[schnorrEntropySize]byte{}, msg)
+		require.ErrorIs(t, err, errZeroizedKey, "NonceCommit")
+		_, err = priv.SignWithNonce(secp256k1.NewScalarFromUint64(69), msg)
+		require.ErrorIs(t, err, errZeroizedKey, "SignWithNonce")
+		_, err = priv.TaprootTweak(nil)
+		require.ErrorIs(t, err, errZeroizedKey, "TaprootTweak")
+		require.PanicsWithError(t, errZeroizedKey.Error(), func() { priv.Bytes() }, "Bytes")
+		require.PanicsWithError(t, errZeroizedKey.Error(), func() { priv.Scalar() }, "Scalar")
+	})
 	t.Run("BadRNG", func(t *testing.T) {
 		priv, err := GenerateSchnorrKey()
 		require.NoError(t, err, "GenerateSchnorrKey")
@@ -135,6 +431,32 @@ func BenchmarkSchnorr(b *testing.B) {
 			_, _ = randomPriv.Sign(rand.Reader, preHashedMsg, nil)
 		}
 	})
+	b.Run("SignBatch", func(b *testing.B) {
+		const batchSize = 16
+		msgs := make([][]byte, 0, batchSize)
+		for i := 0; i < batchSize; i++ {
+			msgs = append(msgs, preHashedMsg)
+		}
+
+		b.Run("Loop", func(b *testing.B) {
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				for _, msg := range msgs {
+					_, _ = randomPriv.Sign(rand.Reader, msg, nil)
+				}
+			}
+		})
+		b.Run("Batch", func(b *testing.B) {
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				_, _ = randomPriv.SignBatch(rand.Reader, msgs)
+			}
+		})
+	})
 	b.Run("Verify", func(b *testing.B) {
 		b.ReportAllocs()
 		b.ResetTimer()
@@ -241,6 +563,15 @@ func testSchnorrKAT(t *testing.T) {
 			derivedSig, err := signSchnorr(auxRandBytes, sk, msgBytes)
 			require.NoError(t, err, "signSchnorr")
 			require.EqualValues(t, sigBytes, derivedSig)
+
+			derivedVec, err := sk.SchnorrVector(auxRandBytes, msgBytes)
+			require.NoError(t, err, "SchnorrVector")
+			derivedVec.Comment = vec[fieldComment]
+			require.EqualValues(t, vec, derivedVec.CSVRecord(i), "SchnorrVector.CSVRecord")
+
+			jsonBytes, err := json.Marshal(derivedVec)
+			require.NoError(t, err, "json.Marshal(SchnorrVector)")
+			require.Contains(t, string(jsonBytes), hex.EncodeToString(sigBytes), "json.Marshal(SchnorrVector)")
 		})
 	}
 }
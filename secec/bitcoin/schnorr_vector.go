@@ -0,0 +1,96 @@
+// Copyright (c) 2023 Yawning Angel
+//
+// SPDX-License-Identifier: BSD-3-Clause
+
+package bitcoin
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+const (
+	csvResultPass = "TRUE"
+	csvResultFail = "FALSE"
+)
+
+// SchnorrVector is a BIP-0340 Schnorr signature test vector.
+type SchnorrVector struct {
+	SecretKey          []byte
+	PublicKey          []byte
+	AuxRand            []byte
+	Message            []byte
+	Signature          []byte
+	VerificationResult bool
+	Comment            string
+}
+
+// CSVRecord returns the test vector as a CSV record, in the format
+// used by the BIP-0340 reference test vectors, with the index `idx`.
+func (vec *SchnorrVector) CSVRecord(idx int) []string {
+	result := csvResultFail
+	if vec.VerificationResult {
+		result = csvResultPass
+	}
+
+	toHex := func(b []byte) string {
+		return strings.ToUpper(hex.EncodeToString(b))
+	}
+
+	return []string{
+		strconv.Itoa(idx),
+		toHex(vec.SecretKey),
+		toHex(vec.PublicKey),
+		toHex(vec.AuxRand),
+		toHex(vec.Message),
+		toHex(vec.Signature),
+		result,
+		vec.Comment,
+	}
+}
+
+// MarshalJSON returns the JSON encoding of the test vector, with all
+// of the byte-string fields hex encoded.
+func (vec *SchnorrVector) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&struct {
+		SecretKey          string `json:"secret_key"`
+		PublicKey          string `json:"public_key"`
+		AuxRand            string `json:"aux_rand"`
+		Message            string `json:"message"`
+		Signature          string `json:"signature"`
+		VerificationResult bool   `json:"verification_result"`
+		Comment            string `json:"comment,omitempty"`
+	}{
+		SecretKey:          hex.EncodeToString(vec.SecretKey),
+		PublicKey:          hex.EncodeToString(vec.PublicKey),
+		AuxRand:            hex.EncodeToString(vec.AuxRand),
+		Message:            hex.EncodeToString(vec.Message),
+		Signature:          hex.EncodeToString(vec.Signature),
+		VerificationResult: vec.VerificationResult,
+		Comment:            vec.Comment,
+	})
+}
+
+// SchnorrVector signs `msg` using the SchnorrPrivateKey `k` and the
+// auxiliary randomness `auxRand`, and returns the resulting BIP-0340
+// test vector.
+//
+// WARNING: The returned vector includes the private key, and this
+// should only ever be used with test keys.
+func (k *SchnorrPrivateKey) SchnorrVector(auxRand *[schnorrEntropySize]byte, msg []byte) (*SchnorrVector, error) {
+	sig, err := signSchnorr(auxRand, k, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SchnorrVector{
+		SecretKey:          k.Bytes(),
+		PublicKey:          k.PublicKey().Bytes(),
+		AuxRand:            append([]byte{}, auxRand[:]...),
+		Message:            append([]byte{}, msg...),
+		Signature:          sig,
+		VerificationResult: k.PublicKey().Verify(msg, sig),
+	}, nil
+}
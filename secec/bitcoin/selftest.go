@@ -0,0 +1,118 @@
+// Copyright (c) 2023 Yawning Angel
+//
+// SPDX-License-Identifier: BSD-3-Clause
+
+package bitcoin
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"gitlab.com/yawning/secp256k1-voi"
+	"gitlab.com/yawning/secp256k1-voi/internal/helpers"
+	"gitlab.com/yawning/secp256k1-voi/secec"
+)
+
+const selfTestDomainSepMessage = "secp256k1-voi/secec/bitcoin/SelfTest"
+
+var (
+	errSelfTestSchnorr   = errors.New("secp256k1/secec/bitcoin: self-test failed: BIP-0340 known-answer")
+	errSelfTestDomainSep = errors.New("secp256k1/secec/bitcoin: self-test failed: ECDSA/Schnorr nonce domain separation")
+)
+
+// SelfTest runs a set of known-answer and consistency checks, including
+// `secec.SelfTest`, suitable for use as a power-on self-test, and
+// returns nil iff all of the checks pass.
+func SelfTest() error {
+	if err := secec.SelfTest(); err != nil {
+		return err
+	}
+
+	if err := selfTestSchnorr(); err != nil {
+		return fmt.Errorf("%w: %w", errSelfTestSchnorr, err)
+	}
+	if err := selfTestDomainSep(); err != nil {
+		return fmt.Errorf("%w: %w", errSelfTestDomainSep, err)
+	}
+
+	return nil
+}
+
+func selfTestSchnorr() error {
+	// BIP-0340 test vector 1.
+	var (
+		sk         = helpers.MustBytesFromHex("b7e151628aed2a6abf7158809cf4f3c762e7160f38b4da56a784d9045190cfef")
+		auxRand    = helpers.MustBytesFromHex("0000000000000000000000000000000000000000000000000000000000000001")
+		msg        = helpers.MustBytesFromHex("243f6a8885a308d313198a2e03707344a4093822299f31d0082efa98ec4e6c89")
+		expectedPk = helpers.MustBytesFromHex("dff1d77f2a671c5f36183726db2341be58feae1da2deced843240f7b502ba659")
+		expected   = helpers.MustBytesFromHex("6896bd60eeae296db48a229ff71dfe071bde413e6d43f917dc8dcf8c78de33418906d11ac976abccb20b091292bff4ea897efcb639ea871cfa95f6de339e4b0a")
+	)
+
+	priv, err := NewSchnorrPrivateKey(sk)
+	if err != nil {
+		return err
+	}
+	pub := priv.PublicKey()
+	if !bytes.Equal(pub.Bytes(), expectedPk) {
+		return errors.New("public key mismatch")
+	}
+
+	sig, err := signSchnorr((*[schnorrEntropySize]byte)(auxRand), priv, msg)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(sig, expected) {
+		return errors.New("signature mismatch")
+	}
+
+	if !pub.Verify(msg, sig) {
+		return errors.New("valid signature rejected")
+	}
+	msg[0] ^= 0x01
+	if pub.Verify(msg, sig) {
+		return errors.New("invalid signature accepted")
+	}
+
+	return nil
+}
+
+func selfTestDomainSep() error {
+	// Signing the same digest with the same key using ECDSA and
+	// Schnorr MUST NOT reuse the nonce, as that leaks the private key.
+	ecdsaPriv, err := secec.NewPrivateKeyFromScalar(secp256k1.NewScalarFromUint64(1))
+	if err != nil {
+		return err
+	}
+	priv := NewSchnorrPrivateKeyFromECDSA(ecdsaPriv)
+
+	digest := sha256.Sum256([]byte(selfTestDomainSepMessage))
+	ecdsaR, _, _, err := ecdsaPriv.SignRaw(secec.RFC6979SHA256(), digest[:])
+	if err != nil {
+		return err
+	}
+
+	var auxRand [schnorrEntropySize]byte
+	sigBIP340, err := signSchnorr(&auxRand, priv, digest[:])
+	if err != nil {
+		return err
+	}
+	sigRFC6979, err := priv.SignSchnorrRFC6979(digest[:])
+	if err != nil {
+		return err
+	}
+
+	for _, sig := range [][]byte{sigBIP340, sigRFC6979} {
+		// r = x(R) mod n, bytes(R) = x(R)
+		schnorrR, _ := secp256k1.NewScalarFromBytes((*[secp256k1.ScalarSize]byte)(sig[:secp256k1.ScalarSize]))
+		if schnorrR.Equal(ecdsaR) == 1 {
+			return errors.New("nonces are identical")
+		}
+	}
+	if bytes.Equal(sigBIP340[:secp256k1.ScalarSize], sigRFC6979[:secp256k1.ScalarSize]) {
+		return errors.New("Schnorr nonces are identical")
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2023 Yawning Angel
+//
+// SPDX-License-Identifier: BSD-3-Clause
+
+package bitcoin
+
+import (
+	"errors"
+
+	"gitlab.com/yawning/secp256k1-voi"
+	"gitlab.com/yawning/secp256k1-voi/internal/taggedhash"
+	"gitlab.com/yawning/secp256k1-voi/secec"
+)
+
+const schnorrTagTapTweak = "TapTweak"
+
+var (
+	errInvalidTweak  = errors.New("secp256k1/secec/bitcoin: tweak >= n")
+	errQIsInfinity   = errors.New("secp256k1/secec/bitcoin: tweaked public key is the point at infinity")
+	errInvalidMRSize = errors.New("secp256k1/secec/bitcoin: invalid merkle root")
+)
+
+// TweakKeyPathOnly computes the BIP-0341 Taproot output key for the
+// key-path only case, where there is no script path (ie: no merkle
+// root is committed to).  It returns the output key, and if the
+// Y-coordinate of the output key before the implicit even-Y fixup
+// was odd (the parity bit in the control block).
+func (k *SchnorrPublicKey) TweakKeyPathOnly() (*SchnorrPublicKey, bool, error) {
+	return taprootTweakPublicKey(k, nil)
+}
+
+// TweakAdd computes the BIP-0341 Taproot output key, committing to the
+// script tree merkle root `merkleRoot`, which MUST be either empty
+// (the key-path only case) or 32-bytes.  The output key is
+// `Q = P + int(hash_TapTweak(bytes(P) || merkleRoot)) * G`, with the
+// implicit even-Y fixup applied.
+func (k *SchnorrPublicKey) TweakAdd(merkleRoot []byte) (*SchnorrPublicKey, error) {
+	pub, _, err := taprootTweakPublicKey(k, merkleRoot)
+	return pub, err
+}
+
+// TaprootTweak computes the BIP-0341 Taproot output private key,
+// committing to the script tree merkle root `merkleRoot`, which MUST
+// be either empty (the key-path only case) or 32-bytes.  The public
+// key of the returned private key is `k.PublicKey().TweakAdd(merkleRoot)`.
+func (k *SchnorrPrivateKey) TaprootTweak(merkleRoot []byte) (*SchnorrPrivateKey, error) {
+	if k.isZeroized() {
+		return nil, errZeroizedKey
+	}
+	if len(merkleRoot) != 0 && len(merkleRoot) != 32 {
+		return nil, errInvalidMRSize
+	}
+
+	// seckey0 = int_from_bytes(seckey0)
+	// P = point_mul(G, seckey0)
+	// seckey = seckey0 if has_even_y(P) else SECP256K1_ORDER - seckey0
+	//
+	// Note: k.d is seckey.
+	//
+	// t = int_from_bytes(tagged_hash("TapTweak", bytes_from_int(x(P)) + h))
+	// if t >= SECP256K1_ORDER:
+	//     raise ValueError
+	tBytes := taggedhash.Sum(schnorrTagTapTweak, k.publicKey.xBytes, merkleRoot)
+	t, err := secp256k1.NewScalarFromCanonicalBytes((*[secp256k1.ScalarSize]byte)(tBytes))
+	if err != nil {
+		return nil, errInvalidTweak
+	}
+
+	// return bytes_from_int((seckey + t) % SECP256K1_ORDER)
+	t.Add(t, k.d)
+	defer t.Zero()
+
+	sk, err := secec.NewPrivateKeyFromScalar(t)
+	if err != nil {
+		// seckey + t = 0 iff Q is the point at infinity.
+		return nil, errQIsInfinity
+	}
+
+	return NewSchnorrPrivateKeyFromECDSA(sk), nil
+}
+
+// taprootTweakPublicKey implements taproot_tweak_pubkey from BIP-0341,
+// with a nil `h` denoting the key-path only case.
+func taprootTweakPublicKey(k *SchnorrPublicKey, h []byte) (*SchnorrPublicKey, bool, error) {
+	if k.xBytes == nil {
+		return nil, false, errAIsUninitialized
+	}
+
+	// The BIP pseudocode is silent as to the length of `h`, but
+	// in practice it is either empty or a 32-byte merkle root.
+	if len(h) != 0 && len(h) != 32 {
+		return nil, false, errInvalidMRSize
+	}
+
+	// t = int_from_bytes(tagged_hash("TapTweak", pubkey + h))
+	// if t >= SECP256K1_ORDER:
+	//     raise ValueError
+	tBytes := taggedhash.Sum(schnorrTagTapTweak, k.xBytes, h)
+	t, err := secp256k1.NewScalarFromCanonicalBytes((*[secp256k1.ScalarSize]byte)(tBytes))
+	if err != nil {
+		return nil, false, errInvalidTweak
+	}
+
+	// P = lift_x(int_from_bytes(pubkey))
+	// Q = point_add(P, point_mul(G, t))
+	Q := secp256k1.NewIdentityPoint().ScalarBaseMult(t)
+	Q.Add(Q, k.point)
+	if Q.IsIdentity() != 0 {
+		return nil, false, errQIsInfinity
+	}
+
+	// return 0 if has_even_y(Q) else 1, bytes_from_int(x(Q))
+	isYOdd := Q.IsYOdd() == 1
+	pub, _ := NewSchnorrPublicKeyFromPoint(Q) // Can't fail, Q != Inf.
+
+	return pub, isYOdd, nil
+}
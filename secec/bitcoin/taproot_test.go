@@ -0,0 +1,114 @@
+// Copyright (c) 2023 Yawning Angel
+//
+// SPDX-License-Identifier: BSD-3-Clause
+
+package bitcoin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gitlab.com/yawning/secp256k1-voi"
+	"gitlab.com/yawning/secp256k1-voi/internal/helpers"
+	"gitlab.com/yawning/secp256k1-voi/internal/taggedhash"
+)
+
+func TestTaproot(t *testing.T) {
+	t.Run("TweakKeyPathOnly", func(t *testing.T) {
+		// BIP-0341 wallet test vectors, scriptPubKey[0].
+		internalKey := helpers.MustBytesFromHex("d6889cb081036e0faefa3a35157ad71086b123b2b144b649798b494c300a961d")
+		expectedKey := helpers.MustBytesFromHex("53a1f6e454df1aa2776a2814a721372d6258050de330b3c6d10ee8f4e0dda343")
+
+		pub, err := NewSchnorrPublicKey(internalKey)
+		require.NoError(t, err, "NewSchnorrPublicKey")
+
+		q, isYOdd, err := pub.TweakKeyPathOnly()
+		require.NoError(t, err, "TweakKeyPathOnly")
+		require.Equal(t, expectedKey, q.Bytes(), "TweakKeyPathOnly")
+
+		// Recompute Q without the even-Y fixup, to check the parity.
+		tBytes := taggedhash.Sum(schnorrTagTapTweak, internalKey)
+		tweak, err := secp256k1.NewScalarFromCanonicalBytes((*[secp256k1.ScalarSize]byte)(tBytes))
+		require.NoError(t, err, "NewScalarFromCanonicalBytes")
+		Q := secp256k1.NewIdentityPoint().ScalarBaseMult(tweak)
+		Q.Add(Q, pub.Point())
+		require.Equal(t, Q.IsYOdd() == 1, isYOdd, "TweakKeyPathOnly - parity")
+
+		// The key-path only case is the same as an empty merkle root.
+		q2, isYOdd2, err := taprootTweakPublicKey(pub, []byte{})
+		require.NoError(t, err, "taprootTweakPublicKey - empty")
+		require.True(t, q.Equal(q2), "taprootTweakPublicKey - empty")
+		require.Equal(t, isYOdd, isYOdd2, "taprootTweakPublicKey - empty parity")
+
+		_, _, err = taprootTweakPublicKey(pub, []byte{0x69})
+		require.ErrorIs(t, err, errInvalidMRSize, "taprootTweakPublicKey - bad merkle root")
+
+		_, _, err = new(SchnorrPublicKey).TweakKeyPathOnly()
+		require.ErrorIs(t, err, errAIsUninitialized, "uninitialized.TweakKeyPathOnly()")
+	})
+
+	t.Run("TweakAdd", func(t *testing.T) {
+		// BIP-0341 wallet test vectors, scriptPubKey.
+		for i, vec := range []struct {
+			internalKey string
+			merkleRoot  string
+			expectedKey string
+		}{
+			{
+				"d6889cb081036e0faefa3a35157ad71086b123b2b144b649798b494c300a961d",
+				"",
+				"53a1f6e454df1aa2776a2814a721372d6258050de330b3c6d10ee8f4e0dda343",
+			},
+			{
+				"187791b6f712a8ea41c8ecdd0ee77fab3e85263b37e1ec18a3651926b3a6cf27",
+				"5b75adecf53548f3ec6ad7d78383bf84cc57b55a3127c72b9a2481752dd88b21",
+				"147c9c57132f6e7ecddba9800bb0c4449251c92a1e60371ee77557b6620f3ea3",
+			},
+		} {
+			pub, err := NewSchnorrPublicKey(helpers.MustBytesFromHex(vec.internalKey))
+			require.NoError(t, err, "[%d]: NewSchnorrPublicKey", i)
+
+			q, err := pub.TweakAdd(helpers.MustBytesFromHex(vec.merkleRoot))
+			require.NoError(t, err, "[%d]: TweakAdd", i)
+			require.Equal(t, helpers.MustBytesFromHex(vec.expectedKey), q.Bytes(), "[%d]: TweakAdd", i)
+		}
+	})
+
+	t.Run("TaprootTweak", func(t *testing.T) {
+		// BIP-0341 wallet test vectors, keyPathSpending.
+		internalPrivKey := helpers.MustBytesFromHex("6b973d88838f27366ed61c9ad6367663045cb456e28335c109e30717ae0c6baa")
+		expectedPrivKey := helpers.MustBytesFromHex("2405b971772ad26915c8dcdf10f238753a9b837e5f8e6a86fd7c0cce5b7296d9")
+
+		sk, err := NewSchnorrPrivateKey(internalPrivKey)
+		require.NoError(t, err, "NewSchnorrPrivateKey")
+
+		tweakedSk, err := sk.TaprootTweak(nil)
+		require.NoError(t, err, "TaprootTweak")
+		require.Equal(t, expectedPrivKey, tweakedSk.Scalar().Bytes(), "TaprootTweak")
+
+		// The public and private tweaks must be consistent, for
+		// both internal key parities.
+		msg := []byte("key-path spend")
+		for i := 0; i < 8; i++ {
+			sk, err := GenerateSchnorrKey()
+			require.NoError(t, err, "GenerateSchnorrKey")
+
+			merkleRoot := make([]byte, 32)
+			merkleRoot[0] = byte(i)
+
+			tweakedSk, err := sk.TaprootTweak(merkleRoot)
+			require.NoError(t, err, "TaprootTweak")
+			q, err := sk.PublicKey().TweakAdd(merkleRoot)
+			require.NoError(t, err, "TweakAdd")
+			require.True(t, q.Equal(tweakedSk.PublicKey()), "tweaked public keys should match")
+
+			sig, err := tweakedSk.Sign(nil, msg, nil)
+			require.NoError(t, err, "Sign")
+			require.True(t, q.Verify(msg, sig), "Verify")
+		}
+
+		_, err = sk.TaprootTweak([]byte{0x69})
+		require.ErrorIs(t, err, errInvalidMRSize, "TaprootTweak - bad merkle root")
+	})
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2023 Yawning Angel
+//
+// SPDX-License-Identifier: BSD-3-Clause
+
+package secec
+
+import (
+	"errors"
+
+	"gitlab.com/yawning/secp256k1-voi"
+)
+
+const maxRecoveryID = 3
+
+var errInvalidSignature = errors.New("secp256k1/secec: invalid signature")
+
+// ConvertSignature converts the signature `sig` of `hash` from the
+// `from` encoding to the `to` encoding.  The signature MUST be valid
+// for the PublicKey `pub`, which is also used to derive the recovery
+// ID when converting to `EncodingCompactRecoverable` or
+// `EncodingEIP2098`.
+//
+// Notes: `s` is always normalized such that it is less than or equal
+// to `n / 2`, which is acceptable to all of the supported encodings.
+// Any recovery ID present in `sig` is ignored, and re-derived from
+// `pub`.
+func ConvertSignature(sig []byte, from, to SignatureEncoding, pub *PublicKey, hash []byte) ([]byte, error) {
+	var (
+		r, s *secp256k1.Scalar
+		err  error
+	)
+
+	switch from {
+	case EncodingASN1:
+		r, s, err = ParseASN1Signature(sig)
+	case EncodingCompact:
+		r, s, err = ParseCompactSignature(sig)
+	case EncodingCompactRecoverable:
+		r, s, _, err = ParseCompactRecoverableSignature(sig)
+	case EncodingEIP2098:
+		r, s, _, err = ParseEIP2098Signature(sig)
+	default:
+		err = errInvalidEncoding
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !pub.VerifyRaw(hash, r, s) {
+		return nil, errInvalidSignature
+	}
+
+	// Normalize s, as `(r, -s)` is also a valid signature.
+	s = secp256k1.NewScalarFrom(s)
+	s.ConditionalNegate(s, s.IsGreaterThanHalfN())
+
+	switch to {
+	case EncodingASN1:
+		return BuildASN1Signature(r, s), nil
+	case EncodingCompact:
+		return BuildCompactSignature(r, s), nil
+	case EncodingCompactRecoverable, EncodingEIP2098:
+	default:
+		return nil, errInvalidEncoding
+	}
+
+	v, err := deriveRecoveryID(pub, hash, r, s)
+	if err != nil {
+		return nil, err
+	}
+
+	if to == EncodingEIP2098 {
+		return BuildEIP2098Signature(r, s, v)
+	}
+	return BuildCompactRecoverableSignature(r, s, v), nil
+}
+
+// deriveRecoveryID returns the recovery ID for the valid signature
+// `(r, s)` of `hash`, made by the PublicKey `pub`.
+func deriveRecoveryID(pub *PublicKey, hash []byte, r, s *secp256k1.Scalar) (byte, error) {
+	for v := byte(0); v <= maxRecoveryID; v++ {
+		q, err := RecoverPublicKey(hash, r, s, v)
+		if err != nil {
+			continue
+		}
+		if pub.Equal(q) {
+			return v, nil
+		}
+	}
+
+	return 0, errInvalidRecoveryID
+}
@@ -0,0 +1,173 @@
+// Copyright (c) 2023 Yawning Angel
+//
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package dleq implements Chaum-Pedersen proofs of discrete logarithm
+// equality, that is, given `(G, H, A, B)`, a proof that
+// `log_G(A) == log_H(B)`.
+//
+// If `H` must have an unknown discrete logarithm relative to `G`, it
+// can be derived with `h2c.NUMSGenerator`.
+package dleq
+
+import (
+	csrand "crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+
+	"gitlab.com/yawning/tuplehash"
+
+	"gitlab.com/yawning/secp256k1-voi"
+	"gitlab.com/yawning/secp256k1-voi/internal/disalloweq"
+	"gitlab.com/yawning/secp256k1-voi/internal/sampling"
+	"gitlab.com/yawning/secp256k1-voi/internal/taggedhash"
+)
+
+const (
+	// ProofSize is the size of a byte-encoded Proof in bytes.
+	ProofSize = secp256k1.ScalarSize * 2
+
+	tagChallenge = "secp256k1-voi/dleq/challenge"
+	domainSepK   = "secp256k1-voi/dleq/nonce"
+
+	wantedEntropyBytes = 256 / 8
+)
+
+var (
+	errInvalidScalar = errors.New("secp256k1/secec/dleq: invalid scalar")
+	errInvalidBase   = errors.New("secp256k1/secec/dleq: base point is the point at infinity")
+	errInvalidProof  = errors.New("secp256k1/secec/dleq: invalid proof")
+
+	challengeMidstate = taggedhash.NewMidstate(tagChallenge)
+)
+
+// Proof is a proof of discrete logarithm equality.
+type Proof struct {
+	_ disalloweq.DisallowEqual
+
+	c *secp256k1.Scalar
+	s *secp256k1.Scalar
+}
+
+// Bytes returns the byte-encoding of the proof `c || s`.
+func (p *Proof) Bytes() []byte {
+	b := make([]byte, 0, ProofSize)
+	b = append(b, p.c.Bytes()...)
+	b = append(b, p.s.Bytes()...)
+	return b
+}
+
+// NewProofFromBytes checks that `b` is valid, and returns a Proof.
+func NewProofFromBytes(b []byte) (*Proof, error) {
+	if len(b) != ProofSize {
+		return nil, errInvalidProof
+	}
+
+	c, err := secp256k1.NewScalarFromCanonicalBytes((*[secp256k1.ScalarSize]byte)(b[:secp256k1.ScalarSize]))
+	if err != nil {
+		return nil, errInvalidProof
+	}
+	s, err := secp256k1.NewScalarFromCanonicalBytes((*[secp256k1.ScalarSize]byte)(b[secp256k1.ScalarSize:]))
+	if err != nil {
+		return nil, errInvalidProof
+	}
+
+	return &Proof{
+		c: c,
+		s: s,
+	}, nil
+}
+
+// Prove produces a proof that `log_G(x*G) == log_H(x*H)`.  Note that
+// `A = x*G` and `B = x*H` are not part of the proof, and must be
+// provided to the verifier separately.
+//
+// Note: If `rand` is nil, [crypto/rand.Reader] will be used.  The
+// nonce is derived from `x`, `G`, `H`, `A`, `B`, and 256-bits of
+// entropy from `rand`, so that a broken entropy source does not
+// leak `x`.
+func Prove(x *secp256k1.Scalar, G, H *secp256k1.Point, rand io.Reader) (*Proof, error) { //nolint:gocritic
+	if x.IsZero() != 0 {
+		return nil, errInvalidScalar
+	}
+	if G.IsIdentity() != 0 || H.IsIdentity() != 0 {
+		return nil, errInvalidBase
+	}
+	if rand == nil {
+		rand = csrand.Reader
+	}
+
+	A := secp256k1.NewIdentityPoint().ScalarMult(x, G)
+	B := secp256k1.NewIdentityPoint().ScalarMult(x, H)
+
+	k, err := deriveNonce(rand, x, G, H, A, B)
+	if err != nil {
+		return nil, err
+	}
+	defer k.Zero()
+
+	// R1 = k*G, R2 = k*H
+	R1 := secp256k1.NewIdentityPoint().ScalarMult(k, G)
+	R2 := secp256k1.NewIdentityPoint().ScalarMult(k, H)
+
+	// c = H(G, H, A, B, R1, R2)
+	c := challenge(G, H, A, B, R1, R2)
+
+	// s = k - c*x
+	s := secp256k1.NewScalar().Multiply(c, x)
+	s.Subtract(k, s)
+
+	return &Proof{
+		c: c,
+		s: s,
+	}, nil
+}
+
+// Verify verifies the proof that `log_G(A) == log_H(B)`.
+func Verify(A, B, G, H *secp256k1.Point, proof *Proof) bool { //nolint:gocritic
+	if proof == nil || proof.c == nil || proof.s == nil {
+		return false
+	}
+	if G.IsIdentity() != 0 || H.IsIdentity() != 0 || A.IsIdentity() != 0 || B.IsIdentity() != 0 {
+		return false
+	}
+
+	// R1 = s*G + c*A, R2 = s*H + c*B
+	scalars := []*secp256k1.Scalar{proof.s, proof.c}
+	R1 := secp256k1.NewIdentityPoint().MultiScalarMultVartime(scalars, []*secp256k1.Point{G, A})
+	R2 := secp256k1.NewIdentityPoint().MultiScalarMultVartime(scalars, []*secp256k1.Point{H, B})
+
+	c := challenge(G, H, A, B, R1, R2)
+
+	return c.Equal(proof.c) == 1
+}
+
+func challenge(points ...*secp256k1.Point) *secp256k1.Scalar {
+	h := challengeMidstate.New()
+	for _, p := range points {
+		_, _ = h.Write(p.CompressedBytes())
+	}
+
+	c, _ := secp256k1.NewScalarFromBytes((*[secp256k1.ScalarSize]byte)(h.Sum(nil)))
+	return c
+}
+
+func deriveNonce(rand io.Reader, x *secp256k1.Scalar, points ...*secp256k1.Point) (*secp256k1.Scalar, error) {
+	// As with the ECDSA nonce generation in secec, mix the secret,
+	// the statement, and fresh entropy with TupleHashXOF128, so that
+	// a bad (or replayed) entropy source does not leak `x`.
+	var entropy [wantedEntropyBytes]byte
+	if _, err := io.ReadFull(rand, entropy[:]); err != nil {
+		return nil, fmt.Errorf("%w: %w", sampling.ErrEntropySource, err)
+	}
+
+	xof := tuplehash.NewTupleHashXOF128([]byte(domainSepK))
+	_, _ = xof.Write(x.Bytes())
+	for _, p := range points {
+		_, _ = xof.Write(p.CompressedBytes())
+	}
+	_, _ = xof.Write(entropy[:])
+
+	return sampling.RandomScalar(xof)
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2023 Yawning Angel
+//
+// SPDX-License-Identifier: BSD-3-Clause
+
+package dleq
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gitlab.com/yawning/secp256k1-voi"
+	"gitlab.com/yawning/secp256k1-voi/internal/sampling"
+	"gitlab.com/yawning/secp256k1-voi/secec"
+	"gitlab.com/yawning/secp256k1-voi/secec/h2c"
+)
+
+func TestDLEQ(t *testing.T) {
+	G := secp256k1.NewGeneratorPoint()
+	H, err := h2c.Secp256k1_XMD_SHA256_SSWU_RO([]byte("secp256k1-voi/dleq/test"), []byte("H"))
+	require.NoError(t, err, "hash_to_curve")
+
+	priv, err := secec.GenerateKey()
+	require.NoError(t, err, "GenerateKey")
+	x := priv.Scalar()
+
+	A := secp256k1.NewIdentityPoint().ScalarMult(x, G)
+	B := secp256k1.NewIdentityPoint().ScalarMult(x, H)
+
+	proof, err := Prove(x, G, H, nil)
+	require.NoError(t, err, "Prove")
+	require.True(t, Verify(A, B, G, H, proof), "Verify")
+
+	proofBytes := proof.Bytes()
+	require.Len(t, proofBytes, ProofSize, "Bytes")
+	proof2, err := NewProofFromBytes(proofBytes)
+	require.NoError(t, err, "NewProofFromBytes")
+	require.True(t, Verify(A, B, G, H, proof2), "Verify - deserialized")
+
+	t.Run("Invalid", func(t *testing.T) {
+		B2 := secp256k1.NewIdentityPoint().Add(B, H)
+		require.False(t, Verify(A, B2, G, H, proof), "Verify - wrong B")
+		require.False(t, Verify(B, A, H, G, proof), "Verify - swapped bases")
+		require.False(t, Verify(A, secp256k1.NewIdentityPoint(), G, H, proof), "Verify - identity B")
+
+		tmp := bytes.Clone(proofBytes)
+		tmp[ProofSize-1] ^= 0x69
+		badProof, err := NewProofFromBytes(tmp)
+		require.NoError(t, err, "NewProofFromBytes - corrupted")
+		require.False(t, Verify(A, B, G, H, badProof), "Verify - corrupted")
+
+		_, err = NewProofFromBytes(proofBytes[:5])
+		require.ErrorIs(t, err, errInvalidProof, "NewProofFromBytes - truncated")
+		_, err = NewProofFromBytes(bytes.Repeat([]byte{0xff}, ProofSize))
+		require.ErrorIs(t, err, errInvalidProof, "NewProofFromBytes - non-canonical")
+
+		_, err = Prove(secp256k1.NewScalar(), G, H, nil)
+		require.ErrorIs(t, err, errInvalidScalar, "Prove - zero x")
+		_, err = Prove(x, G, secp256k1.NewIdentityPoint(), nil)
+		require.ErrorIs(t, err, errInvalidBase, "Prove - identity H")
+		_, err = Prove(x, G, H, bytes.NewReader(nil))
+		require.ErrorIs(t, err, sampling.ErrEntropySource, "Prove - broken rand")
+
+		require.False(t, Verify(A, B, G, H, nil), "Verify - nil proof")
+		require.False(t, Verify(A, B, G, H, &Proof{}), "Verify - empty proof")
+	})
+	t.Run("HedgedNonce", func(t *testing.T) {
+		// A fixed (broken) entropy source must not result in the same
+		// nonce being reused across different statements.
+		zeroRand := bytes.NewReader(make([]byte, 1024))
+
+		proof1, err := Prove(x, G, H, zeroRand)
+		require.NoError(t, err, "Prove - zero rand")
+		require.True(t, Verify(A, B, G, H, proof1), "Verify - zero rand")
+
+		G2 := secp256k1.NewIdentityPoint().Double(G)
+		A2 := secp256k1.NewIdentityPoint().ScalarMult(x, G2)
+		proof2, err := Prove(x, G2, H, zeroRand)
+		require.NoError(t, err, "Prove - zero rand, other G")
+		require.True(t, Verify(A2, B, G2, H, proof2), "Verify - zero rand, other G")
+
+		// s1 = k1 - c1*x, s2 = k2 - c2*x, with k1 == k2 would allow
+		// recovering x = (s1 - s2) / (c2 - c1).
+		k1 := secp256k1.NewScalar().Multiply(proof1.c, x)
+		k1.Add(k1, proof1.s)
+		k2 := secp256k1.NewScalar().Multiply(proof2.c, x)
+		k2.Add(k2, proof2.s)
+		require.EqualValues(t, 0, k1.Equal(k2), "nonces differ")
+
+		// The same statement and entropy is deterministic.
+		proof3, err := Prove(x, G, H, bytes.NewReader(make([]byte, 1024)))
+		require.NoError(t, err, "Prove - zero rand, again")
+		require.Equal(t, proof1.Bytes(), proof3.Bytes(), "Prove - deterministic")
+	})
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2023 Yawning Angel
+//
+// SPDX-License-Identifier: BSD-3-Clause
+
+package secec
+
+import (
+	"errors"
+	"io"
+
+	"gitlab.com/yawning/secp256k1-voi"
+)
+
+var errInvalidECDHPublicKey = errors.New("secp256k1/secec: invalid ECDH public key")
+
+// ECDHCurve is a shim that provides the same methods as the runtime
+// library's `crypto/ecdh.Curve`, for code written against that
+// interface.  As `crypto/ecdh.Curve` has unexported methods, it is
+// not possible to implement the interface itself.
+//
+// The encodings produced and accepted are the same as those of the
+// runtime library's NIST curves (eg: `ecdh.P256()`):
+//
+//   - Private keys are 32-byte big-endian scalars in the range `[1,n)`.
+//   - Public keys are uncompressed SEC 1 points (`0x04 || x || y`).
+//     Compressed and hybrid points, and the point at infinity are
+//     rejected.  Use `NewPublicKey` to accept all SEC 1 encodings.
+//   - The shared secret is the 32-byte SEC 1 encoded x-coordinate.
+type ECDHCurve struct{}
+
+// NewECDHCurve returns a `crypto/ecdh.Curve`-like ECDHCurve.
+func NewECDHCurve() ECDHCurve {
+	return ECDHCurve{}
+}
+
+// GenerateKey generates a new PrivateKey, using `rand` as the entropy
+// source.
+func (ECDHCurve) GenerateKey(rand io.Reader) (*PrivateKey, error) {
+	return GenerateKeyFromReader(rand)
+}
+
+// NewPrivateKey checks that `key` is valid and returns a PrivateKey.
+// This is identical to the package level `NewPrivateKey`.
+func (ECDHCurve) NewPrivateKey(key []byte) (*PrivateKey, error) {
+	return NewPrivateKey(key)
+}
+
+// NewPublicKey checks that `key` is a valid uncompressed SEC 1 point
+// and returns a PublicKey.
+func (ECDHCurve) NewPublicKey(key []byte) (*PublicKey, error) {
+	if len(key) != secp256k1.UncompressedPointSize || key[0] != 0x04 {
+		return nil, errInvalidECDHPublicKey
+	}
+
+	return NewPublicKey(key)
+}
+
+// ECDH performs a ECDH exchange with the PrivateKey `local` and the
+// PublicKey `remote`, and returns the shared secret, as with
+// `PrivateKey.ECDH`.
+func (ECDHCurve) ECDH(local *PrivateKey, remote *PublicKey) ([]byte, error) {
+	return local.ECDH(remote)
+}
+
+// String returns the name of the curve.
+func (ECDHCurve) String() string {
+	return "secp256k1"
+}
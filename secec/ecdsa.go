@@ -7,21 +7,30 @@ package secec
 import (
 	"crypto"
 	csrand "crypto/rand"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
 
 	"gitlab.com/yawning/tuplehash"
+	"golang.org/x/crypto/sha3"
 
 	"gitlab.com/yawning/secp256k1-voi"
+	"gitlab.com/yawning/secp256k1-voi/internal/rfc6979"
+	"gitlab.com/yawning/secp256k1-voi/internal/sampling"
 )
 
 const (
 	wantedEntropyBytes = 256 / 8
-	maxScalarResamples = 8
+	maxLowRAttempts    = 128
 	domainSepECDSA     = "ECDSA-Sign"
+	domainSepCounter   = "ECDSA-Sign-Counter"
 )
 
+// MinDigestSize is the minimum size of a message digest in bytes, as
+// accepted by the ECDSA signing and verification routines.
+const MinDigestSize = secp256k1.ScalarSize
+
 var (
 	errInvalidEncoding = errors.New("secp256k1/secec: invalid signature encoding")
 	errInvalidScalar   = errors.New("secp256k1/secec: invalid scalar")
@@ -30,9 +39,14 @@ var (
 	errRIsInfinity     = errors.New("secp256k1/secec: R is the point at infinity")
 	errVNeqR           = errors.New("secp256k1/secec: v does not equal r")
 	errSigCheckFailed  = errors.New("secp256k1/secec: failed to verify new sig")
+	errZeroDigest      = errors.New("secp256k1/secec: digest is all zero")
+	errVMismatch       = errors.New("secp256k1/secec: recovery ID does not match public key")
+	errLowRGrinding    = errors.New("secp256k1/secec: failed to generate low-R signature")
+
+	errInvalidRecoveryID = errors.New("secp256k1/secec: invalid recovery ID")
 
-	errEntropySource     = errors.New("secp256k1/secec: entropy source failure")
-	errRejectionSampling = errors.New("secp256k1/secec: failed rejection sampling")
+	errEntropySource     = sampling.ErrEntropySource
+	errRejectionSampling = sampling.ErrRejectionSampling
 )
 
 // SignatureEncoding is a ECDSA signature encoding method.
@@ -48,6 +62,10 @@ const (
 	// encoded as 32-byte big-endian integers, and `V` being in the
 	// range `[0,3]`.
 	EncodingCompactRecoverable
+	// EncodingEIP2098 is the EIP-2098 `[R | yParityAndS]` compact
+	// representation, with the y-parity of R stored in the most
+	// significant bit of S.
+	EncodingEIP2098
 )
 
 // ECDSAOptions can be used with `PrivateKey.Sign` or `PublicKey.Verify`
@@ -72,6 +90,16 @@ type ECDSAOptions struct {
 	// RejectMalleable will cause the verification process to
 	// reject signatures where `s > n / 2`.
 	RejectMalleable bool
+
+	// RejectZeroDigest will cause the signing and verification
+	// processes to reject digests that are all zero, which usually
+	// is indicative of a bug in the caller.
+	//
+	// Note: The entry points that do not take options (eg: `VerifyRaw`)
+	// accept all-zero digests.  Use a Verifier created with
+	// `NewVerifierWithOptions` to reject them when verifying `(r, s)`
+	// or ASN.1 encoded signatures.
+	RejectZeroDigest bool
 }
 
 // HashFunc returns an identifier for the hash function used to produce
@@ -87,6 +115,10 @@ func (opt *ECDSAOptions) HashFunc() crypto.Hash {
 // byte-encoded signature.  If `opts` is not a `*ECDSAOptions` the
 // output encoding will default to `EncodingASN1`.
 //
+// This implements the [crypto.Signer] interface.  `opts.HashFunc()`
+// is only used to validate the length of `digest`, and a value of
+// `crypto.Hash(0)` skips the check.
+//
 // Notes: If `rand` is nil, [crypto/rand.Reader] will be used.
 // `s` will always be less than or equal to `n / 2`.
 func (k *PrivateKey) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
@@ -103,12 +135,17 @@ func (k *PrivateKey) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts)
 			if hashFn == crypto.Hash(0) {
 				hashFn = crypto.SHA256
 			}
+			if o.RejectZeroDigest && isZeroDigest(digest) {
+				return nil, errZeroDigest
+			}
 		}
 
-		// Check that the digest is sized correctly.
-		expectedLen := hashFn.Size()
-		if len(digest) != expectedLen {
-			return nil, errInvalidDigest
+		// Check that the digest is sized correctly, if the hash
+		// function is specified.
+		if hashFn != crypto.Hash(0) {
+			if expectedLen, ok := digestSize(hashFn); !ok || len(digest) != expectedLen {
+				return nil, errInvalidDigest
+			}
 		}
 	}
 
@@ -142,6 +179,10 @@ func (k *PrivateKey) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts)
 		sig = BuildCompactSignature(r, s)
 	case EncodingCompactRecoverable:
 		sig = BuildCompactRecoverableSignature(r, s, v)
+	case EncodingEIP2098:
+		if sig, err = BuildEIP2098Signature(r, s, v); err != nil {
+			return nil, err
+		}
 	default:
 		// "Why, yes, this is after SignRaw. Don't do that then."
 		return nil, errInvalidEncoding
@@ -159,7 +200,155 @@ func (k *PrivateKey) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts)
 // `s` will always be less than or equal to `n / 2`.  `recovery_id`
 // will always be in the range `[0, 3]`.
 func (k *PrivateKey) SignRaw(rand io.Reader, digest []byte) (*secp256k1.Scalar, *secp256k1.Scalar, byte, error) {
-	return sign(rand, k, digest)
+	return sign(rand, k, digest, nil)
+}
+
+// SignDigest signs `digest` using the PrivateKey `k`, using the signing
+// procedure as specified in SEC 1, Version 2.0, Section 4.1.3.  It
+// returns the tuple `(r, s)`.
+//
+// `digest` MUST be at least `MinDigestSize` bytes, and digests longer
+// than `MinDigestSize` bytes (eg: SHA-512, SHA3-512) are truncated to
+// the left-most `ceil(log2(n))` bits (ie: the first `MinDigestSize`
+// bytes), as specified in SEC 1, Version 2.0, Section 4.1.3, Step 5.
+//
+// Notes: If `rand` is nil, [crypto/rand.Reader] will be used.
+// `s` will always be less than or equal to `n / 2`.
+func (k *PrivateKey) SignDigest(rand io.Reader, digest []byte) (*secp256k1.Scalar, *secp256k1.Scalar, error) {
+	r, s, _, err := k.SignRaw(rand, digest)
+	return r, s, err
+}
+
+// SignCompactRecoverable signs `digest` (which should be the result of
+// hashing a larger message) using the PrivateKey `k`, using the signing
+// procedure as specified in SEC 1, Version 2.0, Section 4.1.3.  It
+// returns the 65-byte `[R | S | V]` compact recoverable signature.
+//
+// Notes: If `rand` is nil, [crypto/rand.Reader] will be used.
+// `s` will always be less than or equal to `n / 2`.  `V` is the raw
+// recovery ID in the range `[0, 3]`, and protocol specific offsets
+// (eg: Bitcoin's `27`/`31`, Ethereum's `27`) are NOT applied.
+func (k *PrivateKey) SignCompactRecoverable(rand io.Reader, digest []byte) ([]byte, error) {
+	r, s, v, err := k.SignRaw(rand, digest)
+	if err != nil {
+		return nil, err
+	}
+
+	return BuildCompactRecoverableSignature(r, s, v), nil
+}
+
+// SignASN1Counter signs `digest` (which should be the result of hashing
+// a larger message) using the PrivateKey `k`, using the signing procedure
+// as specified in SEC 1, Version 2.0, Section 4.1.3, with the context
+// string `ctx` and the monotonic counter `counter` additionally mixed
+// into the nonce derivation.  It returns the ASN.1 encoded signature.
+//
+// WARNING: The caller MUST NOT reuse a `(ctx, counter)` pair with the
+// same key.  While entropy from [crypto/rand.Reader] is still used,
+// the counter is what guarantees nonce uniqueness if it is broken.
+//
+// Note: `s` will always be less than or equal to `n / 2`.
+func (k *PrivateKey) SignASN1Counter(ctx string, counter uint64, digest []byte) ([]byte, error) {
+	var counterBytes [8]byte
+	binary.BigEndian.PutUint64(counterBytes[:], counter)
+
+	nd := func(priv *PrivateKey, _ string, digest []byte, rand io.Reader) (io.Reader, error) {
+		e, err := hashToScalar(digest)
+		if err != nil {
+			return nil, err
+		}
+		return mitigateDebianAndSony(rand, domainSepCounter, priv, e, []byte(ctx), counterBytes[:])
+	}
+
+	r, s, _, err := sign(nil, k, digest, nd)
+	if err != nil {
+		return nil, err
+	}
+
+	return BuildASN1Signature(r, s), nil
+}
+
+// SignLowRRecoverable signs `digest` (which should be the result of
+// hashing a larger message) using the PrivateKey `k`, using the signing
+// procedure as specified in SEC 1, Version 2.0, Section 4.1.3, retrying
+// with a fresh nonce until `r <= 2^255 - 1` (ie: "low-R", where the
+// ASN.1 encoding of `r` is 32-bytes).  It returns the compact recoverable
+// `[R | S | V]` signature.
+//
+// Notes: If `rand` is nil, [crypto/rand.Reader] will be used.  As
+// grinding requires a different nonce per attempt, `rand` MUST NOT be
+// `RFC6979SHA256()`.  `s` will always be less than or equal to `n / 2`,
+// and `V` always corresponds to the final `(r, s)`.
+func (k *PrivateKey) SignLowRRecoverable(rand io.Reader, digest []byte) ([]byte, error) {
+	if rand == readerRFC6979SHA256 {
+		return nil, errLowRGrinding
+	}
+
+	for i := 0; i < maxLowRAttempts; i++ {
+		r, s, v, err := k.SignRaw(rand, digest)
+		if err != nil {
+			return nil, err
+		}
+
+		// Each attempt has a ~1/2 chance of producing a low-R signature.
+		if r.Bytes()[0]&0x80 == 0 {
+			return BuildCompactRecoverableSignature(r, s, v), nil
+		}
+	}
+
+	return nil, errLowRGrinding
+}
+
+// NonceDerivationFunc is a function that returns the [io.Reader] that
+// ECDSA signing will sample the per-signature nonce `k` from, given
+// the private key `priv`, a domain separation context string `ctx`,
+// the digest being signed `digest`, and the caller provided entropy
+// source `rand` (which may be nil).
+//
+// WARNING: The security of ECDSA is entirely dependent on `k` being
+// uniformly distributed and never reused across different digests.
+type NonceDerivationFunc func(priv *PrivateKey, ctx string, digest []byte, rand io.Reader) (io.Reader, error)
+
+// SignerConfig is the configuration for `PrivateKey.SignASN1WithConfig`.
+type SignerConfig struct {
+	// Rand is the entropy source passed to NonceDerivation.
+	Rand io.Reader
+
+	// NonceDerivation is the function used to derive the source of
+	// the ECDSA nonce.  If unspecified, the default hardened
+	// derivation used by `Sign` will be used.
+	NonceDerivation NonceDerivationFunc
+
+	// RejectZeroDigest will cause the signing process to reject
+	// digests that are all zero.
+	RejectZeroDigest bool
+}
+
+// SignASN1WithConfig signs `digest` (which should be the result of
+// hashing a larger message) using the PrivateKey `k`, using the signing
+// procedure as specified in SEC 1, Version 2.0, Section 4.1.3, with
+// the nonce derivation specified by `cfg`.  It returns the ASN.1
+// encoded signature.
+//
+// Note: `s` will always be less than or equal to `n / 2`.
+func (k *PrivateKey) SignASN1WithConfig(cfg *SignerConfig, digest []byte) ([]byte, error) {
+	var (
+		rand io.Reader
+		nd   NonceDerivationFunc
+	)
+	if cfg != nil {
+		rand, nd = cfg.Rand, cfg.NonceDerivation
+		if cfg.RejectZeroDigest && isZeroDigest(digest) {
+			return nil, errZeroDigest
+		}
+	}
+
+	r, s, _, err := sign(rand, k, digest, nd)
+	if err != nil {
+		return nil, err
+	}
+
+	return BuildASN1Signature(r, s), nil
 }
 
 // Verify verifies the byte encoded signature `sig` of `digest`,
@@ -180,10 +369,12 @@ func (k *PublicKey) Verify(digest, sig []byte, opts *ECDSAOptions) bool {
 		if hashFn == crypto.Hash(0) {
 			hashFn = crypto.SHA256
 		}
+		if opts.RejectZeroDigest && isZeroDigest(digest) {
+			return false
+		}
 
 		// Check that the digest is sized correctly.
-		expectedLen := hashFn.Size()
-		if len(digest) != expectedLen {
+		if expectedLen, ok := digestSize(hashFn); !ok || len(digest) != expectedLen {
 			return false
 		}
 	}
@@ -202,6 +393,8 @@ func (k *PublicKey) Verify(digest, sig []byte, opts *ECDSAOptions) bool {
 		r, s, err = ParseCompactSignature(sig)
 	case EncodingCompactRecoverable:
 		r, s, v, err = ParseCompactRecoverableSignature(sig)
+	case EncodingEIP2098:
+		r, s, v, err = ParseEIP2098Signature(sig)
 	default:
 		err = errInvalidEncoding
 	}
@@ -216,7 +409,7 @@ func (k *PublicKey) Verify(digest, sig []byte, opts *ECDSAOptions) bool {
 	switch sigEncoding {
 	case EncodingASN1, EncodingCompact:
 		return k.VerifyRaw(digest, r, s)
-	case EncodingCompactRecoverable:
+	case EncodingCompactRecoverable, EncodingEIP2098:
 		q, err := RecoverPublicKey(digest, r, s, v)
 		if err != nil {
 			return false
@@ -231,10 +424,115 @@ func (k *PublicKey) Verify(digest, sig []byte, opts *ECDSAOptions) bool {
 // PublicKey `k`, using the verification procedure as specified in
 // SEC 1, Version 2.0, Section 4.1.4.  Its return value records
 // whether the signature is valid.
+//
+// Note: All-zero digests are accepted.  See `Verifier.VerifyRaw` for
+// a variant that honors `ECDSAOptions.RejectZeroDigest`.
 func (k *PublicKey) VerifyRaw(digest []byte, r, s *secp256k1.Scalar) bool {
 	return nil == verify(nil, k, digest, r, s)
 }
 
+// VerifyDigest verifies the `(r, s)` signature of `digest`, using the
+// PublicKey `k`, using the verification procedure as specified in
+// SEC 1, Version 2.0, Section 4.1.4.  Its return value records
+// whether the signature is valid.
+//
+// `digest` MUST be at least `MinDigestSize` bytes, and digests longer
+// than `MinDigestSize` bytes are truncated as with `SignDigest`.
+func (k *PublicKey) VerifyDigest(digest []byte, r, s *secp256k1.Scalar) bool {
+	return k.VerifyRaw(digest, r, s)
+}
+
+// VerifyPrecomputedE verifies the `(r, s)` signature, using the PublicKey
+// `k`, and the scalar representation `e` of the message digest, using
+// the verification procedure as specified in SEC 1, Version 2.0, Section
+// 4.1.4, skipping Steps 2 and 3.  Its return value records whether the
+// signature is valid.
+//
+// WARNING: It is the caller's responsibility to ensure that `e` was
+// derived from the message digest as specified in SEC 1, Version 2.0,
+// Section 4.1.4, Step 3.
+func (k *PublicKey) VerifyPrecomputedE(e, r, s *secp256k1.Scalar) bool {
+	if r.IsZero() != 0 || s.IsZero() != 0 {
+		return false
+	}
+
+	return nil == verifyE(nil, k, e, r, s)
+}
+
+// VerifyRecoverable verifies the `[R | S | V]` compact recoverable
+// signature `sig` of `digest`, using the PublicKey `k`, using the
+// verification procedure as specified in SEC 1, Version 2.0, Section
+// 4.1.4, and additionally checks that the recovery ID `V` is consistent
+// with `k`.  If the signature is valid but `V` is not consistent with
+// `k`, it returns false and an error.
+func (k *PublicKey) VerifyRecoverable(digest, sig []byte) (bool, error) {
+	r, s, v, err := ParseCompactRecoverableSignature(sig)
+	if err != nil {
+		return false, err
+	}
+
+	if !k.VerifyRaw(digest, r, s) {
+		return false, nil
+	}
+
+	q, err := RecoverPublicKey(digest, r, s, v)
+	if err != nil || !k.Equal(q) {
+		return false, errVMismatch
+	}
+
+	return true, nil
+}
+
+// VerifyASN1WithTrailingByte verifies the ASN.1 encoded signature `sig`
+// of `hash`, with a trailing (protocol specific) hash-type byte, using
+// the PublicKey `k`, using the verification procedure as specified in
+// SEC 1, Version 2.0, Section 4.1.4.  It returns the trailing byte and
+// true iff the signature is valid, 0 and false otherwise.
+//
+// Note: Unlike `bitcoin.VerifyASN1`, `s` in the range `[1, n)` is
+// accepted, and the trailing byte is not interpreted.
+func (k *PublicKey) VerifyASN1WithTrailingByte(hash, sig []byte) (byte, bool) {
+	if len(sig) == 0 {
+		return 0, false
+	}
+
+	hashType := sig[len(sig)-1]
+	r, s, err := ParseASN1Signature(sig[:len(sig)-1])
+	if err != nil {
+		return 0, false
+	}
+
+	if !k.VerifyRaw(hash, r, s) {
+		return 0, false
+	}
+
+	return hashType, true
+}
+
+// VerifyStrict verifies the `(r, s)` signature of `hash`, using the
+// PublicKey `k`, using the verification procedure as specified in
+// SEC 1, Version 2.0, Section 4.1.4.  In addition to whether the
+// signature is valid, it returns whether the x-coordinate of R was
+// greater than or equal to n (ie: if Step 7 reduced xR).
+//
+// Note: Valid signatures where xR >= n occur with negligible
+// probability for honestly generated signatures.
+func (k *PublicKey) VerifyStrict(hash []byte, r, s *secp256k1.Scalar) (bool, bool) {
+	if r.IsZero() != 0 || s.IsZero() != 0 {
+		return false, false
+	}
+
+	e, err := hashToScalar(hash)
+	if err != nil {
+		return false, false
+	}
+
+	sc := newVerifyScratch()
+	err = sc.verifyE(nil, k, e, r, s)
+
+	return err == nil, sc.xRReduced == 1
+}
+
 // RecoverPublicKey recovers the public key from the signature
 // `(r, s, recoveryID)` over `digest`.  `recoverID` MUST be in the range
 // `[0,3]`.
@@ -281,7 +579,43 @@ func RecoverPublicKey(digest []byte, r, s *secp256k1.Scalar, recoveryID byte) (*
 	return NewPublicKeyFromPoint(Q)
 }
 
-func sign(rand io.Reader, d *PrivateKey, hBytes []byte) (*secp256k1.Scalar, *secp256k1.Scalar, byte, error) {
+// RecoverPublicKeyStrictEthereum recovers the public key from the
+// signature `(r, s, v)` over `digest`, following the Ethereum rule
+// that `v` MUST be in the range `[0,1]` (ie: only encodes the parity of
+// the y-coordinate of R).  The recovery IDs corresponding to the case
+// where the x-coordinate of R was reduced modulo n are rejected.
+//
+// Note: As with RecoverPublicKey, `s` in the range `[1, n)` is
+// considered valid here.
+func RecoverPublicKeyStrictEthereum(digest []byte, r, s *secp256k1.Scalar, v byte) (*PublicKey, error) {
+	if v > 1 {
+		return nil, errInvalidRecoveryID
+	}
+
+	return RecoverPublicKey(digest, r, s, v)
+}
+
+// RecoverCompact recovers the public key from the 65-byte `[R | S | V]`
+// compact recoverable signature `sig` over `digest`.  `V` MUST be the
+// raw recovery ID in the range `[0,3]`, without any protocol specific
+// offset applied.
+//
+// Note: As with RecoverPublicKey, `s` in the range `[1, n)` is
+// considered valid here.
+func RecoverCompact(digest, sig []byte) (*PublicKey, error) {
+	r, s, v, err := ParseCompactRecoverableSignature(sig)
+	if err != nil {
+		return nil, err
+	}
+
+	return RecoverPublicKey(digest, r, s, v)
+}
+
+func sign(rand io.Reader, d *PrivateKey, hBytes []byte, nd NonceDerivationFunc) (*secp256k1.Scalar, *secp256k1.Scalar, byte, error) {
+	if d.isZeroized() {
+		return nil, nil, 0, errZeroizedKey
+	}
+
 	var recoveryID byte
 
 	// Note/yawning: `e` (derived from `hash`) in steps 4 and 5, is
@@ -320,7 +654,13 @@ func sign(rand io.Reader, d *PrivateKey, hBytes []byte) (*secp256k1.Scalar, *sec
 	// to do, even if this wasn't something that has historically
 	// been a large problem.
 
-	fixedRng, err := mitigateDebianAndSony(rand, domainSepECDSA, d, e)
+	var fixedRng io.Reader
+	switch nd {
+	case nil:
+		fixedRng, err = mitigateDebianAndSony(rand, domainSepECDSA, d, e)
+	default:
+		fixedRng, err = nd(d, domainSepECDSA, hBytes, rand)
+	}
 	if err != nil {
 		return nil, nil, 0, err
 	}
@@ -423,13 +763,45 @@ func verify(d *PrivateKey, q *PublicKey, hBytes []byte, r, s *secp256k1.Scalar)
 		return err
 	}
 
+	return verifyE(d, q, e, r, s)
+}
+
+func verifyE(d *PrivateKey, q *PublicKey, e, r, s *secp256k1.Scalar) error {
+	return newVerifyScratch().verifyE(d, q, e, r, s)
+}
+
+// verifyScratch is the temporary state used by the verification
+// procedure, split out so that it can be re-used across calls.
+type verifyScratch struct {
+	sInv, u1, u2, v *secp256k1.Scalar
+	R               *secp256k1.Point
+
+	xRReduced uint64 // Set iff xR >= n
+}
+
+func (sc *verifyScratch) verifyE(d *PrivateKey, q *PublicKey, e, r, s *secp256k1.Scalar) error {
+	// Note/yawning: Steps 1 through 3 are the caller's responsibility.
+
 	// 4. Compute: u1 = e(s^−1) mod n and u2 = r(s^-1) mod n.
 
-	sInv := secp256k1.NewScalar().Invert(s)
-	u1 := secp256k1.NewScalar().Multiply(e, sInv)
-	u2 := secp256k1.NewScalar().Multiply(r, sInv)
+	sInv := sc.sInv.Invert(s)
+	return sc.verifySInv(d, q, e, r, sInv)
+}
 
-	R := secp256k1.NewIdentityPoint()
+// verifyEVartime is verifyE with `d = nil`, that inverts `s` in
+// variable time.  This is safe as `s` is part of the signature, and
+// thus public.
+func (sc *verifyScratch) verifyEVartime(q *PublicKey, e, r, s *secp256k1.Scalar) error {
+	sInv := sc.sInv.InvertVartime(s)
+	return sc.verifySInv(nil, q, e, r, sInv)
+}
+
+func (sc *verifyScratch) verifySInv(d *PrivateKey, q *PublicKey, e, r, sInv *secp256k1.Scalar) error {
+	u1 := sc.u1.Multiply(e, sInv)
+	u2 := sc.u2.Multiply(r, sInv)
+
+	R := sc.R
+	sc.xRReduced = 0
 	switch d {
 	case nil:
 		// 5. Compute: R = (xR, yR) = u1 * G + u2 * QU.
@@ -457,7 +829,8 @@ func verify(d *PrivateKey, q *PublicKey, hBytes []byte, r, s *secp256k1.Scalar)
 	// 7. Set v = xR mod n.
 
 	xRBytes, _ := R.XBytes() // Can't fail, R != Inf.
-	v, _ := secp256k1.NewScalarFromBytes((*[secp256k1.ScalarSize]byte)(xRBytes))
+	v, xRReduced := sc.v.SetBytes((*[secp256k1.ScalarSize]byte)(xRBytes))
+	sc.xRReduced = xRReduced
 
 	// 8. Compare v and r — if v = r, output “valid”, and if
 	// v != r, output “invalid”.
@@ -469,23 +842,65 @@ func verify(d *PrivateKey, q *PublicKey, hBytes []byte, r, s *secp256k1.Scalar)
 	return nil
 }
 
+func newVerifyScratch() *verifyScratch {
+	return &verifyScratch{
+		sInv: secp256k1.NewScalar(),
+		u1:   secp256k1.NewScalar(),
+		u2:   secp256k1.NewScalar(),
+		v:    secp256k1.NewScalar(),
+		R:    secp256k1.NewIdentityPoint(),
+	}
+}
+
+// digestSize returns the digest size of `hashFn`, and true, or 0 and
+// false if `hashFn` is unknown or unavailable, as `crypto.Hash.Size`
+// panics on unknown hash functions.
+func digestSize(hashFn crypto.Hash) (int, bool) {
+	if !hashFn.Available() {
+		return 0, false
+	}
+	return hashFn.Size(), true
+}
+
+func isZeroDigest(digest []byte) bool {
+	var acc byte
+	for _, b := range digest {
+		acc |= b
+	}
+
+	return acc == 0
+}
+
 // hashToScalar converts a hash to a scalar per SEC 1, Version 2.0,
 // Section 4.1.3, Step 5 (and Section 4.1.4, Step 3).
 //
+// Digests shorter than `MinDigestSize` bytes are rejected, and digests
+// longer than `MinDigestSize` bytes are truncated to the left-most
+// `MinDigestSize` bytes.  As `n` is 256-bits, this is exactly the
+// left-most `ceil(log2(n))` bits, and no further bit shifting is
+// required.
+//
 // Note: This also will reduce the resulting scalar such that it is
 // in the range [0, n), which is fine for ECDSA.
 func hashToScalar(hash []byte) (*secp256k1.Scalar, error) {
-	if len(hash) < secp256k1.ScalarSize {
+	return setHashToScalar(secp256k1.NewScalar(), hash)
+}
+
+// setHashToScalar sets `dst` to the scalar representation of `hash`
+// as with hashToScalar, and returns `dst`.  It exists so that callers
+// that re-use scalars can avoid the heap allocation.
+func setHashToScalar(dst *secp256k1.Scalar, hash []byte) (*secp256k1.Scalar, error) {
+	if len(hash) < MinDigestSize {
 		return nil, errInvalidDigest
 	}
 
 	// TLDR; The left-most Ln-bits of hash.
 	tmp := (*[secp256k1.ScalarSize]byte)(hash[:secp256k1.ScalarSize])
-	s, _ := secp256k1.NewScalarFromBytes(tmp) // Reduction info unneeded.
-	return s, nil
+	_, _ = dst.SetBytes(tmp) // Reduction info unneeded.
+	return dst, nil
 }
 
-func mitigateDebianAndSony(rand io.Reader, ctx string, k *PrivateKey, e *secp256k1.Scalar) (io.Reader, error) {
+func mitigateDebianAndSony(rand io.Reader, ctx string, k *PrivateKey, e *secp256k1.Scalar, extra ...[]byte) (io.Reader, error) {
 	// There are documented attacks that can exploit even the
 	// most subtle amounts of bias (< 1-bit) in the generation
 	// of the ECDSA nonce.
@@ -504,42 +919,36 @@ func mitigateDebianAndSony(rand io.Reader, ctx string, k *PrivateKey, e *secp256
 
 	switch rand {
 	case readerRFC6979SHA256:
-		return newDrbgRFC6979(k.scalar, e), nil
+		return rfc6979.NewDRBG(k.scalar, e), nil
 	case nil:
 		rand = csrand.Reader
 	}
 
+	return mixNonceEntropy(newNonceXOF(ctx, k), rand, e, extra...)
+}
+
+// newNonceXOF returns the TupleHashXOF128 instance used by
+// mitigateDebianAndSony, with the private key already absorbed.
+func newNonceXOF(ctx string, k *PrivateKey) sha3.ShakeHash {
+	xof := tuplehash.NewTupleHashXOF128([]byte("Honorary Debian/Sony RNG mitigation:" + ctx))
+	_, _ = xof.Write(k.scalar.Bytes())
+	return xof
+}
+
+func mixNonceEntropy(xof sha3.ShakeHash, rand io.Reader, e *secp256k1.Scalar, extra ...[]byte) (io.Reader, error) {
 	var tmp [wantedEntropyBytes]byte
 	if _, err := io.ReadFull(rand, tmp[:]); err != nil {
 		return nil, fmt.Errorf("%w: %w", errEntropySource, err)
 	}
 
-	xof := tuplehash.NewTupleHashXOF128([]byte("Honorary Debian/Sony RNG mitigation:" + ctx))
-	_, _ = xof.Write(k.scalar.Bytes())
 	_, _ = xof.Write(tmp[:])
 	_, _ = xof.Write(e.Bytes())
+	for _, v := range extra {
+		_, _ = xof.Write(v)
+	}
 	return xof, nil
 }
 
 func sampleRandomScalar(rand io.Reader) (*secp256k1.Scalar, error) {
-	// Do rejection sampling to ensure that there is no bias in the
-	// scalar values.  Note that the odds of a single failure are
-	// approximately p = 3.73 * 10^-39, so even requiring a single
-	// retry is unlikely unless the entropy source is broken.
-	var (
-		tmp [secp256k1.ScalarSize]byte
-		s   = secp256k1.NewScalar()
-	)
-	for i := 0; i < maxScalarResamples; i++ {
-		if _, err := io.ReadFull(rand, tmp[:]); err != nil {
-			return nil, fmt.Errorf("%w: %w", errEntropySource, err)
-		}
-
-		_, didReduce := s.SetBytes(&tmp)
-		if didReduce == 0 && s.IsZero() == 0 { // Short circuit reject is ok.
-			return s, nil
-		}
-	}
-
-	return nil, errRejectionSampling
+	return sampling.RandomScalar(rand)
 }
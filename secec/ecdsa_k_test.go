@@ -20,6 +20,7 @@ import (
 
 	"gitlab.com/yawning/secp256k1-voi"
 	"gitlab.com/yawning/secp256k1-voi/internal/helpers"
+	"gitlab.com/yawning/secp256k1-voi/internal/rfc6979"
 )
 
 func testEcdsaK(t *testing.T) {
@@ -208,6 +209,36 @@ func testEcdsaK(t *testing.T) {
 		require.ErrorIs(t, err, errEntropySource, "Sign - badReader")
 	})
 
+	t.Run("MitigateDebianAndSony/Counter", func(t *testing.T) {
+		e, err := hashToScalar(msg1Hash)
+		require.NoError(t, err, "hashToScalar")
+
+		// With a broken RNG, the counter still separates nonces.
+		readK := func(extra ...[]byte) []byte {
+			rng, err := mitigateDebianAndSony(newZeroReader(), domainSepCounter, testKey, e, extra...)
+			require.NoError(t, err, "mitigateDebianAndSony")
+
+			var k [32]byte
+			_, _ = io.ReadFull(rng, k[:])
+			return k[:]
+		}
+		k0 := readK([]byte("ctx"), []byte{0, 0, 0, 0, 0, 0, 0, 0})
+		k1 := readK([]byte("ctx"), []byte{0, 0, 0, 0, 0, 0, 0, 1})
+		require.NotEqual(t, k0, k1, "counter 0 vs 1")
+		require.Equal(t, k0, readK([]byte("ctx"), []byte{0, 0, 0, 0, 0, 0, 0, 0}), "counter 0 - again")
+		require.NotEqual(t, k0, readK([]byte("other ctx"), []byte{0, 0, 0, 0, 0, 0, 0, 0}), "ctx")
+
+		for counter := uint64(0); counter < 3; counter++ {
+			sig, err := testKey.SignASN1Counter("ctx", counter, msg1Hash)
+			require.NoError(t, err, "SignASN1Counter(%d)", counter)
+			require.True(t, testKey.PublicKey().Verify(msg1Hash, sig, nil), "Verify(%d)", counter)
+		}
+
+		badSig, err := testKey.SignASN1Counter("ctx", 0, msg1Hash[:16])
+		require.Nil(t, badSig, "SignASN1Counter - truncated")
+		require.ErrorIs(t, err, errInvalidDigest, "SignASN1Counter - truncated")
+	})
+
 	t.Run("RFC6979/SHA256/TestVectors", testRFC6979KAT)
 	t.Run("RFC6979/SHA256/DRBG", func(t *testing.T) {
 		// Since it is vanishingly unlikely that more than 1 read
@@ -222,7 +253,7 @@ func testEcdsaK(t *testing.T) {
 		e, _ := hashToScalar(msg1Hash)
 
 		var b [secp256k1.ScalarSize]byte
-		rd := newDrbgRFC6979(x, e)
+		rd := rfc6979.NewDRBG(x, e)
 		for _, expected := range [][]byte{
 			helpers.MustBytesFromHex("98b1853bf3b2798395bffd1ac98f8abaf3e0e3666268f70541890f5c884111cd"),
 			helpers.MustBytesFromHex("6f52ef0ec8d7e821316fca6780a791df875b03c73405bf4f63321c07c98ace6e"),
@@ -273,6 +304,14 @@ func testRFC6979KAT(t *testing.T) {
 			require.NoError(t, err, "Sign")
 
 			require.Equal(t, vec[fieldSignature], strings.ToUpper(hex.EncodeToString(sig)), "Sign - RFC6979")
+
+			r, s, v, err := privKey.SignRFC6979(hashMsgForTests([]byte(vec[fieldMessage])))
+			require.NoError(t, err, "SignRFC6979")
+			require.Equal(t, sig, BuildASN1Signature(r, s), "SignRFC6979")
+
+			q, err := RecoverPublicKey(hashMsgForTests([]byte(vec[fieldMessage])), r, s, v)
+			require.NoError(t, err, "RecoverPublicKey")
+			require.True(t, privKey.PublicKey().Equal(q), "SignRFC6979 - recovery ID")
 		})
 	}
 }
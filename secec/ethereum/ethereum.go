@@ -0,0 +1,195 @@
+// Copyright (c) 2023 Yawning Angel
+//
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package ethereum implements the ethereum specific primitives.
+package ethereum
+
+import (
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"math/big"
+
+	"golang.org/x/crypto/sha3"
+
+	"gitlab.com/yawning/secp256k1-voi/secec"
+)
+
+const (
+	// AddressSize is the size of an ethereum address in bytes.
+	AddressSize = 20
+
+	// vOffset is the legacy offset added to the recovery ID, as
+	// produced by `eth_sign` and friends.
+	vOffset = 27
+
+	// vOffsetEIP155 is the offset added to `chainId * 2` and the
+	// recovery ID, as specified in EIP-155.
+	vOffsetEIP155 = 35
+)
+
+var (
+	errInvalidRecoveryID = errors.New("secp256k1/secec/ethereum: invalid recovery ID")
+	errInvalidV          = errors.New("secp256k1/secec/ethereum: invalid v")
+	errChainIDOverflow   = errors.New("secp256k1/secec/ethereum: chain ID overflow")
+	errSIsHigh           = errors.New("secp256k1/secec/ethereum: s > n / 2")
+)
+
+// VerifyRecoverAddress recovers the public key from the `[R | S | V]`
+// recoverable signature `sig` of `hash`, and returns true iff the
+// address derived from the recovered public key is `expected`.
+//
+// Note: `V` may either be the raw recovery ID, or the recovery ID
+// offset by 27, and MUST only encode the parity of the y-coordinate
+// of R.  Per EIP-2, signatures where `s > n / 2` are rejected.
+func VerifyRecoverAddress(expected [AddressSize]byte, hash [32]byte, sig []byte) bool {
+	pk, err := recoverPublicKey(hash, sig)
+	if err != nil {
+		return false
+	}
+
+	addr := deriveAddress(pk)
+
+	return subtle.ConstantTimeCompare(expected[:], addr[:]) == 1
+}
+
+// EIP712Hash returns the EIP-712 digest of the `hashStruct(message)`
+// value `structHash` under the domain `domainSeparator`, which is
+// `keccak256("\x19\x01" || domainSeparator || structHash)`.
+func EIP712Hash(domainSeparator, structHash [32]byte) [32]byte {
+	h := sha3.NewLegacyKeccak256()
+	_, _ = h.Write([]byte{0x19, 0x01})
+	_, _ = h.Write(domainSeparator[:])
+	_, _ = h.Write(structHash[:])
+
+	var digest [32]byte
+	h.Sum(digest[:0])
+	return digest
+}
+
+// VerifyEIP712 verifies the `[R | S | V]` recoverable signature `sig`
+// of the EIP-712 digest of `structHash` under the domain
+// `domainSeparator`, and returns true iff it was produced by `pub`.
+//
+// Note: The same restrictions on `sig` as with VerifyRecoverAddress
+// apply, and `V` MUST be consistent with `pub`, as is the case when
+// the signature is checked with `ecrecover`.
+func VerifyEIP712(pub *secec.PublicKey, domainSeparator, structHash [32]byte, sig []byte) bool {
+	pk, err := recoverPublicKey(EIP712Hash(domainSeparator, structHash), sig)
+	if err != nil {
+		return false
+	}
+
+	return pub.Equal(pk)
+}
+
+func recoverPublicKey(hash [32]byte, sig []byte) (*secec.PublicKey, error) {
+	r, s, v, err := secec.ParseCompactRecoverableSignature(sig)
+	if err != nil {
+		return nil, err
+	}
+	if v >= vOffset {
+		v -= vOffset
+	}
+	if s.IsGreaterThanHalfN() != 0 {
+		return nil, errSIsHigh
+	}
+
+	return secec.RecoverPublicKeyStrictEthereum(hash[:], r, s, v)
+}
+
+// Address returns the EIP-55 mixed-case checksum encoding of the
+// ethereum address corresponding to `pk`, including the `0x` prefix.
+func Address(pk *secec.PublicKey) string {
+	addr := deriveAddress(pk)
+	return encodeEIP55(&addr)
+}
+
+// encodeEIP55 returns the EIP-55 mixed-case checksum encoding of `addr`.
+func encodeEIP55(addr *[AddressSize]byte) string {
+	b := []byte(hex.EncodeToString(addr[:]))
+
+	// Each letter is upper-cased iff the corresponding nibble of the
+	// Keccak-256 digest of the lower-case hex address is >= 8.
+	h := sha3.NewLegacyKeccak256()
+	_, _ = h.Write(b)
+	checksum := h.Sum(nil)
+	for i, c := range b {
+		nibble := checksum[i/2] >> (4 * (1 - i%2)) & 0x0f
+		if c >= 'a' && nibble >= 8 {
+			b[i] = c - ('a' - 'A')
+		}
+	}
+
+	return "0x" + string(b)
+}
+
+// deriveAddress returns the ethereum address corresponding to `pk`,
+// which is the right-most 20-bytes of the Keccak-256 digest of the
+// uncompressed point, sans the SEC 1 prefix.
+func deriveAddress(pk *secec.PublicKey) [AddressSize]byte {
+	h := sha3.NewLegacyKeccak256()
+	_, _ = h.Write(pk.Bytes()[1:])
+	digest := h.Sum(nil)
+
+	var addr [AddressSize]byte
+	copy(addr[:], digest[len(digest)-AddressSize:])
+	return addr
+}
+
+// DecodeEIP155V decodes the transaction signature `v` value, and returns
+// the recovery ID and chain ID.  The chain ID will be `0` for the
+// pre-EIP-155 `27/28` encoding, and for the raw `0/1` y-parity encoding
+// used by typed transactions (where the chain ID is carried separately).
+func DecodeEIP155V(v *big.Int) (byte, uint64, error) {
+	if v == nil || v.Sign() < 0 {
+		return 0, 0, errInvalidV
+	}
+
+	// All sensible values that are not EIP-155 fit in a uint64.
+	if v.IsUint64() {
+		switch vv := v.Uint64(); vv {
+		case 0, 1:
+			return byte(vv), 0, nil
+		case vOffset, vOffset + 1:
+			return byte(vv - vOffset), 0, nil
+		default:
+			if vv < vOffsetEIP155 {
+				return 0, 0, errInvalidV
+			}
+		}
+	}
+
+	// v = chainId * 2 + 35 + recoveryID
+	tmp := new(big.Int).Sub(v, big.NewInt(vOffsetEIP155))
+	recoveryID := byte(tmp.Bit(0))
+	tmp.Rsh(tmp, 1)
+	if !tmp.IsUint64() {
+		return 0, 0, errChainIDOverflow
+	}
+
+	return recoveryID, tmp.Uint64(), nil
+}
+
+// EncodeEIP155V encodes the recovery ID and chain ID into a transaction
+// signature `v` value.  If `chainID` is `0`, the pre-EIP-155 `27/28`
+// encoding will be used.  `recoveryID` MUST be `0` or `1`, as EIP-155
+// can not represent the other recovery IDs, and this will panic
+// otherwise.
+func EncodeEIP155V(recoveryID byte, chainID uint64) *big.Int {
+	if recoveryID > 1 {
+		panic(errInvalidRecoveryID)
+	}
+
+	if chainID == 0 {
+		return big.NewInt(int64(vOffset + recoveryID))
+	}
+
+	// Do this with big.Int as chainId * 2 + 36 can overflow.
+	v := new(big.Int).SetUint64(chainID)
+	v.Lsh(v, 1)
+	v.Add(v, big.NewInt(int64(vOffsetEIP155+recoveryID)))
+
+	return v
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2023 Yawning Angel
+//
+// SPDX-License-Identifier: BSD-3-Clause
+
+package ethereum
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/sha3"
+
+	"gitlab.com/yawning/secp256k1-voi/internal/helpers"
+	"gitlab.com/yawning/secp256k1-voi/secec"
+)
+
+const testMessage = "The whole thing is a bunch of people running around making money off each other."
+
+func TestEthereum(t *testing.T) {
+	t.Run("Address/KAT", func(t *testing.T) {
+		priv, err := secec.NewPrivateKey(helpers.MustBytesFromHex("0000000000000000000000000000000000000000000000000000000000000001"))
+		require.NoError(t, err, "NewPrivateKey")
+
+		addr := deriveAddress(priv.PublicKey())
+		require.EqualValues(t, helpers.MustBytesFromHex("7e5f4552091a69125d5dfcb7b8c2659029395bdf"), addr[:], "deriveAddress")
+		require.Equal(t, "0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf", Address(priv.PublicKey()), "Address")
+	})
+	t.Run("Address/EIP-55", func(t *testing.T) {
+		for _, expected := range []string{
+			"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+			"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+			"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+			"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+		} {
+			addr := (*[AddressSize]byte)(helpers.MustBytesFromHex(expected[2:]))
+			require.Equal(t, expected, encodeEIP55(addr), "encodeEIP55")
+		}
+	})
+	t.Run("VerifyRecoverAddress", func(t *testing.T) {
+		priv, err := secec.GenerateKey()
+		require.NoError(t, err, "GenerateKey")
+
+		addr := deriveAddress(priv.PublicKey())
+		msgHash := sha256.Sum256([]byte(testMessage))
+
+		opts := &secec.ECDSAOptions{
+			Encoding: secec.EncodingCompactRecoverable,
+		}
+		sig, err := priv.Sign(nil, msgHash[:], opts)
+		require.NoError(t, err, "Sign")
+
+		ok := VerifyRecoverAddress(addr, msgHash, sig)
+		require.True(t, ok, "VerifyRecoverAddress")
+
+		sig[64] += vOffset
+		ok = VerifyRecoverAddress(addr, msgHash, sig)
+		require.True(t, ok, "VerifyRecoverAddress - offset V")
+
+		var badAddr [AddressSize]byte
+		ok = VerifyRecoverAddress(badAddr, msgHash, sig)
+		require.False(t, ok, "VerifyRecoverAddress - wrong address")
+
+		ok = VerifyRecoverAddress(addr, msgHash, sig[:64])
+		require.False(t, ok, "VerifyRecoverAddress - truncated")
+	})
+	t.Run("EIP712/KAT", func(t *testing.T) {
+		// The `Mail` example from EIP-712, signed by keccak256("cow").
+		domainSeparator := (*[32]byte)(helpers.MustBytesFromHex("f2cee375fa42b42143804025fc449deafd50cc031ca257e0b194a650a912090f"))
+		structHash := (*[32]byte)(helpers.MustBytesFromHex("c52c0ee5d84264471806290a3f2c4cecfc5490626bf912d01f240d7a274b371e"))
+		expectedDigest := helpers.MustBytesFromHex("be609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2")
+		expectedAddr := helpers.MustBytesFromHex("cd2a3d9f938e13cd947ec05abc7fe734df8dd826")
+		sig := helpers.MustBytesFromHex("4355c47d63924e8a72e509b65029052eb6c299d53a04e167c5775fd466751c9d07299936d304c153f6443dfa05f40ff007d72911b6f72307f996231605b915621c")
+
+		digest := EIP712Hash(*domainSeparator, *structHash)
+		require.EqualValues(t, expectedDigest, digest[:], "EIP712Hash")
+
+		ok := VerifyRecoverAddress(*(*[AddressSize]byte)(expectedAddr), digest, sig)
+		require.True(t, ok, "VerifyRecoverAddress")
+
+		h := sha3.NewLegacyKeccak256()
+		_, _ = h.Write([]byte("cow"))
+		priv, err := secec.NewPrivateKey(h.Sum(nil))
+		require.NoError(t, err, "NewPrivateKey")
+		pub := priv.PublicKey()
+
+		ok = VerifyEIP712(pub, *domainSeparator, *structHash, sig)
+		require.True(t, ok, "VerifyEIP712")
+
+		badSig := bytes.Clone(sig)
+		badSig[64] ^= 0x01
+		ok = VerifyEIP712(pub, *domainSeparator, *structHash, badSig)
+		require.False(t, ok, "VerifyEIP712 - wrong V")
+
+		ok = VerifyEIP712(pub, *structHash, *domainSeparator, sig)
+		require.False(t, ok, "VerifyEIP712 - wrong digest")
+
+		otherPriv, err := secec.GenerateKey()
+		require.NoError(t, err, "GenerateKey")
+		ok = VerifyEIP712(otherPriv.PublicKey(), *domainSeparator, *structHash, sig)
+		require.False(t, ok, "VerifyEIP712 - wrong key")
+
+		// Per EIP-2, high s is rejected.
+		r, s, v, err := secec.ParseCompactRecoverableSignature(sig)
+		require.NoError(t, err, "ParseCompactRecoverableSignature")
+		s.Negate(s)
+		badSig = secec.BuildCompactRecoverableSignature(r, s, (v-vOffset)^1)
+		ok = VerifyEIP712(pub, *domainSeparator, *structHash, badSig)
+		require.False(t, ok, "VerifyEIP712 - high s")
+	})
+	t.Run("EIP155", func(t *testing.T) {
+		for _, tc := range []struct {
+			v          string
+			recoveryID byte
+			chainID    uint64
+		}{
+			{"27", 0, 0},
+			{"28", 1, 0},
+			{"37", 0, 1},    // Mainnet
+			{"38", 1, 1},    // Mainnet
+			{"309", 0, 137}, // Polygon
+			{"36893488147419103265", 0, math.MaxUint64},
+			{"36893488147419103266", 1, math.MaxUint64},
+		} {
+			v, ok := new(big.Int).SetString(tc.v, 10)
+			require.True(t, ok, "SetString(%s)", tc.v)
+
+			recoveryID, chainID, err := DecodeEIP155V(v)
+			require.NoError(t, err, "DecodeEIP155V(%s)", tc.v)
+			require.Equal(t, tc.recoveryID, recoveryID, "DecodeEIP155V(%s) - recoveryID", tc.v)
+			require.Equal(t, tc.chainID, chainID, "DecodeEIP155V(%s) - chainID", tc.v)
+
+			v2 := EncodeEIP155V(tc.recoveryID, tc.chainID)
+			require.EqualValues(t, 0, v.Cmp(v2), "EncodeEIP155V(%d, %d)", tc.recoveryID, tc.chainID)
+		}
+
+		// Typed transactions just use the y-parity.
+		for _, yParity := range []byte{0, 1} {
+			recoveryID, chainID, err := DecodeEIP155V(big.NewInt(int64(yParity)))
+			require.NoError(t, err, "DecodeEIP155V(%d)", yParity)
+			require.Equal(t, yParity, recoveryID, "DecodeEIP155V(%d) - recoveryID", yParity)
+			require.Zero(t, chainID, "DecodeEIP155V(%d) - chainID", yParity)
+		}
+
+		for _, v := range []string{"-1", "2", "26", "29", "34"} {
+			bad, _ := new(big.Int).SetString(v, 10)
+			_, _, err := DecodeEIP155V(bad)
+			require.ErrorIs(t, err, errInvalidV, "DecodeEIP155V(%s)", v)
+		}
+		_, _, err := DecodeEIP155V(nil)
+		require.ErrorIs(t, err, errInvalidV, "DecodeEIP155V(nil)")
+
+		tooLarge, _ := new(big.Int).SetString("36893488147419103267", 10)
+		_, _, err = DecodeEIP155V(tooLarge)
+		require.ErrorIs(t, err, errChainIDOverflow, "DecodeEIP155V(tooLarge)")
+
+		require.PanicsWithValue(t, errInvalidRecoveryID, func() {
+			EncodeEIP155V(2, 1)
+		}, "EncodeEIP155V(2, 1)")
+	})
+}
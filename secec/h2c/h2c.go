@@ -8,6 +8,10 @@ package h2c
 import (
 	"crypto"
 	_ "crypto/sha256" // Pull in SHA256
+	_ "crypto/sha512" // Pull in SHA512
+	"errors"
+
+	"golang.org/x/crypto/sha3"
 
 	"gitlab.com/yawning/secp256k1-voi"
 )
@@ -18,30 +22,74 @@ const (
 
 	encodeToCurveSize = ell
 	hashToCurveSize   = ell * 2
+
+	// NUMSDomainSeparator is the domain separator used by NUMSGenerator.
+	NUMSDomainSeparator = "secp256k1-voi_NUMS-Generator_XMD:SHA-256_SSWU_RO_"
 )
 
+var (
+	errInvalidExpander = errors.New("secp256k1/secec/h2c: invalid expander")
+	errInvalidCount    = errors.New("secp256k1/secec/h2c: invalid count")
+)
+
+// Expander is a RFC 9380 expand_message variant.
+type Expander int
+
+const (
+	// ExpanderXMDSHA256 is expand_message_xmd with SHA-256.
+	ExpanderXMDSHA256 Expander = iota
+	// ExpanderXMDSHA512 is expand_message_xmd with SHA-512.
+	ExpanderXMDSHA512
+	// ExpanderXOFSHAKE128 is expand_message_xof with SHAKE128.
+	ExpanderXOFSHAKE128
+	// ExpanderXOFSHAKE256 is expand_message_xof with SHAKE256.
+	ExpanderXOFSHAKE256
+)
+
+func (e Expander) expandMessage(out, domainSeparator, message []byte) error {
+	switch e {
+	case ExpanderXMDSHA256:
+		return expandMessageXMD(out, crypto.SHA256.New, domainSeparator, message)
+	case ExpanderXMDSHA512:
+		return expandMessageXMD(out, crypto.SHA512.New, domainSeparator, message)
+	case ExpanderXOFSHAKE128:
+		return expandMessageXOF(out, sha3.NewShake128, 128, domainSeparator, message)
+	case ExpanderXOFSHAKE256:
+		return expandMessageXOF(out, sha3.NewShake256, 256, domainSeparator, message)
+	default:
+		return errInvalidExpander
+	}
+}
+
+// HashToCurveWithHash implements the secp256k1 hash_to_curve with the
+// simplified SWU map, with the message `msg` and the domain separation
+// tag `dst`, using the expand_message variant `expander`.  With
+// `ExpanderXMDSHA256`, this is identical to `HashToCurve(msg, dst)`.
+//
+// Note: RFC 9380 only defines a suite for `ExpanderXMDSHA256`.  The
+// other expanders are provided for protocols that define their own
+// suites, and the caller is responsible for picking a suitable
+// domain separator.
+func HashToCurveWithHash(msg, dst []byte, expander Expander) (*secp256k1.Point, error) {
+	// 1. u = hash_to_field(msg, 2)
+	var uBytes [hashToCurveSize]byte
+	if err := expander.expandMessage(uBytes[:], dst, msg); err != nil {
+		return nil, err
+	}
+
+	return hashToCurve(&uBytes), nil
+}
+
 // Secp256k1_XMD_SHA256_SSWU_RO implements the secp256k1_XMD:SHA-256_SSWU_RO_
 // h2c suite.
 func Secp256k1_XMD_SHA256_SSWU_RO(domainSeparator, message []byte) (*secp256k1.Point, error) { //nolint:revive
 	// 1. u = hash_to_field(msg, 2)
 	var uBytes [hashToCurveSize]byte
-	if err := expandMessageXMD(uBytes[:], crypto.SHA256, domainSeparator, message); err != nil {
+	if err := expandMessageXMD(uBytes[:], crypto.SHA256.New, domainSeparator, message); err != nil {
 		return nil, err
 	}
 
-	// 2. Q0 = map_to_curve(u[0])
-	q0 := secp256k1.NewIdentityPoint().SetUniformBytes(uBytes[:ell])
-
-	// 3. Q1 = map_to_curve(u[1])
-	q1 := secp256k1.NewIdentityPoint().SetUniformBytes(uBytes[ell:])
-
-	// 4. R = Q0 + Q1              # Point addition
-	r := secp256k1.NewIdentityPoint().Add(q0, q1)
-
-	// 5. P = clear_cofactor(R)
-	// 6. return P
-
-	return r, nil
+	return hashToCurve(&uBytes), nil
 }
 
 // Secp256k1_XMD_SHA256_SSWU_NU implements the secp256k1_XMD:SHA-256_SSWU_NU_
@@ -49,7 +97,7 @@ func Secp256k1_XMD_SHA256_SSWU_RO(domainSeparator, message []byte) (*secp256k1.P
 func Secp256k1_XMD_SHA256_SSWU_NU(domainSeparator, message []byte) (*secp256k1.Point, error) { //nolint:revive
 	// 1. u = hash_to_field(msg, 1)
 	var uBytes [encodeToCurveSize]byte
-	if err := expandMessageXMD(uBytes[:], crypto.SHA256, domainSeparator, message); err != nil {
+	if err := expandMessageXMD(uBytes[:], crypto.SHA256.New, domainSeparator, message); err != nil {
 		return nil, err
 	}
 
@@ -61,3 +109,85 @@ func Secp256k1_XMD_SHA256_SSWU_NU(domainSeparator, message []byte) (*secp256k1.P
 
 	return q, nil
 }
+
+// HashToCurve implements the RFC 9380 hash_to_curve operation with the
+// secp256k1_XMD:SHA-256_SSWU_RO_ suite, with the message `msg` and the
+// domain separation tag `dst`.
+//
+// Note: This is `Secp256k1_XMD_SHA256_SSWU_RO(dst, msg)`, with the
+// argument order used by RFC 9380.
+func HashToCurve(msg, dst []byte) (*secp256k1.Point, error) {
+	return Secp256k1_XMD_SHA256_SSWU_RO(dst, msg)
+}
+
+// EncodeToCurve implements the RFC 9380 encode_to_curve operation with
+// the secp256k1_XMD:SHA-256_SSWU_NU_ suite, with the message `msg` and
+// the domain separation tag `dst`.
+//
+// Note: This is `Secp256k1_XMD_SHA256_SSWU_NU(dst, msg)`, with the
+// argument order used by RFC 9380.  The output distribution is not
+// uniform, and HashToCurve should be used unless the protocol
+// specifically calls for encode_to_curve.
+func EncodeToCurve(msg, dst []byte) (*secp256k1.Point, error) {
+	return Secp256k1_XMD_SHA256_SSWU_NU(dst, msg)
+}
+
+// HashToScalar implements the RFC 9380 hash_to_field operation, with
+// the modulus set to the order of the group `n`, using expand_message_xmd
+// with SHA-256, the message `msg`, and the domain separation tag `dst`,
+// and returns `count` scalars.
+//
+// Note: Each scalar is derived from `L = 48` bytes of output, so the
+// bias is negligible (`~2^-128`).
+func HashToScalar(msg, dst []byte, count int) ([]*secp256k1.Scalar, error) {
+	if count <= 0 {
+		return nil, errInvalidCount
+	}
+
+	uBytes, err := ExpandMessageXMD(crypto.SHA256.New, msg, dst, count*ell)
+	if err != nil {
+		return nil, err
+	}
+
+	scalars := make([]*secp256k1.Scalar, 0, count)
+	for i := 0; i < count; i++ {
+		s := secp256k1.NewScalar().SetWideBytes(uBytes[i*ell : (i+1)*ell])
+		scalars = append(scalars, s)
+	}
+
+	return scalars, nil
+}
+
+// NUMSGenerator derives a "nothing up my sleeve" generator from `label`,
+// with an unknown discrete logarithm relative to the canonical generator,
+// and returns it.  It is `Secp256k1_XMD_SHA256_SSWU_RO(NUMSDomainSeparator,
+// label)`, and can be reproduced by any conforming RFC 9380 implementation.
+//
+// Note: This library does not define any alternate generators itself.
+// Callers that require one (eg: for commitment schemes, or as `H` for
+// the dleq package) SHOULD derive it with this, using a protocol-specific
+// label.
+func NUMSGenerator(label []byte) *secp256k1.Point {
+	h, err := Secp256k1_XMD_SHA256_SSWU_RO([]byte(NUMSDomainSeparator), label)
+	if err != nil {
+		// This can only fail if the domain separator is invalid.
+		panic("secp256k1/secec/h2c: failed to derive NUMS generator: " + err.Error())
+	}
+
+	return h
+}
+
+func hashToCurve(uBytes *[hashToCurveSize]byte) *secp256k1.Point {
+	// 2. Q0 = map_to_curve(u[0])
+	q0 := secp256k1.NewIdentityPoint().SetUniformBytes(uBytes[:ell])
+
+	// 3. Q1 = map_to_curve(u[1])
+	q1 := secp256k1.NewIdentityPoint().SetUniformBytes(uBytes[ell:])
+
+	// 4. R = Q0 + Q1              # Point addition
+	r := secp256k1.NewIdentityPoint().Add(q0, q1)
+
+	// 5. P = clear_cofactor(R)
+	// 6. return P
+	return r
+}
@@ -6,10 +6,12 @@
 package h2c
 
 import (
-	"crypto"
 	"crypto/subtle"
 	"errors"
+	"hash"
 	"math"
+
+	"golang.org/x/crypto/sha3"
 )
 
 const oversizeDST = "H2C-OVERSIZE-DST-"
@@ -21,14 +23,36 @@ var (
 	errEllOutOfRange     = errors.New("secp256k1/secec/h2c: ell out of range")
 )
 
+// ExpandMessageXMD implements the RFC 9380 expand_message_xmd operation,
+// with the hash function `newHash`, the message `msg`, and the domain
+// separation tag `dst`, and returns `lenInBytes` bytes of uniformly
+// random data.  Domain separation tags longer than 255 bytes are hashed
+// as specified in RFC 9380 Section 5.3.3.
+//
+// Note: The hash function MUST have at least a 256-bit digest, and
+// `lenInBytes` MUST be in the range `[1, 255 * b_in_bytes]`.  Unlike
+// RFC 9380, 0-length output is rejected.
+func ExpandMessageXMD(newHash func() hash.Hash, msg, dst []byte, lenInBytes int) ([]byte, error) {
+	if lenInBytes <= 0 || lenInBytes > math.MaxUint16 {
+		return nil, errInvalidOutputSize
+	}
+
+	out := make([]byte, lenInBytes)
+	if err := expandMessageXMD(out, newHash, dst, msg); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
 // expandMessageXMD implements expand_message_xmd, overwriting out with
 // uniformly random data generated by the provided hash function, domain
 // separation tag, and message.
-func expandMessageXMD(out []byte, hFunc crypto.Hash, domainSeparator, message []byte) error {
+func expandMessageXMD(out []byte, newHash func() hash.Hash, domainSeparator, message []byte) error {
 	lenInBytes := len(out)
-	bInBytes := hFunc.Size()
 
-	h := hFunc.New()
+	h := newHash()
+	bInBytes := h.Size()
 	rInBytes := h.BlockSize()
 
 	// 0. Ensure parameters are sensible.
@@ -137,3 +161,52 @@ func expandMessageXMD(out []byte, hFunc crypto.Hash, domainSeparator, message []
 
 	return nil
 }
+
+// expandMessageXOF implements expand_message_xof, overwriting out with
+// uniformly random data generated by the provided extendable-output
+// function (with a target security level of `k` bits), domain
+// separation tag, and message.
+func expandMessageXOF(out []byte, newXOF func() sha3.ShakeHash, k int, domainSeparator, message []byte) error {
+	lenInBytes := len(out)
+
+	// 0. Ensure parameters are sensible.
+	//
+	// As with expandMessageXMD, 0-length output is rejected.
+	if lenInBytes == 0 || lenInBytes > math.MaxUint16 {
+		return errInvalidOutputSize
+	}
+
+	xof := newXOF()
+
+	// 5.3.3 Using DSTs longer than 255 bytes.
+	DST := domainSeparator
+	lenDST := len(domainSeparator)
+	switch {
+	case lenDST == 0:
+		return errInvalidDomainSep
+	case lenDST > math.MaxUint8:
+		// DST = H("H2C-OVERSIZE-DST-" || a_very_long_DST, ceil(2 * k / 8))
+		_, _ = xof.Write([]byte(oversizeDST))
+		_, _ = xof.Write(DST)
+		DST = make([]byte, 2*k/8)
+		_, _ = xof.Read(DST)
+		lenDST = len(DST)
+		xof.Reset()
+	}
+
+	// 1. ABORT if len_in_bytes > 65535 or len(DST) > 255
+	//
+	// Note: These checks are done already.
+
+	// 2. DST_prime = DST || I2OSP(len(DST), 1)
+	// 3. msg_prime = msg || I2OSP(len_in_bytes, 2) || DST_prime
+	// 4. uniform_bytes = H(msg_prime, len_in_bytes)
+	_, _ = xof.Write(message)                                         // msg
+	_, _ = xof.Write([]byte{byte(lenInBytes >> 8), byte(lenInBytes)}) // I2OSP(len_in_bytes, 2)
+	_, _ = xof.Write(DST)                                             // DST
+	_, _ = xof.Write([]byte{byte(lenDST)})                            // I2OSP(len(DST), 1)
+	_, _ = xof.Read(out)
+
+	// 5. return uniform_bytes
+	return nil
+}
@@ -8,13 +8,16 @@ package h2c
 import (
 	"crypto"
 	_ "crypto/sha1" //nolint:gosec // Used for short digest test.
-	_ "crypto/sha256"
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/sha3"
 
 	"gitlab.com/yawning/secp256k1-voi"
 	"gitlab.com/yawning/secp256k1-voi/internal/helpers"
@@ -56,30 +59,128 @@ func TestH2C(t *testing.T) {
 
 		// Our implementation requires at least a 256-bit digest.
 		var out [encodeToCurveSize]byte
-		err := expandMessageXMD(out[:], crypto.SHA1, dst, []byte("short hash"))
+		err := expandMessageXMD(out[:], crypto.SHA1.New, dst, []byte("short hash"))
 		require.ErrorIs(t, err, errInvalidDigestSize, "expandMessageXMD - SHA1")
 
 		// Our implementation rejects 0-length DSTs.
-		err = expandMessageXMD(out[:], crypto.SHA256, []byte{}, []byte("zero DST"))
+		err = expandMessageXMD(out[:], crypto.SHA256.New, []byte{}, []byte("zero DST"))
 		require.ErrorIs(t, err, errInvalidDomainSep, "expandMessageXMD - 0 length dst")
 
 		// Our implementation rejects 0-length output, even if the RFC does not.
-		err = expandMessageXMD(out[:0], crypto.SHA256, dst, []byte("zero output"))
+		err = expandMessageXMD(out[:0], crypto.SHA256.New, dst, []byte("zero output"))
 		require.ErrorIs(t, err, errInvalidOutputSize, "expandMessageXMD - 0 length output")
 
 		// The RFC calls for rejecting outputs larger than > 2^16-1.
 		// Though, this case can never happen (see the ell tests).
-		err = expandMessageXMD(make([]byte, 65536), crypto.SHA256, dst, []byte("oversize output"))
+		err = expandMessageXMD(make([]byte, 65536), crypto.SHA256.New, dst, []byte("oversize output"))
 		require.ErrorIs(t, err, errInvalidOutputSize, "expandMessageXMD - oversize output")
 
 		// ell = ceil(len_in_bytes / b_in_bytes), up to 255
-		err = expandMessageXMD(make([]byte, 8161), crypto.SHA256, dst, []byte("oversize ell"))
+		err = expandMessageXMD(make([]byte, 8161), crypto.SHA256.New, dst, []byte("oversize ell"))
 		require.ErrorIs(t, err, errEllOutOfRange, "expandMessageXMD - oversize ell")
 
-		err = expandMessageXMD(make([]byte, 8160), crypto.SHA256, dst, []byte("maximum ell"))
+		err = expandMessageXMD(make([]byte, 8160), crypto.SHA256.New, dst, []byte("maximum ell"))
 		require.NoError(t, err, "expandMessageXMD - maximum ell")
 	})
 
+	t.Run("ExpandMessageXMD", func(t *testing.T) {
+		dst := []byte("QUUX-V01-CS02-with-expander-SHA256-128")
+
+		// RFC 9380 Appendix K.1, msg = "abc", len_in_bytes = 0x20.
+		out, err := ExpandMessageXMD(sha256.New, []byte("abc"), dst, 0x20)
+		require.NoError(t, err, "ExpandMessageXMD")
+		require.Equal(t, helpers.MustBytesFromHex("d8ccab23b5985ccea865c6c97b6e5b8350e794e603b4b97902f53a8a0d605615"), out, "ExpandMessageXMD")
+
+		out, err = ExpandMessageXMD(sha256.New, []byte("abc"), dst, 0)
+		require.Nil(t, out, "ExpandMessageXMD - 0 length output")
+		require.ErrorIs(t, err, errInvalidOutputSize, "ExpandMessageXMD - 0 length output")
+
+		_, err = ExpandMessageXMD(sha256.New, []byte("abc"), dst, 255*sha256.Size+1)
+		require.ErrorIs(t, err, errEllOutOfRange, "ExpandMessageXMD - oversize ell")
+		_, err = ExpandMessageXMD(sha256.New, []byte("abc"), dst, 1<<16)
+		require.ErrorIs(t, err, errInvalidOutputSize, "ExpandMessageXMD - oversize output")
+	})
+	t.Run("HashToScalar", func(t *testing.T) {
+		dst := []byte("secp256k1-voi_HashToScalar_test")
+		msg := []byte("hash_to_field")
+
+		nBig, _ := new(big.Int).SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
+
+		scalars, err := HashToScalar(msg, dst, 3)
+		require.NoError(t, err, "HashToScalar")
+		require.Len(t, scalars, 3, "HashToScalar")
+
+		uBytes, err := ExpandMessageXMD(sha256.New, msg, dst, 3*ell)
+		require.NoError(t, err, "ExpandMessageXMD")
+		for i, s := range scalars {
+			v := new(big.Int).SetBytes(uBytes[i*ell : (i+1)*ell])
+			v.Mod(v, nBig)
+
+			var expected [secp256k1.ScalarSize]byte
+			require.Equal(t, v.FillBytes(expected[:]), s.Bytes(), "[%d]: HashToScalar - big.Int", i)
+		}
+
+		_, err = HashToScalar(msg, dst, 0)
+		require.ErrorIs(t, err, errInvalidCount, "HashToScalar - 0 count")
+		_, err = HashToScalar(msg, nil, 1)
+		require.ErrorIs(t, err, errInvalidDomainSep, "HashToScalar - 0 length dst")
+	})
+	t.Run("ExpandMessage/OtherHashes", func(t *testing.T) {
+		// RFC 9380 Appendix K.3, K.5 and K.6, msg = "", len_in_bytes = 0x20.
+		for _, tc := range []struct {
+			expander Expander
+			dst      string
+			expected string
+		}{
+			{ExpanderXMDSHA512, "QUUX-V01-CS02-with-expander-SHA512-256", "6b9a7312411d92f921c6f68ca0b6380730a1a4d982c507211a90964c394179ba"},
+			{ExpanderXOFSHAKE128, "QUUX-V01-CS02-with-expander-SHAKE128", "86518c9cd86581486e9485aa74ab35ba150d1c75c88e26b7043e44e2acd735a2"},
+			{ExpanderXOFSHAKE256, "QUUX-V01-CS02-with-expander-SHAKE256", "2ffc05c48ed32b95d72e807f6eab9f7530dd1c2f013914c8fed38c5ccc15ad76"},
+
+			// RFC 9380 Appendix K.5, DST longer than 255 bytes.
+			{ExpanderXOFSHAKE128, "QUUX-V01-CS02-with-expander-SHAKE128-long-DST-" + strings.Repeat("1", 210), "827c6216330a122352312bccc0c8d6e7a146c5257a776dbd9ad9d75cd880fc53"},
+		} {
+			var out [32]byte
+			err := tc.expander.expandMessage(out[:], []byte(tc.dst), nil)
+			require.NoError(t, err, "expandMessage(%d)", tc.expander)
+			require.Equal(t, helpers.MustBytesFromHex(tc.expected), out[:], "expandMessage(%d)", tc.expander)
+		}
+
+		// RFC 9380 Section 5.3.3, oversized DSTs are replaced with
+		// `H("H2C-OVERSIZE-DST-" || DST, ceil(2 * k / 8))`.
+		longDST := []byte("secp256k1-voi_long-DST_" + strings.Repeat("2", 256))
+		for _, tc := range []struct {
+			expander Expander
+			newXOF   func() sha3.ShakeHash
+			k        int
+		}{
+			{ExpanderXOFSHAKE128, sha3.NewShake128, 128},
+			{ExpanderXOFSHAKE256, sha3.NewShake256, 256},
+		} {
+			xof := tc.newXOF()
+			_, _ = xof.Write([]byte(oversizeDST))
+			_, _ = xof.Write(longDST)
+			hashedDST := make([]byte, 2*tc.k/8)
+			_, _ = xof.Read(hashedDST)
+
+			var expected, out [32]byte
+			err := tc.expander.expandMessage(expected[:], hashedDST, []byte("abc"))
+			require.NoError(t, err, "expandMessage(%d) - hashed DST", tc.expander)
+			err = tc.expander.expandMessage(out[:], longDST, []byte("abc"))
+			require.NoError(t, err, "expandMessage(%d) - long DST", tc.expander)
+			require.Equal(t, expected, out, "expandMessage(%d) - long DST", tc.expander)
+		}
+
+		var out [encodeToCurveSize]byte
+		err := ExpanderXOFSHAKE128.expandMessage(out[:], []byte{}, []byte("zero DST"))
+		require.ErrorIs(t, err, errInvalidDomainSep, "expandMessageXOF - 0 length dst")
+
+		err = ExpanderXOFSHAKE128.expandMessage(out[:0], []byte("DST"), []byte("zero output"))
+		require.ErrorIs(t, err, errInvalidOutputSize, "expandMessageXOF - 0 length output")
+
+		err = Expander(69).expandMessage(out[:], []byte("DST"), []byte("bad expander"))
+		require.ErrorIs(t, err, errInvalidExpander, "expandMessage - invalid expander")
+	})
+
 	suiteTestDefs := []h2cSuiteTestDef{
 		{
 			n:    "Suite/secp256k1_XMD:SHA-256_SSWU_RO_",
@@ -91,6 +192,20 @@ func TestH2C(t *testing.T) {
 			file: "testdata/secp256k1_XMD_SHA-256_SSWU_NU_.json",
 			fn:   Secp256k1_XMD_SHA256_SSWU_NU,
 		},
+		{
+			n:    "HashToCurve",
+			file: "testdata/secp256k1_XMD_SHA-256_SSWU_RO_.json",
+			fn: func(dst, msg []byte) (*secp256k1.Point, error) {
+				return HashToCurve(msg, dst)
+			},
+		},
+		{
+			n:    "EncodeToCurve",
+			file: "testdata/secp256k1_XMD_SHA-256_SSWU_NU_.json",
+			fn: func(dst, msg []byte) (*secp256k1.Point, error) {
+				return EncodeToCurve(msg, dst)
+			},
+		},
 	}
 	for i, suiteTest := range suiteTestDefs {
 		t.Run(suiteTest.n, func(t *testing.T) {
@@ -112,6 +227,43 @@ func TestH2C(t *testing.T) {
 		p, err = Secp256k1_XMD_SHA256_SSWU_NU([]byte{}, m)
 		require.Nil(t, p, "NU - 0 length dst")
 		require.Error(t, err, "NU - 0 length dst")
+
+		p, err = HashToCurveWithHash(m, []byte{}, ExpanderXOFSHAKE256)
+		require.Nil(t, p, "HashToCurveWithHash - 0 length dst")
+		require.Error(t, err, "HashToCurveWithHash - 0 length dst")
+	})
+
+	t.Run("HashToCurveWithHash", func(t *testing.T) {
+		dst := []byte("QUUX-V01-CS02-with-secp256k1_XMD:SHA-256_SSWU_RO_")
+		m := []byte("abc")
+
+		expected, err := Secp256k1_XMD_SHA256_SSWU_RO(dst, m)
+		require.NoError(t, err, "Secp256k1_XMD_SHA256_SSWU_RO")
+
+		p, err := HashToCurveWithHash(m, dst, ExpanderXMDSHA256)
+		require.NoError(t, err, "HashToCurveWithHash - SHA256")
+		require.EqualValues(t, 1, expected.Equal(p), "HashToCurveWithHash - SHA256")
+
+		for _, expander := range []Expander{ExpanderXMDSHA512, ExpanderXOFSHAKE128, ExpanderXOFSHAKE256} {
+			p, err = HashToCurveWithHash(m, dst, expander)
+			require.NoError(t, err, "HashToCurveWithHash(%d)", expander)
+			require.EqualValues(t, 0, p.IsIdentity(), "HashToCurveWithHash(%d)", expander)
+			require.EqualValues(t, 0, expected.Equal(p), "HashToCurveWithHash(%d)", expander)
+		}
+	})
+	t.Run("NUMSGenerator", func(t *testing.T) {
+		label := []byte("secp256k1-voi/test/H")
+
+		h := NUMSGenerator(label)
+		require.EqualValues(t, 0, h.IsIdentity(), "NUMSGenerator - identity")
+		require.EqualValues(t, 0, h.Equal(secp256k1.NewGeneratorPoint()), "NUMSGenerator - G")
+
+		expected, err := Secp256k1_XMD_SHA256_SSWU_RO([]byte(NUMSDomainSeparator), label)
+		require.NoError(t, err, "Secp256k1_XMD_SHA256_SSWU_RO")
+		require.EqualValues(t, 1, expected.Equal(h), "NUMSGenerator - reproducible")
+
+		h2 := NUMSGenerator([]byte("secp256k1-voi/test/H2"))
+		require.EqualValues(t, 0, h.Equal(h2), "NUMSGenerator - distinct labels")
 	})
 }
 
@@ -186,7 +338,7 @@ func testExpandMessage(t *testing.T, def *h2cExpandTestDef) {
 			expectedU := helpers.MustBytesFromHex(vec.UniformBytes)
 			out := make([]byte, len(expectedU))
 
-			err := expandMessageXMD(out, def.h, []byte(testVectors.DST), []byte(vec.Msg))
+			err := expandMessageXMD(out, def.h.New, []byte(testVectors.DST), []byte(vec.Msg))
 			require.NoError(t, err, "ExpandMessageXMD(out, h, dst, msg)")
 			require.Equal(t, expectedU, out, "ExpandMessageXMD(out, h, dst, msg)")
 		})
@@ -0,0 +1,159 @@
+// Copyright (c) 2023 Yawning Angel
+//
+// SPDX-License-Identifier: BSD-3-Clause
+
+package secec
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"gitlab.com/yawning/secp256k1-voi"
+)
+
+const (
+	// JWKCurveSecp256k1 is the RFC 8812 JSON Web Key `crv` value for
+	// secp256k1.
+	JWKCurveSecp256k1 = "secp256k1"
+	// JWKCurveP256K is the legacy (pre-RFC 8812) JSON Web Key `crv`
+	// value for secp256k1, as used by WebCrypto and some JOSE libraries.
+	JWKCurveP256K = "P-256K"
+
+	jwkKtyEc = "EC"
+)
+
+var (
+	jwkEncoding = base64.RawURLEncoding.Strict()
+
+	errInvalidJWK      = errors.New("secp256k1/secec: invalid JSON Web Key")
+	errInvalidJWKCurve = errors.New("secp256k1/secec: JSON Web Key curve is not secp256k1")
+)
+
+type jsonWebKey struct {
+	KeyType string `json:"kty"`
+	Crv     string `json:"crv"`
+	X       string `json:"x"`
+	Y       string `json:"y"`
+	D       string `json:"d,omitempty"`
+}
+
+// MarshalJWKPublicKey returns the JSON Web Key (RFC 7517/RFC 7518)
+// encoding of the public key, using `crv` as the curve name, which
+// MUST be `JWKCurveSecp256k1`, `JWKCurveP256K`, or empty (defaulting
+// to `JWKCurveSecp256k1`).
+func MarshalJWKPublicKey(k *PublicKey, crv string) ([]byte, error) {
+	jwk, err := newJSONWebKey(k, crv)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(jwk)
+}
+
+// ParseJWKPublicKey parses a JSON Web Key (RFC 7517/RFC 7518) encoded
+// public key.  Both `JWKCurveSecp256k1` and `JWKCurveP256K` are accepted
+// as the curve name, and the point MUST be on the curve.  Any private
+// key material is ignored.
+func ParseJWKPublicKey(data []byte) (*PublicKey, error) {
+	var jwk jsonWebKey
+	if err := json.Unmarshal(data, &jwk); err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidJWK, err)
+	}
+
+	return jwk.publicKey()
+}
+
+// MarshalJWKPrivateKey returns the JSON Web Key (RFC 7517/RFC 7518)
+// encoding of the private key, including the public key, using `crv`
+// as the curve name, as with `MarshalJWKPublicKey`.
+func MarshalJWKPrivateKey(k *PrivateKey, crv string) ([]byte, error) {
+	if k.isZeroized() {
+		return nil, errZeroizedKey
+	}
+
+	jwk, err := newJSONWebKey(k.publicKey, crv)
+	if err != nil {
+		return nil, err
+	}
+	jwk.D = jwkEncoding.EncodeToString(k.scalar.Bytes())
+
+	return json.Marshal(jwk)
+}
+
+// ParseJWKPrivateKey parses a JSON Web Key (RFC 7517/RFC 7518) encoded
+// private key.  Both `JWKCurveSecp256k1` and `JWKCurveP256K` are accepted
+// as the curve name, and the public key MUST match the private key.
+func ParseJWKPrivateKey(data []byte) (*PrivateKey, error) {
+	var jwk jsonWebKey
+	if err := json.Unmarshal(data, &jwk); err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidJWK, err)
+	}
+
+	pub, err := jwk.publicKey()
+	if err != nil {
+		return nil, err
+	}
+
+	dBytes, err := jwkEncoding.DecodeString(jwk.D)
+	if err != nil || len(dBytes) != PrivateKeySize {
+		return nil, errInvalidJWK
+	}
+
+	k, err := NewPrivateKey(dBytes)
+	if err != nil {
+		return nil, err
+	}
+	if !k.publicKey.Equal(pub) {
+		return nil, fmt.Errorf("%w: public key mismatch", errInvalidJWK)
+	}
+
+	return k, nil
+}
+
+func newJSONWebKey(k *PublicKey, crv string) (*jsonWebKey, error) {
+	switch crv {
+	case "":
+		crv = JWKCurveSecp256k1
+	case JWKCurveSecp256k1, JWKCurveP256K:
+	default:
+		return nil, errInvalidJWKCurve
+	}
+
+	// Uncompressed SEC 1 encoding: 0x04 || x || y
+	pointBytes := k.pointBytes
+	return &jsonWebKey{
+		KeyType: jwkKtyEc,
+		Crv:     crv,
+		X:       jwkEncoding.EncodeToString(pointBytes[1 : 1+secp256k1.CoordSize]),
+		Y:       jwkEncoding.EncodeToString(pointBytes[1+secp256k1.CoordSize:]),
+	}, nil
+}
+
+func (jwk *jsonWebKey) publicKey() (*PublicKey, error) {
+	if jwk.KeyType != jwkKtyEc {
+		return nil, fmt.Errorf("%w: kty is not EC", errInvalidJWK)
+	}
+	switch jwk.Crv {
+	case JWKCurveSecp256k1, JWKCurveP256K:
+	default:
+		return nil, errInvalidJWKCurve
+	}
+
+	xBytes, err := jwkEncoding.DecodeString(jwk.X)
+	if err != nil || len(xBytes) != secp256k1.CoordSize {
+		return nil, fmt.Errorf("%w: invalid x", errInvalidJWK)
+	}
+	yBytes, err := jwkEncoding.DecodeString(jwk.Y)
+	if err != nil || len(yBytes) != secp256k1.CoordSize {
+		return nil, fmt.Errorf("%w: invalid y", errInvalidJWK)
+	}
+
+	pt, err := secp256k1.NewPointFromCoords((*[secp256k1.CoordSize]byte)(xBytes), (*[secp256k1.CoordSize]byte)(yBytes))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidJWK, err)
+	}
+
+	return NewPublicKeyFromPoint(pt)
+}
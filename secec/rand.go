@@ -0,0 +1,31 @@
+// Copyright (c) 2023 Yawning Angel
+//
+// SPDX-License-Identifier: BSD-3-Clause
+
+package secec
+
+import (
+	"io"
+
+	"golang.org/x/crypto/sha3"
+)
+
+const domainSepDeterministicRand = "secp256k1-voi/secec/DeterministicRand"
+
+// DeterministicRand returns an [io.Reader] that produces a deterministic
+// stream of bytes derived from `seed` with cSHAKE128, for the purpose
+// of reproducible simulations and tests.
+//
+// All of the routines in this package (and sub-packages) that take an
+// entropy source are deterministic given the output of the entropy
+// source.  In particular, while ECDSA signing mixes the private key and
+// the digest into the nonce derivation, the result is still a fixed
+// function of the entropy read.
+//
+// WARNING: The output is entirely determined by `seed`.  Do not use
+// this for anything other than simulations or testing.
+func DeterministicRand(seed []byte) io.Reader {
+	xof := sha3.NewCShake128(nil, []byte(domainSepDeterministicRand))
+	_, _ = xof.Write(seed)
+	return xof
+}
@@ -6,6 +6,7 @@ package secec
 
 import (
 	stdasn1 "encoding/asn1"
+	"encoding/pem"
 	"errors"
 	"math/big"
 
@@ -22,20 +23,76 @@ const (
 	// CompactRecoverableSignatureSize is the size of a compact recoverable
 	// signature in bytes.
 	CompactRecoverableSignatureSize = 65
+
+	// VersionedPrivateKeySize is the size of a versioned private key
+	// in bytes.
+	VersionedPrivateKeySize = 2 + PrivateKeySize
+
+	versionedFormatV1     = 0x01
+	versionedTypePrivate  = 0x01
+	versionedHeaderLength = 2
+
+	// sshKeyTypeSecp256k1 and sshCurveSecp256k1 are the RFC 5656
+	// identifiers for secp256k1, which is not one of the named curves,
+	// and thus uses the ASCII representation of the OID.
+	sshKeyTypeSecp256k1 = "ecdsa-sha2-" + sshCurveSecp256k1
+	sshCurveSecp256k1   = "1.3.132.0.10"
+
+	pemTypePublicKey = "PUBLIC KEY"
 )
 
 var (
 	oidEcPublicKey = stdasn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
-	oidSecp256k1   = stdasn1.ObjectIdentifier{1, 3, 132, 0, 10}
+	oidSecp256k1   = secp256k1.Parameters().OID
 
 	errInvalidAsn1SPKI  = errors.New("secp256k1/secec: invalid ASN.1 Subject Public Key Info")
 	errInvalidAsn1Algo  = errors.New("secp256k1/secec: algorithm is not ecPublicKey")
 	errInvalidAsn1Curve = errors.New("secp256k1/secec: named curve is not secp256k1")
+	errInvalidPEM       = errors.New("secp256k1/secec: invalid PEM public key")
 
 	errInvalidAsn1Sig    = errors.New("secp256k1/secec: invalid ASN.1 signature")
 	errInvalidCompactSig = errors.New("secp256k1/secec: invalid compact signature")
+
+	errInvalidSSHKey     = errors.New("secp256k1/secec: invalid OpenSSH public key")
+	errInvalidSSHKeyType = errors.New("secp256k1/secec: OpenSSH key type is not secp256k1")
+
+	errInvalidVersionedKey = errors.New("secp256k1/secec: invalid versioned key")
+	errUnsupportedVersion  = errors.New("secp256k1/secec: unsupported versioned key version")
+	errUnexpectedKeyType   = errors.New("secp256k1/secec: unexpected versioned key type")
 )
 
+// MarshalVersioned returns the self-describing versioned encoding of
+// the private key, `version || type || key`, intended for persistent
+// storage.  It panics if `k` has been zeroized.
+func (k *PrivateKey) MarshalVersioned() []byte {
+	b := make([]byte, 0, VersionedPrivateKeySize)
+	b = append(b, versionedFormatV1, versionedTypePrivate)
+	b = append(b, k.mustScalar().Bytes()...)
+	return b
+}
+
+// UnmarshalVersionedPrivateKey parses a versioned private key, as
+// produced by `PrivateKey.MarshalVersioned`, and returns a PrivateKey.
+func UnmarshalVersionedPrivateKey(data []byte) (*PrivateKey, error) {
+	if len(data) < versionedHeaderLength {
+		return nil, errInvalidVersionedKey
+	}
+
+	switch data[0] {
+	case versionedFormatV1:
+	default:
+		return nil, errUnsupportedVersion
+	}
+	if data[1] != versionedTypePrivate {
+		return nil, errUnexpectedKeyType
+	}
+	if len(data) != VersionedPrivateKeySize {
+		return nil, errInvalidVersionedKey
+	}
+
+	return NewPrivateKey(data[versionedHeaderLength:])
+}
+
 // ParseASN1PublicKey parses an ASN.1 encoded public key as specified in
 // SEC 1, Version 2.0, Appendix C.3.
 //
@@ -74,6 +131,24 @@ func ParseASN1PublicKey(data []byte) (*PublicKey, error) {
 	return NewPublicKey(encodedPoint)
 }
 
+// VerifyASN1PEM verifies the ASN.1 encoded signature `sig` of `hash`,
+// using the PEM encoded (`PUBLIC KEY`) ASN.1 Subject Public Key Info
+// `pemPublicKey`.  It returns an error iff the public key could not be
+// parsed, and otherwise records whether the signature is valid.
+func VerifyASN1PEM(pemPublicKey, hash, sig []byte) (bool, error) {
+	block, _ := pem.Decode(pemPublicKey)
+	if block == nil || block.Type != pemTypePublicKey {
+		return false, errInvalidPEM
+	}
+
+	k, err := ParseASN1PublicKey(block.Bytes)
+	if err != nil {
+		return false, err
+	}
+
+	return k.Verify(hash, sig, nil), nil
+}
+
 // ParseASN1Signature parses an ASN.1 encoded signature as specified in
 // SEC 1, Version 2.0, Appendix C.8, and returns the scalars `(r, s)`.
 //
@@ -81,6 +156,15 @@ func ParseASN1PublicKey(data []byte) (*PublicKey, error) {
 // as in encoded as a `ECDSA-Sig-Value`, WITHOUT the optional `a` and
 // `y` fields.  Both `r` and `s` MUST be in the range `[1, n)`.
 func ParseASN1Signature(data []byte) (*secp256k1.Scalar, *secp256k1.Scalar, error) {
+	r, s := secp256k1.NewScalar(), secp256k1.NewScalar()
+	if err := parseASN1Signature(r, s, data); err != nil {
+		return nil, nil, err
+	}
+
+	return r, s, nil
+}
+
+func parseASN1Signature(r, s *secp256k1.Scalar, data []byte) error {
 	var (
 		inner          cryptobyte.String
 		rBytes, sBytes []byte
@@ -92,19 +176,163 @@ func ParseASN1Signature(data []byte) (*secp256k1.Scalar, *secp256k1.Scalar, erro
 		!inner.ReadASN1Integer(&rBytes) ||
 		!inner.ReadASN1Integer(&sBytes) ||
 		!inner.Empty() {
-		return nil, nil, errInvalidAsn1Sig
+		return errInvalidAsn1Sig
 	}
 
-	r, err := bytesToCanonicalScalar(rBytes)
-	if err != nil || r.IsZero() != 0 {
-		return nil, nil, errInvalidScalar
+	if err := setCanonicalScalarBytes(r, rBytes); err != nil || r.IsZero() != 0 {
+		return errInvalidScalar
 	}
-	s, err := bytesToCanonicalScalar(sBytes)
-	if err != nil || s.IsZero() != 0 {
-		return nil, nil, errInvalidScalar
+	if err := setCanonicalScalarBytes(s, sBytes); err != nil || s.IsZero() != 0 {
+		return errInvalidScalar
 	}
 
-	return r, s, nil
+	return nil
+}
+
+// ParseOpenSSHPublicKey parses an OpenSSH wire-format public key as
+// specified in RFC 5656, Section 3.1, with the secp256k1 specific
+// identifiers as specified in RFC 5656, Section 6.1.
+//
+// Note: This is the raw wire-format, not the base64 encoded form used
+// in `authorized_keys` files.
+func ParseOpenSSHPublicKey(data []byte) (*PublicKey, error) {
+	var keyType, curve, q []byte
+
+	input := cryptobyte.String(data)
+	if !readSSHString(&input, &keyType) ||
+		!readSSHString(&input, &curve) ||
+		!readSSHString(&input, &q) ||
+		!input.Empty() {
+		return nil, errInvalidSSHKey
+	}
+
+	if string(keyType) != sshKeyTypeSecp256k1 || string(curve) != sshCurveSecp256k1 {
+		return nil, errInvalidSSHKeyType
+	}
+
+	// RFC 5656 mandates the uncompressed format (unless compression
+	// was negotiated), but NewPublicKey handles all of the formats.
+	return NewPublicKey(q)
+}
+
+func readSSHString(s *cryptobyte.String, out *[]byte) bool {
+	var l uint32
+	return s.ReadUint32(&l) && s.ReadBytes(out, int(l))
+}
+
+// MarshalOpenSSHPublicKey serializes `pk` into an OpenSSH wire-format
+// public key as specified in RFC 5656, Section 3.1.
+func MarshalOpenSSHPublicKey(pk *PublicKey) []byte {
+	var b cryptobyte.Builder
+	b.AddUint32LengthPrefixed(func(b *cryptobyte.Builder) {
+		b.AddBytes([]byte(sshKeyTypeSecp256k1))
+	})
+	b.AddUint32LengthPrefixed(func(b *cryptobyte.Builder) {
+		b.AddBytes([]byte(sshCurveSecp256k1))
+	})
+	b.AddUint32LengthPrefixed(func(b *cryptobyte.Builder) {
+		b.AddBytes(pk.Bytes()) // Uncompressed SEC1 format.
+	})
+
+	return b.BytesOrPanic()
+}
+
+// IsStrictDER returns true iff `data` is a strict (minimal) DER encoded
+// ASN.1 signature, per the rules specified in BIP-0066, sans the
+// trailing `sighash` byte.
+//
+// Note: This only checks the encoding, and does not check that `r`
+// and `s` are in the range `[1, n)`.
+//
+// See: https://github.com/bitcoin/bips/blob/master/bip-0066.mediawiki
+func IsStrictDER(data []byte) bool {
+	// Note: This is the BIP-0066 IsValidSignatureEncoding, with all
+	// of the length accounting adjusted to exclude the sighash byte.
+	//
+	// Format: 0x30 [total-length] 0x02 [R-length] [R] 0x02 [S-length] [S]
+
+	const asn1IsCompound = 0x20
+
+	lenSig := len(data)
+
+	// Minimum and maximum size constraints.
+	switch {
+	case lenSig < 8:
+		return false
+	case lenSig > 72:
+		return false
+	}
+
+	// A signature is of type 0x30 (compound).
+	if data[0] != (stdasn1.TagSequence | asn1IsCompound) {
+		return false
+	}
+
+	// Make sure the length covers the entire signature.
+	if int(data[1]) != lenSig-2 {
+		return false
+	}
+
+	// Extract the length of the R element.
+	lenR := int(data[3])
+
+	// Make sure the length of the S element is still inside the signature.
+	if 5+lenR >= lenSig {
+		return false
+	}
+
+	// Extract the length of the S element.
+	lenS := int(data[5+lenR])
+
+	// Verify that the length of the signature matches the sum of the length
+	// of the elements.
+	if lenR+lenS+6 != lenSig {
+		return false
+	}
+
+	// Check whether the R element is an integer.
+	if data[2] != stdasn1.TagInteger {
+		return false
+	}
+
+	// Zero-length integers are not allowed for R.
+	if lenR == 0 {
+		return false
+	}
+
+	// Negative numbers are not allowed for R.
+	if data[4]&0x80 != 0x00 {
+		return false
+	}
+
+	// Null bytes at the start of R are not allowed, unless R would
+	// otherwise be interpreted as a negative number.
+	if lenR > 1 && (data[4] == 0x00) && (data[5]&0x80 == 0x00) {
+		return false
+	}
+
+	// Check whether the S element is an integer.
+	if data[lenR+4] != stdasn1.TagInteger {
+		return false
+	}
+
+	// Zero-length integers are not allowed for S.
+	if lenS == 0 {
+		return false
+	}
+
+	// Negative numbers are not allowed for S.
+	if data[lenR+6]&0x80 != 0x00 {
+		return false
+	}
+
+	// Null bytes at the start of S are not allowed, unless S would otherwise be
+	// interpreted as a negative number.
+	if lenS > 1 && (data[lenR+6] == 0x00) && (data[lenR+7]&0x80 == 0x00) {
+		return false
+	}
+
+	return true
 }
 
 // BuildASN1Signature serializes `(r, s)` into an ASN.1 encoded signature
@@ -149,6 +377,29 @@ func BuildCompactSignature(r, s *secp256k1.Scalar) []byte {
 	return buildCompactSignature(r, s, false)
 }
 
+// ParseCompactSignatureLowS parses a "compact" `[R | S]` signature, and
+// returns the scalars `(r, s)`.  `r` MUST be in the range `[1, n)`, and
+// `s` MUST be in the range `[1, n / 2]`, as required by BOLT #1.
+func ParseCompactSignatureLowS(data []byte) (*secp256k1.Scalar, *secp256k1.Scalar, error) {
+	r, s, err := ParseCompactSignature(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	if s.IsGreaterThanHalfN() != 0 {
+		return nil, nil, errInvalidScalar
+	}
+
+	return r, s, nil
+}
+
+// BuildCompactSignatureLowS serializes `(r, s)` into a "compact"
+// `[R | S]` signature, normalizing `s` such that it is less than or
+// equal to `n / 2`, as required by BOLT #1.
+func BuildCompactSignatureLowS(r, s *secp256k1.Scalar) []byte {
+	s = secp256k1.NewScalar().ConditionalNegate(s, s.IsGreaterThanHalfN())
+	return buildCompactSignature(r, s, false)
+}
+
 // ParseCompactRecoverableSignature parses a "compact" `[R | S | V]`
 // signature, and returns the scalars `(r, s)` and recovery ID `v`.
 // Both `r` and `s` MUST be in the range `[1, n)`.  `v` MUST be in
@@ -175,6 +426,45 @@ func BuildCompactRecoverableSignature(r, s *secp256k1.Scalar, v byte) []byte {
 	return dst
 }
 
+// ParseEIP2098Signature parses an EIP-2098 `[R | yParityAndS]` compact
+// signature, and returns the scalars `(r, s)` and recovery ID `v`.
+// Both `r` and `s` MUST be in the range `[1, n)`.  `v` will be in the
+// range `[0,1]`.
+func ParseEIP2098Signature(data []byte) (*secp256k1.Scalar, *secp256k1.Scalar, byte, error) {
+	if len(data) != CompactSignatureSize {
+		return nil, nil, 0, errInvalidCompactSig
+	}
+
+	var tmp [CompactSignatureSize]byte
+	copy(tmp[:], data)
+	v := tmp[32] >> 7
+	tmp[32] &= 0x7f
+
+	r, s, err := ParseCompactSignature(tmp[:])
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	return r, s, v, nil
+}
+
+// BuildEIP2098Signature serializes `(r, s, v)` into an EIP-2098
+// `[R | yParityAndS]` compact signature.  `s` MUST be less than or
+// equal to `n / 2`, and `v` MUST be in the range `[0,1]`.
+func BuildEIP2098Signature(r, s *secp256k1.Scalar, v byte) ([]byte, error) {
+	if s.IsGreaterThanHalfN() != 0 {
+		return nil, errInvalidScalar
+	}
+	if v > 1 {
+		return nil, errInvalidRecoveryID
+	}
+
+	dst := buildCompactSignature(r, s, false)
+	dst[32] |= v << 7
+
+	return dst, nil
+}
+
 func buildCompactSignature(r, s *secp256k1.Scalar, allocV bool) []byte {
 	l := CompactSignatureSize
 	if allocV {
@@ -200,19 +490,18 @@ func buildASN1PublicKey(pk *PublicKey) []byte {
 	return b.BytesOrPanic()
 }
 
-func bytesToCanonicalScalar(sBytes []byte) (*secp256k1.Scalar, error) {
+func setCanonicalScalarBytes(dst *secp256k1.Scalar, sBytes []byte) error {
 	sLen := len(sBytes)
 	if sLen > secp256k1.ScalarSize || sLen == 0 {
-		return nil, errInvalidScalar
+		return errInvalidScalar
 	}
 
 	var tmp [secp256k1.ScalarSize]byte
 	copy(tmp[secp256k1.ScalarSize-sLen:], sBytes)
 
-	s, err := secp256k1.NewScalarFromCanonicalBytes(&tmp)
-	if err != nil {
-		return nil, errInvalidScalar
+	if _, err := dst.SetCanonicalBytes(&tmp); err != nil {
+		return errInvalidScalar
 	}
 
-	return s, nil
+	return nil
 }
@@ -0,0 +1,231 @@
+// Copyright (c) 2023 Yawning Angel
+//
+// SPDX-License-Identifier: BSD-3-Clause
+
+package secec
+
+import (
+	"bytes"
+	stdasn1 "encoding/asn1"
+	"encoding/pem"
+	"errors"
+
+	"golang.org/x/crypto/cryptobyte"
+	"golang.org/x/crypto/cryptobyte/asn1"
+)
+
+const (
+	pemTypeECPrivateKey = "EC PRIVATE KEY"
+
+	ecPrivKeyVersion = 1
+
+	pkcs8VersionV1 = 0
+	pkcs8VersionV2 = 1
+)
+
+var (
+	errInvalidAsn1PrivateKey  = errors.New("secp256k1/secec: invalid ASN.1 EC private key")
+	errInvalidAsn1PubMismatch = errors.New("secp256k1/secec: ASN.1 EC private key public key mismatch")
+	errInvalidPEMPrivateKey   = errors.New("secp256k1/secec: invalid PEM private key")
+	errInvalidPKCS8           = errors.New("secp256k1/secec: invalid PKCS #8 private key")
+
+	tagECPrivKeyParameters = asn1.Tag(0).ContextSpecific().Constructed()
+	tagECPrivKeyPublicKey  = asn1.Tag(1).ContextSpecific().Constructed()
+	tagPKCS8Attributes     = asn1.Tag(0).ContextSpecific().Constructed()
+	tagPKCS8PublicKey      = asn1.Tag(1).ContextSpecific()
+)
+
+// MarshalPEMPublicKey returns the PEM encoded (`PUBLIC KEY`) ASN.1
+// Subject Public Key Info of the public key, as in `ASN1Bytes`.
+func MarshalPEMPublicKey(k *PublicKey) []byte {
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  pemTypePublicKey,
+		Bytes: buildASN1PublicKey(k),
+	})
+}
+
+// ParsePEMPublicKey parses a PEM encoded (`PUBLIC KEY`) ASN.1 Subject
+// Public Key Info, as with `ParseASN1PublicKey`.
+func ParsePEMPublicKey(data []byte) (*PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != pemTypePublicKey {
+		return nil, errInvalidPEM
+	}
+
+	return ParseASN1PublicKey(block.Bytes)
+}
+
+// ASN1Bytes returns the ASN.1 encoded private key, as specified in
+// SEC 1, Version 2.0, Appendix C.4 (`ECPrivateKey`), including the
+// optional curve parameters and public key.  It panics if `k` has been
+// zeroized.
+func (k *PrivateKey) ASN1Bytes() []byte {
+	return buildASN1PrivateKey(k, true)
+}
+
+// ParseASN1PrivateKey parses an ASN.1 encoded private key as specified
+// in SEC 1, Version 2.0, Appendix C.4 (`ECPrivateKey`).  If present, the
+// optional curve parameters MUST be the secp256k1 named curve, and the
+// optional public key MUST match the private key.
+//
+// WARNING: As with `ParseASN1PublicKey`, parsing the case where the
+// curve is parameterized as part of the private key is not, and will
+// not be supported.
+func ParseASN1PrivateKey(data []byte) (*PrivateKey, error) {
+	var (
+		inner, privateKey cryptobyte.String
+		params, publicKey cryptobyte.String
+		hasParams, hasPub bool
+
+		version  int64
+		oidCurve stdasn1.ObjectIdentifier
+	)
+
+	input := cryptobyte.String(data)
+	if !input.ReadASN1(&inner, asn1.SEQUENCE) ||
+		!input.Empty() ||
+		!inner.ReadASN1Integer(&version) ||
+		version != ecPrivKeyVersion ||
+		!inner.ReadASN1(&privateKey, asn1.OCTET_STRING) ||
+		!inner.ReadOptionalASN1(&params, &hasParams, tagECPrivKeyParameters) ||
+		!inner.ReadOptionalASN1(&publicKey, &hasPub, tagECPrivKeyPublicKey) ||
+		!inner.Empty() {
+		return nil, errInvalidAsn1PrivateKey
+	}
+
+	if hasParams {
+		if !params.ReadASN1ObjectIdentifier(&oidCurve) || !params.Empty() {
+			return nil, errInvalidAsn1PrivateKey
+		}
+		if !oidCurve.Equal(oidSecp256k1) {
+			return nil, errInvalidAsn1Curve
+		}
+	}
+
+	// Some implementations strip leading zeros from the private key,
+	// despite SEC 1 requiring `ceiling(log2(n)/8)` octets.
+	if len(privateKey) > PrivateKeySize {
+		return nil, errInvalidAsn1PrivateKey
+	}
+	var keyBytes [PrivateKeySize]byte
+	copy(keyBytes[PrivateKeySize-len(privateKey):], privateKey)
+
+	k, err := NewPrivateKey(keyBytes[:])
+	if err != nil {
+		return nil, err
+	}
+
+	if hasPub {
+		var encodedPoint stdasn1.BitString
+		if !publicKey.ReadASN1BitString(&encodedPoint) || !publicKey.Empty() {
+			return nil, errInvalidAsn1PrivateKey
+		}
+
+		pub, err := NewPublicKey(encodedPoint.RightAlign())
+		if err != nil || !bytes.Equal(pub.pointBytes, k.publicKey.pointBytes) {
+			return nil, errInvalidAsn1PubMismatch
+		}
+	}
+
+	return k, nil
+}
+
+// MarshalPEMPrivateKey returns the PEM encoded (`EC PRIVATE KEY`) ASN.1
+// private key, as in `PrivateKey.ASN1Bytes`.  This is the format used
+// by `openssl ec`.  It panics if `k` has been zeroized.
+func MarshalPEMPrivateKey(k *PrivateKey) []byte {
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  pemTypeECPrivateKey,
+		Bytes: k.ASN1Bytes(),
+	})
+}
+
+// ParsePEMPrivateKey parses a PEM encoded (`EC PRIVATE KEY`) ASN.1
+// private key, as with `ParseASN1PrivateKey`.
+func ParsePEMPrivateKey(data []byte) (*PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != pemTypeECPrivateKey {
+		return nil, errInvalidPEMPrivateKey
+	}
+
+	return ParseASN1PrivateKey(block.Bytes)
+}
+
+// MarshalPKCS8PrivateKey returns the PKCS #8 (RFC 5208) `PrivateKeyInfo`
+// ASN.1 encoding of the private key, with the inner SEC 1 `ECPrivateKey`
+// omitting the (redundant) curve parameters, matching the output of
+// `openssl pkcs8 -topk8`.
+func MarshalPKCS8PrivateKey(k *PrivateKey) ([]byte, error) {
+	if k.isZeroized() {
+		return nil, errZeroizedKey
+	}
+
+	var b cryptobyte.Builder
+	b.AddASN1(asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddASN1Int64(pkcs8VersionV1)
+		b.AddASN1(asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+			b.AddASN1ObjectIdentifier(oidEcPublicKey)
+			b.AddASN1ObjectIdentifier(oidSecp256k1)
+		})
+		b.AddASN1OctetString(buildASN1PrivateKey(k, false))
+	})
+
+	return b.Bytes()
+}
+
+// ParsePKCS8PrivateKey parses a PKCS #8 (RFC 5208) `PrivateKeyInfo`
+// or RFC 5958 `OneAsymmetricKey` ASN.1 encoded private key.  The inner
+// SEC 1 `ECPrivateKey` is parsed as with `ParseASN1PrivateKey`, and
+// may or may not include the curve parameters and public key.
+// Attributes are ignored.
+func ParsePKCS8PrivateKey(der []byte) (*PrivateKey, error) {
+	var (
+		inner, algorithm, privateKey cryptobyte.String
+
+		version                int64
+		oidAlgorithm, oidCurve stdasn1.ObjectIdentifier
+	)
+
+	input := cryptobyte.String(der)
+	if !input.ReadASN1(&inner, asn1.SEQUENCE) ||
+		!input.Empty() ||
+		!inner.ReadASN1Integer(&version) ||
+		(version != pkcs8VersionV1 && version != pkcs8VersionV2) ||
+		!inner.ReadASN1(&algorithm, asn1.SEQUENCE) ||
+		!inner.ReadASN1(&privateKey, asn1.OCTET_STRING) ||
+		!inner.SkipOptionalASN1(tagPKCS8Attributes) ||
+		!inner.SkipOptionalASN1(tagPKCS8PublicKey) ||
+		!inner.Empty() ||
+		!algorithm.ReadASN1ObjectIdentifier(&oidAlgorithm) ||
+		!algorithm.ReadASN1ObjectIdentifier(&oidCurve) ||
+		!algorithm.Empty() {
+		return nil, errInvalidPKCS8
+	}
+
+	if !oidAlgorithm.Equal(oidEcPublicKey) {
+		return nil, errInvalidAsn1Algo
+	}
+	if !oidCurve.Equal(oidSecp256k1) {
+		return nil, errInvalidAsn1Curve
+	}
+
+	return ParseASN1PrivateKey(privateKey)
+}
+
+func buildASN1PrivateKey(k *PrivateKey, withParameters bool) []byte {
+	var b cryptobyte.Builder
+	b.AddASN1(asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddASN1Int64(ecPrivKeyVersion)
+		b.AddASN1OctetString(k.mustScalar().Bytes())
+		if withParameters {
+			b.AddASN1(tagECPrivKeyParameters, func(b *cryptobyte.Builder) {
+				b.AddASN1ObjectIdentifier(oidSecp256k1)
+			})
+		}
+		b.AddASN1(tagECPrivKeyPublicKey, func(b *cryptobyte.Builder) {
+			b.AddASN1BitString(k.publicKey.Bytes()) // Uncompressed SEC1 format.
+		})
+	})
+
+	return b.BytesOrPanic()
+}
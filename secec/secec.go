@@ -11,12 +11,20 @@ import (
 	"bytes"
 	"crypto"
 	"crypto/rand"
+	"crypto/sha256"
 	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
 	"errors"
 	"fmt"
+	"hash"
+	"io"
+	"runtime"
+	"sort"
 
 	"gitlab.com/yawning/secp256k1-voi"
 	"gitlab.com/yawning/secp256k1-voi/internal/disalloweq"
+	"golang.org/x/crypto/hkdf"
 )
 
 // PrivateKeySize is the size of a secp256k1 private key in bytes.
@@ -25,7 +33,9 @@ const PrivateKeySize = 32
 var (
 	errAIsInfinity       = errors.New("secp256k1/secec: public key is the point at infinity")
 	errAIsUninitialized  = errors.New("secp256k1/secec: uninitialized public key")
+	errInvalidKDFLength  = errors.New("secp256k1/secec: invalid KDF output length")
 	errInvalidPrivateKey = errors.New("secp256k1/secec: invalid private key")
+	errZeroizedKey       = errors.New("secp256k1/secec: private key has been zeroized")
 )
 
 // PrivateKey is a secp256k1 private key.
@@ -36,25 +46,143 @@ type PrivateKey struct {
 	publicKey *PublicKey
 }
 
-// Bytes returns a copy of the encoding of the private key.
+// Bytes returns a copy of the encoding of the private key.  It panics
+// if `k` has been zeroized.
 func (k *PrivateKey) Bytes() []byte {
-	return k.scalar.Bytes()
+	return k.mustScalar().Bytes()
 }
 
-// Scalar returns a copy of the scalar underlying `k`.
+// Scalar returns a copy of the scalar underlying `k`.  It panics if
+// `k` has been zeroized.
 func (k *PrivateKey) Scalar() *secp256k1.Scalar {
-	return secp256k1.NewScalarFrom(k.scalar)
+	return secp256k1.NewScalarFrom(k.mustScalar())
+}
+
+// Zeroize overwrites the secret scalar underlying `k` with zero.  After
+// Zeroize has been called, `k` is unusable, all operations that use
+// the secret scalar (eg: signing and ECDH) will return an error, and
+// all operations that serialize the secret scalar (eg: `Bytes`) will
+// return an error or panic.
+//
+// Note: This can not erase copies of the private key (eg: returned by
+// `Bytes` or `Scalar`), state derived from the private key (eg: by
+// `NewSigner`, see `Signer.Zeroize`), or any copies made by the Go
+// runtime (eg: when a goroutine stack grows).
+func (k *PrivateKey) Zeroize() {
+	k.scalar.Zero()
+	runtime.KeepAlive(k.scalar)
+}
+
+func (k *PrivateKey) isZeroized() bool {
+	// INVARIANT: The scalar is only ever 0 after Zeroize.
+	return k.scalar.IsZero() != 0
+}
+
+func (k *PrivateKey) mustScalar() *secp256k1.Scalar {
+	if k.isZeroized() {
+		panic(errZeroizedKey)
+	}
+	return k.scalar
 }
 
 // ECDH performs a ECDH exchange and returns the shared secret as
 // specified in SEC 1, Version 2.0, Section 3.3.1, and returns the
 // x-coordinate encoded according to SEC 1, Version 2.0, Section 2.3.5.
 // The result is never the point at infinity.
+//
+// WARNING: The raw x-coordinate is not uniformly distributed, and MUST
+// NOT be used directly as a key.  Use `ECDHKDF`, or pass it through a
+// suitable KDF.
 func (k *PrivateKey) ECDH(remote *PublicKey) ([]byte, error) {
+	if k.isZeroized() {
+		return nil, errZeroizedKey
+	}
+
 	pt := secp256k1.NewIdentityPoint().ScalarMult(k.scalar, remote.point)
 	return pt.XBytes()
 }
 
+// ECDHKDF performs a ECDH exchange as with `ECDH`, and derives a
+// `length`-byte key from the shared x-coordinate with HKDF-SHA256
+// (RFC 5869), with an empty salt, and the context `info`.  `length`
+// MUST be in the range `[1,255*32]`.
+func (k *PrivateKey) ECDHKDF(remote *PublicKey, info []byte, length int) ([]byte, error) {
+	if length <= 0 || length > 255*sha256.Size {
+		return nil, errInvalidKDFLength
+	}
+
+	sharedX, err := k.ECDH(remote)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		for i := range sharedX {
+			sharedX[i] = 0
+		}
+	}()
+
+	out := make([]byte, length)
+	if _, err = io.ReadFull(hkdf.New(sha256.New, sharedX, nil, info), out); err != nil {
+		// This should NEVER happen, the length is checked above.
+		panic("secp256k1/secec: failed to read HKDF output: " + err.Error())
+	}
+
+	return out, nil
+}
+
+// ECDHVartime performs a ECDH-like exchange with the public scalar
+// `scalar`, and returns the x-coordinate of `scalar * remote` encoded
+// according to SEC 1, Version 2.0, Section 2.3.5.  It returns an error
+// iff the result is the point at infinity (ie: `scalar` is zero).
+//
+// WARNING: This is variable-time with respect to `scalar`, and MUST
+// NOT be used with secret scalars.  Use `PrivateKey.ECDH` instead.
+func ECDHVartime(scalar *secp256k1.Scalar, remote *PublicKey) ([]byte, error) {
+	pt := secp256k1.NewIdentityPoint().ScalarMultVartime(scalar, remote.point)
+	return pt.XBytes()
+}
+
+// CombineSharedSecrets deterministically combines multiple shared secrets
+// (eg: the output of ECDH) into a single value, by hashing the length
+// prefixed shared secrets in lexicographic order with `h`.  The result
+// is independent of the order of `secrets`.
+func CombineSharedSecrets(secrets [][]byte, h func() hash.Hash) []byte {
+	sorted := make([][]byte, 0, len(secrets))
+	sorted = append(sorted, secrets...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i], sorted[j]) < 0
+	})
+
+	hh := h()
+	for _, secret := range sorted {
+		var l [4]byte
+		binary.BigEndian.PutUint32(l[:], uint32(len(secret)))
+		_, _ = hh.Write(l[:])
+		_, _ = hh.Write(secret)
+	}
+
+	return hh.Sum(nil)
+}
+
+// ChannelID returns an identifier for the pair of public keys `a` and
+// `b`, that is independent of the order of the keys.  It is
+// `SHA-256(min(A, B) || max(A, B))`, where `A` and `B` are the SEC 1
+// compressed encodings of `a` and `b`, ordered lexicographically.
+func ChannelID(a, b *PublicKey) [32]byte {
+	aBytes, bBytes := a.CompressedBytes(), b.CompressedBytes()
+	if bytes.Compare(aBytes, bBytes) > 0 {
+		aBytes, bBytes = bBytes, aBytes
+	}
+
+	h := sha256.New()
+	_, _ = h.Write(aBytes)
+	_, _ = h.Write(bBytes)
+
+	var id [32]byte
+	h.Sum(id[:0])
+	return id
+}
+
 // Equal returns whether `x` represents the same private key as `k`.
 // This check is performed in constant time as long as the key types
 // match.
@@ -67,6 +195,15 @@ func (k *PrivateKey) Equal(x crypto.PrivateKey) bool {
 	return other.scalar.Equal(k.scalar) == 1
 }
 
+// PublicKeysEqual returns whether `other` has the same public key as
+// `k`.  This check is performed in constant time.
+//
+// Note: For valid private keys, this is equivalent to `k.Equal(other)`,
+// and is intended as a cross-check when importing keys.
+func (k *PrivateKey) PublicKeysEqual(other *PrivateKey) bool {
+	return k.publicKey.Equal(other.publicKey)
+}
+
 func (k *PrivateKey) Public() crypto.PublicKey {
 	return k.publicKey
 }
@@ -94,7 +231,10 @@ func (k *PublicKey) Bytes() []byte {
 }
 
 // CompressedBytes returns a copy of the compressed encoding of the public
-// key.
+// key.  This is derived from the cached uncompressed encoding, and is
+// considerably cheaper than `k.Point().CompressedBytes()`.
+//
+// Note: `NewPublicKey` accepts the compressed encoding.
 func (k *PublicKey) CompressedBytes() []byte {
 	xBytes, yIsOdd := secp256k1.SplitUncompressedPoint(k.pointBytes)
 	buf := make([]byte, 0, secp256k1.CompressedPointSize)
@@ -110,6 +250,24 @@ func (k *PublicKey) ASN1Bytes() []byte {
 	return buildASN1PublicKey(k)
 }
 
+// Fingerprint returns the digest of the compressed encoding of the
+// public key, using the hash function `h`.
+//
+// Note: The fingerprint is always over the compressed encoding (and not
+// the uncompressed or ASN.1 encodings), and this will panic if `h` is
+// not available.
+func (k *PublicKey) Fingerprint(h crypto.Hash) []byte {
+	hh := h.New()
+	_, _ = hh.Write(k.CompressedBytes())
+	return hh.Sum(nil)
+}
+
+// FingerprintString returns the base64 encoded SHA-256 fingerprint of
+// the public key, as returned by `Fingerprint(crypto.SHA256)`.
+func (k *PublicKey) FingerprintString() string {
+	return base64.StdEncoding.EncodeToString(k.Fingerprint(crypto.SHA256))
+}
+
 // Point returns a copy of the point underlying `k`.
 func (k *PublicKey) Point() *secp256k1.Point {
 	return secp256k1.NewPointFrom(k.point)
@@ -131,7 +289,13 @@ func (k *PublicKey) Equal(x crypto.PublicKey) bool {
 // GenerateKey generates a new PrivateKey, using [crypto/rand.Reader]
 // as the entropy source.
 func GenerateKey() (*PrivateKey, error) {
-	s, err := sampleRandomScalar(rand.Reader)
+	return GenerateKeyFromReader(rand.Reader)
+}
+
+// GenerateKeyFromReader generates a new PrivateKey, using `rand`
+// as the entropy source.
+func GenerateKeyFromReader(rand io.Reader) (*PrivateKey, error) {
+	s, err := sampleRandomScalar(rand)
 	if err != nil {
 		return nil, err
 	}
@@ -198,6 +362,50 @@ func NewPublicKey(key []byte) (*PublicKey, error) {
 	return newPublicKeyFromPoint(pt)
 }
 
+// IsCanonicalCompressedPublicKey returns true iff `b` is a canonical
+// SEC 1, Version 2.0, Section 2.3.3 compressed encoding of a public key.
+// That is, `b` is 33-bytes long, the prefix is `0x02` or `0x03`, the
+// x-coordinate is fully reduced (`< p`), and `x^3 + 7` has a square
+// root.
+//
+// Note: Unlike NewPublicKey, this does not accept the uncompressed
+// encoding or the encoding of the point at infinity.
+func IsCanonicalCompressedPublicKey(b []byte) bool {
+	// SetCompressedBytes does all of the required checks.
+	_, err := secp256k1.NewIdentityPoint().SetCompressedBytes(b)
+	return err == nil
+}
+
+// NewPublicKeys checks that each of `keys` is valid, and returns the
+// corresponding PublicKeys.  If any of the keys are invalid, the
+// returned error will include the index of the first invalid key.
+//
+// Note: Each key is currently decoded independently, so this is
+// provided for convenience rather than performance.
+func NewPublicKeys(keys [][]byte) ([]*PublicKey, error) {
+	pks := make([]*PublicKey, 0, len(keys))
+	for i, key := range keys {
+		pk, err := NewPublicKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("secp256k1/secec: invalid public key at index %d: %w", i, err)
+		}
+		pks = append(pks, pk)
+	}
+
+	return pks, nil
+}
+
+// CommitScalar returns `s * G` as a PublicKey, for use as a commitment
+// to the secret scalar `s`.  `s` MUST be non-zero.
+func CommitScalar(s *secp256k1.Scalar) (*PublicKey, error) {
+	if s.IsZero() != 0 {
+		return nil, errInvalidScalar
+	}
+
+	pt := secp256k1.NewIdentityPoint().ScalarBaseMult(s)
+	return newPublicKeyFromPoint(pt)
+}
+
 // NewPublicKeyFromPoint checks that `point` is valid, and returns a PublicKey.
 func NewPublicKeyFromPoint(point *secp256k1.Point) (*PublicKey, error) {
 	return newPublicKeyFromPoint(secp256k1.NewPointFrom(point))
@@ -11,9 +11,21 @@ import (
 	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/sha256"
+	"crypto/sha512"
+	stdasn1 "encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/cryptobyte"
+	"golang.org/x/crypto/cryptobyte/asn1"
+	"golang.org/x/crypto/sha3"
 
 	"gitlab.com/yawning/secp256k1-voi"
 	"gitlab.com/yawning/secp256k1-voi/internal/helpers"
@@ -57,6 +69,161 @@ func TestSecec(t *testing.T) {
 		require.NoError(t, err, "ECDH - Bob")
 
 		require.EqualValues(t, aliceX, bobX, "shared secrets should match")
+
+		vartimeX, err := ECDHVartime(alicePriv.Scalar(), bobPub)
+		require.NoError(t, err, "ECDHVartime")
+		require.EqualValues(t, aliceX, vartimeX, "ECDHVartime should match ECDH")
+
+		vartimeX, err = ECDHVartime(secp256k1.NewScalar(), bobPub)
+		require.Error(t, err, "ECDHVartime - zero scalar")
+		require.Nil(t, vartimeX, "ECDHVartime - zero scalar")
+	})
+	t.Run("ECDH/ECDHCurve", func(t *testing.T) {
+		curve := NewECDHCurve()
+		require.Equal(t, "secp256k1", curve.String(), "String")
+
+		alicePriv, err := curve.GenerateKey(rand.Reader)
+		require.NoError(t, err, "GenerateKey - Alice")
+		bobPriv, err := curve.NewPrivateKey(alicePriv.Bytes())
+		require.NoError(t, err, "NewPrivateKey")
+		require.True(t, alicePriv.Equal(bobPriv), "NewPrivateKey - round trip")
+
+		bobPriv, err = curve.GenerateKey(rand.Reader)
+		require.NoError(t, err, "GenerateKey - Bob")
+
+		bobPub, err := curve.NewPublicKey(bobPriv.PublicKey().Bytes())
+		require.NoError(t, err, "NewPublicKey")
+
+		aliceX, err := curve.ECDH(alicePriv, bobPub)
+		require.NoError(t, err, "ECDH - Alice")
+		bobX, err := bobPriv.ECDH(alicePriv.PublicKey())
+		require.NoError(t, err, "ECDH - Bob")
+		require.Equal(t, aliceX, bobX, "shared secrets should match")
+
+		_, err = curve.NewPublicKey(bobPriv.PublicKey().CompressedBytes())
+		require.ErrorIs(t, err, errInvalidECDHPublicKey, "NewPublicKey - compressed")
+		_, err = curve.NewPublicKey([]byte{0x00})
+		require.ErrorIs(t, err, errInvalidECDHPublicKey, "NewPublicKey - identity")
+		_, err = curve.NewPrivateKey(make([]byte, PrivateKeySize))
+		require.ErrorIs(t, err, errInvalidPrivateKey, "NewPrivateKey - zero")
+	})
+	t.Run("ECDH/KDF", func(t *testing.T) {
+		alicePriv, err := GenerateKey()
+		require.NoError(t, err, "GenerateKey - Alice")
+		bobPriv, err := GenerateKey()
+		require.NoError(t, err, "GenerateKey - Bob")
+
+		info := []byte("secp256k1-voi/ECDHKDF/test")
+		aliceKey, err := alicePriv.ECDHKDF(bobPriv.PublicKey(), info, 48)
+		require.NoError(t, err, "ECDHKDF - Alice")
+		require.Len(t, aliceKey, 48, "ECDHKDF - length")
+
+		bobKey, err := bobPriv.ECDHKDF(alicePriv.PublicKey(), info, 48)
+		require.NoError(t, err, "ECDHKDF - Bob")
+		require.Equal(t, aliceKey, bobKey, "derived keys should match")
+
+		sharedX, err := alicePriv.ECDH(bobPriv.PublicKey())
+		require.NoError(t, err, "ECDH")
+		require.NotEqual(t, sharedX, aliceKey[:len(sharedX)], "derived key should not be the raw x-coordinate")
+
+		otherKey, err := alicePriv.ECDHKDF(bobPriv.PublicKey(), []byte("other info"), 48)
+		require.NoError(t, err, "ECDHKDF - other info")
+		require.NotEqual(t, aliceKey, otherKey, "info should separate keys")
+
+		for _, l := range []int{0, -1, 255*32 + 1} {
+			_, err = alicePriv.ECDHKDF(bobPriv.PublicKey(), info, l)
+			require.ErrorIs(t, err, errInvalidKDFLength, "ECDHKDF - length %d", l)
+		}
+	})
+	t.Run("PrivateKey/Zeroize", func(t *testing.T) {
+		priv, err := GenerateKey()
+		require.NoError(t, err, "GenerateKey")
+		otherPriv, err := GenerateKey()
+		require.NoError(t, err, "GenerateKey")
+
+		sr := priv.NewSigner("test-zeroize")
+
+		priv.Zeroize()
+		require.EqualValues(t, 1, priv.scalar.IsZero(), "Zeroize - scalar")
+
+		_, err = priv.Sign(rand.Reader, testMessageHash, nil)
+		require.ErrorIs(t, err, errZeroizedKey, "Sign")
+		_, _, _, err = priv.SignRFC6979(testMessageHash)
+		require.ErrorIs(t, err, errZeroizedKey, "SignRFC6979")
+		_, err = priv.ECDH(otherPriv.PublicKey())
+		require.ErrorIs(t, err, errZeroizedKey, "ECDH")
+		_, err = priv.ECDHKDF(otherPriv.PublicKey(), nil, 32)
+		require.ErrorIs(t, err, errZeroizedKey, "ECDHKDF")
+		_, err = sr.SignASN1(rand.Reader, testMessageHash)
+		require.ErrorIs(t, err, errZeroizedKey, "Signer.SignASN1 - key zeroized")
+
+		// The secret scalar MUST NOT be serialized.
+		require.PanicsWithError(t, errZeroizedKey.Error(), func() { priv.Bytes() }, "Bytes")
+		require.PanicsWithError(t, errZeroizedKey.Error(), func() { priv.Scalar() }, "Scalar")
+		require.PanicsWithError(t, errZeroizedKey.Error(), func() { priv.ASN1Bytes() }, "ASN1Bytes")
+		require.PanicsWithError(t, errZeroizedKey.Error(), func() { priv.MarshalVersioned() }, "MarshalVersioned")
+		require.PanicsWithError(t, errZeroizedKey.Error(), func() { MarshalPEMPrivateKey(priv) }, "MarshalPEMPrivateKey")
+		b, err := MarshalPKCS8PrivateKey(priv)
+		require.Nil(t, b, "MarshalPKCS8PrivateKey")
+		require.ErrorIs(t, err, errZeroizedKey, "MarshalPKCS8PrivateKey")
+		b, err = MarshalJWKPrivateKey(priv, JWKCurveSecp256k1)
+		require.Nil(t, b, "MarshalJWKPrivateKey")
+		require.ErrorIs(t, err, errZeroizedKey, "MarshalJWKPrivateKey")
+
+		// Signers hold state derived from the key, and are zeroized
+		// separately.
+		sr = otherPriv.NewSigner("test-zeroize")
+		_, err = sr.SignASN1(rand.Reader, testMessageHash)
+		require.NoError(t, err, "Signer.SignASN1")
+		sr.Zeroize()
+		require.Nil(t, sr.nonceXOF, "Signer.Zeroize - nonceXOF")
+		_, err = sr.SignASN1(rand.Reader, testMessageHash)
+		require.ErrorIs(t, err, errZeroizedKey, "Signer.SignASN1 - signer zeroized")
+		_, err = otherPriv.Sign(rand.Reader, testMessageHash, nil)
+		require.NoError(t, err, "Sign - signer zeroized")
+	})
+	t.Run("ChannelID", func(t *testing.T) {
+		priv1, err := NewPrivateKeyFromScalar(secp256k1.NewScalarFromUint64(1))
+		require.NoError(t, err, "NewPrivateKeyFromScalar(1)")
+		priv2, err := NewPrivateKeyFromScalar(secp256k1.NewScalarFromUint64(2))
+		require.NoError(t, err, "NewPrivateKeyFromScalar(2)")
+		pub1, pub2 := priv1.PublicKey(), priv2.PublicKey()
+
+		id := ChannelID(pub1, pub2)
+		require.Equal(t, id, ChannelID(pub2, pub1), "ChannelID - order independent")
+
+		// G (0x0279...) sorts before 2G (0x02c6...).
+		expected := sha256.Sum256(append(pub1.CompressedBytes(), pub2.CompressedBytes()...))
+		require.Equal(t, expected, id, "ChannelID - construction")
+
+		require.NotEqual(t, id, ChannelID(pub1, pub1), "ChannelID - distinct pairs")
+	})
+	t.Run("ECDH/CombineSharedSecrets", func(t *testing.T) {
+		var secrets [][]byte
+		for i := 0; i < 3; i++ {
+			alicePriv, err := GenerateKey()
+			require.NoError(t, err, "GenerateKey - Alice")
+			bobPriv, err := GenerateKey()
+			require.NoError(t, err, "GenerateKey - Bob")
+
+			secret, err := alicePriv.ECDH(bobPriv.PublicKey())
+			require.NoError(t, err, "ECDH")
+			secrets = append(secrets, secret)
+		}
+
+		combined := CombineSharedSecrets(secrets, sha256.New)
+		require.Len(t, combined, sha256.Size, "CombineSharedSecrets")
+
+		reversed := [][]byte{secrets[2], secrets[1], secrets[0]}
+		require.EqualValues(t, combined, CombineSharedSecrets(reversed, sha256.New), "CombineSharedSecrets - order")
+		require.EqualValues(t, secrets[0], reversed[2], "CombineSharedSecrets - input unmodified")
+
+		require.NotEqualValues(t, combined, CombineSharedSecrets(secrets[:2], sha256.New), "CombineSharedSecrets - subset")
+
+		// Length prefixing prevents trivial concatenation ambiguity.
+		a := CombineSharedSecrets([][]byte{[]byte("ab"), []byte("c")}, sha256.New)
+		b := CombineSharedSecrets([][]byte{[]byte("a"), []byte("bc")}, sha256.New)
+		require.NotEqualValues(t, a, b, "CombineSharedSecrets - ambiguity")
 	})
 	t.Run("ECDSA", func(t *testing.T) {
 		priv, err := GenerateKey()
@@ -92,6 +259,13 @@ func TestSecec(t *testing.T) {
 		ok = pub.VerifyRaw(testMessageHash, r, s)
 		require.True(t, ok, "VerifyRaw")
 
+		e, err := hashToScalar(testMessageHash)
+		require.NoError(t, err, "hashToScalar")
+		ok = pub.VerifyPrecomputedE(e, r, s)
+		require.True(t, ok, "VerifyPrecomputedE")
+		ok = pub.VerifyPrecomputedE(s, r, s)
+		require.False(t, ok, "VerifyPrecomputedE - Bad e")
+
 		opts := &ECDSAOptions{
 			Hash:       crypto.SHA256,
 			Encoding:   EncodingCompact,
@@ -121,6 +295,10 @@ func TestSecec(t *testing.T) {
 		require.ErrorIs(t, err, errInvalidRorS, "verify - Zero r")
 		err = verify(nil, pub, testMessageHash, r, &zero)
 		require.ErrorIs(t, err, errInvalidRorS, "verify - Zero s")
+		ok = pub.VerifyPrecomputedE(e, &zero, s)
+		require.False(t, ok, "VerifyPrecomputedE - Zero r")
+		ok = pub.VerifyPrecomputedE(e, r, &zero)
+		require.False(t, ok, "VerifyPrecomputedE - Zero s")
 
 		badSig, err := priv.Sign(rand.Reader, testMessageHash[:30], nil)
 		require.Nil(t, badSig, "Sign - Truncated hash")
@@ -134,7 +312,7 @@ func TestSecec(t *testing.T) {
 		require.Nil(t, badSig, "Sign - Truncated hash")
 		require.ErrorIs(t, err, errInvalidDigest, "Sign - Truncated hash, opts")
 
-		opts.Encoding = EncodingCompactRecoverable + 1
+		opts.Encoding = EncodingEIP2098 + 1
 		badSig, err = priv.Sign(rand.Reader, testMessageHash, opts)
 		require.Nil(t, badSig, "Sign - Bad encoding")
 		require.ErrorIs(t, err, errInvalidEncoding, "Sign - Bad encoding")
@@ -158,6 +336,421 @@ func TestSecec(t *testing.T) {
 		pubUntyped := priv.Public()
 		require.True(t, pub.Equal(pubUntyped), "pub.Equal(pubUntyped)")
 	})
+	t.Run("ECDSA/crypto.Signer", func(t *testing.T) {
+		priv, err := GenerateKey()
+		require.NoError(t, err, "GenerateKey")
+		pub := priv.PublicKey()
+
+		var signer crypto.Signer = priv
+		require.True(t, pub.Equal(signer.Public()), "Public")
+
+		// nil rand falls back to crypto/rand.
+		sig, err := signer.Sign(nil, testMessageHash, crypto.SHA256)
+		require.NoError(t, err, "Sign - nil rand")
+		require.True(t, pub.Verify(testMessageHash, sig, nil), "Verify - nil rand")
+		_, _, err = ParseASN1Signature(sig)
+		require.NoError(t, err, "ParseASN1Signature")
+
+		// Digest length is validated against opts.HashFunc().
+		digest512 := sha512.Sum512([]byte("crypto.Signer"))
+		sig, err = signer.Sign(rand.Reader, digest512[:], crypto.SHA512)
+		require.NoError(t, err, "Sign - SHA512")
+		require.True(t, pub.Verify(digest512[:], sig, &ECDSAOptions{Hash: crypto.SHA512}), "Verify - SHA512")
+
+		_, err = signer.Sign(rand.Reader, digest512[:], crypto.SHA256)
+		require.ErrorIs(t, err, errInvalidDigest, "Sign - SHA256 opts, SHA512 digest")
+
+		// crypto.Hash(0) skips the length check (and does not panic).
+		sig, err = signer.Sign(rand.Reader, testMessageHash, crypto.Hash(0))
+		require.NoError(t, err, "Sign - crypto.Hash(0)")
+		require.True(t, pub.Verify(testMessageHash, sig, nil), "Verify - crypto.Hash(0)")
+
+		// Unknown hash functions are rejected (and do not panic).
+		for _, hashFn := range []crypto.Hash{crypto.Hash(99), crypto.Hash(math.MaxUint)} {
+			sig, err = signer.Sign(rand.Reader, testMessageHash, hashFn)
+			require.Nil(t, sig, "Sign - crypto.Hash(%d)", uint(hashFn))
+			require.ErrorIs(t, err, errInvalidDigest, "Sign - crypto.Hash(%d)", uint(hashFn))
+
+			opts := &ECDSAOptions{Hash: hashFn}
+			sig, err = priv.Sign(rand.Reader, testMessageHash, opts)
+			require.Nil(t, sig, "Sign - ECDSAOptions{crypto.Hash(%d)}", uint(hashFn))
+			require.ErrorIs(t, err, errInvalidDigest, "Sign - ECDSAOptions{crypto.Hash(%d)}", uint(hashFn))
+
+			validSig, err := priv.Sign(rand.Reader, testMessageHash, nil)
+			require.NoError(t, err, "Sign")
+			require.False(t, pub.Verify(testMessageHash, validSig, opts), "Verify - crypto.Hash(%d)", uint(hashFn))
+			require.False(t, NewVerifierWithOptions(opts).VerifyASN1(pub, testMessageHash, validSig), "Verifier - crypto.Hash(%d)", uint(hashFn))
+		}
+	})
+	t.Run("ECDSA/SignASN1WithConfig", func(t *testing.T) {
+		priv, err := GenerateKey()
+		require.NoError(t, err, "GenerateKey")
+		pub := priv.PublicKey()
+
+		sig, err := priv.SignASN1WithConfig(nil, testMessageHash)
+		require.NoError(t, err, "SignASN1WithConfig - nil")
+		require.True(t, pub.Verify(testMessageHash, sig, nil), "Verify - nil")
+
+		var calls int
+		cfg := &SignerConfig{
+			NonceDerivation: func(k *PrivateKey, ctx string, digest []byte, rand io.Reader) (io.Reader, error) {
+				calls++
+				require.Equal(t, priv, k, "NonceDerivation - priv")
+				require.Equal(t, domainSepECDSA, ctx, "NonceDerivation - ctx")
+				require.Equal(t, testMessageHash, digest, "NonceDerivation - digest")
+				require.Nil(t, rand, "NonceDerivation - rand")
+
+				return sha3.NewShake128(), nil // Fixed (and terrible) nonces.
+			},
+		}
+
+		sig, err = priv.SignASN1WithConfig(cfg, testMessageHash)
+		require.NoError(t, err, "SignASN1WithConfig")
+		require.True(t, pub.Verify(testMessageHash, sig, nil), "Verify")
+		require.Equal(t, 1, calls, "NonceDerivation calls")
+
+		sig2, err := priv.SignASN1WithConfig(cfg, testMessageHash)
+		require.NoError(t, err, "SignASN1WithConfig - again")
+		require.Equal(t, sig, sig2, "SignASN1WithConfig - deterministic")
+
+		errNope := errors.New("nope")
+		cfg.NonceDerivation = func(*PrivateKey, string, []byte, io.Reader) (io.Reader, error) {
+			return nil, errNope
+		}
+		sig, err = priv.SignASN1WithConfig(cfg, testMessageHash)
+		require.Nil(t, sig, "SignASN1WithConfig - NonceDerivation failure")
+		require.ErrorIs(t, err, errNope, "SignASN1WithConfig - NonceDerivation failure")
+	})
+	t.Run("ECDSA/Digest", func(t *testing.T) {
+		priv, err := GenerateKey()
+		require.NoError(t, err, "GenerateKey")
+		pub := priv.PublicKey()
+
+		digestSHA512 := sha512.Sum512([]byte("SignDigest"))
+		digestSHA3 := sha3.Sum512([]byte("SignDigest"))
+		for _, v := range []struct {
+			name   string
+			digest []byte
+		}{
+			{"SHA-256", testMessageHash},
+			{"SHA-512", digestSHA512[:]},
+			{"SHA3-512", digestSHA3[:]},
+		} {
+			r, s, err := priv.SignDigest(rand.Reader, v.digest)
+			require.NoError(t, err, "SignDigest(%s)", v.name)
+			require.True(t, pub.VerifyDigest(v.digest, r, s), "VerifyDigest(%s)", v.name)
+
+			// Digests are truncated to the left-most 256-bits.
+			require.True(t, pub.VerifyDigest(v.digest[:MinDigestSize], r, s), "VerifyDigest(%s) - truncated", v.name)
+
+			tampered := bytes.Clone(v.digest)
+			tampered[0] ^= 0x69
+			require.False(t, pub.VerifyDigest(tampered, r, s), "VerifyDigest(%s) - tampered", v.name)
+
+			// Interoperable with the ASN.1 verification routine.
+			sig := BuildASN1Signature(r, s)
+			require.True(t, pub.Verify(v.digest, sig, nil), "Verify(%s)", v.name)
+		}
+
+		shortDigest := testMessageHash[:MinDigestSize-1]
+		_, _, err = priv.SignDigest(rand.Reader, shortDigest)
+		require.ErrorIs(t, err, errInvalidDigest, "SignDigest - short digest")
+
+		r, s, err := priv.SignDigest(rand.Reader, testMessageHash)
+		require.NoError(t, err, "SignDigest")
+		require.False(t, pub.VerifyDigest(shortDigest, r, s), "VerifyDigest - short digest")
+	})
+	t.Run("ECDSA/RejectZeroDigest", func(t *testing.T) {
+		priv, err := GenerateKey()
+		require.NoError(t, err, "GenerateKey")
+		pub := priv.PublicKey()
+
+		zeroDigest := make([]byte, 32)
+
+		// Zero digests are allowed by default.
+		sig, err := priv.Sign(rand.Reader, zeroDigest, nil)
+		require.NoError(t, err, "Sign - default")
+		require.True(t, pub.Verify(zeroDigest, sig, nil), "Verify - default")
+
+		opts := &ECDSAOptions{
+			RejectZeroDigest: true,
+		}
+		ok := pub.Verify(zeroDigest, sig, opts)
+		require.False(t, ok, "Verify - RejectZeroDigest")
+
+		badSig, err := priv.Sign(rand.Reader, zeroDigest, opts)
+		require.Nil(t, badSig, "Sign - RejectZeroDigest")
+		require.ErrorIs(t, err, errZeroDigest, "Sign - RejectZeroDigest")
+
+		badSig, err = priv.SignASN1WithConfig(&SignerConfig{RejectZeroDigest: true}, zeroDigest)
+		require.Nil(t, badSig, "SignASN1WithConfig - RejectZeroDigest")
+		require.ErrorIs(t, err, errZeroDigest, "SignASN1WithConfig - RejectZeroDigest")
+
+		r, s, err := ParseASN1Signature(sig)
+		require.NoError(t, err, "ParseASN1Signature")
+		require.True(t, pub.VerifyRaw(zeroDigest, r, s), "VerifyRaw - default")
+
+		vr := NewVerifier()
+		require.True(t, vr.VerifyASN1(pub, zeroDigest, sig), "Verifier.VerifyASN1 - default")
+		require.True(t, vr.VerifyRaw(pub, zeroDigest, r, s), "Verifier.VerifyRaw - default")
+
+		vr = NewVerifierWithOptions(opts)
+		require.False(t, vr.VerifyASN1(pub, zeroDigest, sig), "Verifier.VerifyASN1 - RejectZeroDigest")
+		require.False(t, vr.VerifyRaw(pub, zeroDigest, r, s), "Verifier.VerifyRaw - RejectZeroDigest")
+
+		// Non-zero digests are unaffected.
+		sig, err = priv.Sign(rand.Reader, testMessageHash, opts)
+		require.NoError(t, err, "Sign - RejectZeroDigest, non-zero")
+		require.True(t, pub.Verify(testMessageHash, sig, opts), "Verify - RejectZeroDigest, non-zero")
+		require.True(t, vr.VerifyASN1(pub, testMessageHash, sig), "Verifier.VerifyASN1 - RejectZeroDigest, non-zero")
+
+		r, s, err = ParseASN1Signature(sig)
+		require.NoError(t, err, "ParseASN1Signature - non-zero")
+		require.True(t, vr.VerifyRaw(pub, testMessageHash, r, s), "Verifier.VerifyRaw - RejectZeroDigest, non-zero")
+	})
+	t.Run("ECDSA/Verifier", func(t *testing.T) {
+		priv, err := GenerateKey()
+		require.NoError(t, err, "GenerateKey")
+		pub := priv.PublicKey()
+
+		otherPriv, err := GenerateKey()
+		require.NoError(t, err, "GenerateKey - other")
+		otherPub := otherPriv.PublicKey()
+
+		vr := NewVerifier()
+		for i := 0; i < 10; i++ {
+			sig, err := priv.Sign(rand.Reader, testMessageHash, nil)
+			require.NoError(t, err, "Sign")
+
+			ok := vr.VerifyASN1(pub, testMessageHash, sig)
+			require.True(t, ok, "[%d]: VerifyASN1", i)
+
+			ok = vr.VerifyASN1(otherPub, testMessageHash, sig)
+			require.False(t, ok, "[%d]: VerifyASN1 - Wrong key", i)
+
+			tmp := bytes.Clone(sig)
+			tmp[len(tmp)-1] ^= 0x69
+			ok = vr.VerifyASN1(pub, testMessageHash, tmp)
+			require.False(t, ok, "[%d]: VerifyASN1 - Corrupted sig", i)
+
+			ok = vr.VerifyASN1(pub, testMessageHash[:5], sig)
+			require.False(t, ok, "[%d]: VerifyASN1 - Truncated h", i)
+
+			r, s, err := ParseASN1Signature(sig)
+			require.NoError(t, err, "[%d]: ParseASN1Signature", i)
+			ok = vr.VerifyRaw(pub, testMessageHash, r, s)
+			require.True(t, ok, "[%d]: VerifyRaw", i)
+
+			ok = vr.VerifyRaw(otherPub, testMessageHash, r, s)
+			require.False(t, ok, "[%d]: VerifyRaw - Wrong key", i)
+
+			require.True(t, pub.VerifyDERFast(testMessageHash, sig), "[%d]: VerifyDERFast", i)
+			require.False(t, otherPub.VerifyDERFast(testMessageHash, sig), "[%d]: VerifyDERFast - Wrong key", i)
+			require.False(t, pub.VerifyDERFast(testMessageHash, tmp), "[%d]: VerifyDERFast - Corrupted sig", i)
+			require.False(t, pub.VerifyDERFast(testMessageHash[:5], sig), "[%d]: VerifyDERFast - Truncated h", i)
+		}
+
+		sig, err := priv.Sign(rand.Reader, testMessageHash, nil)
+		require.NoError(t, err, "Sign")
+		allocs := testing.AllocsPerRun(10, func() {
+			_ = vr.VerifyASN1(pub, testMessageHash, sig)
+		})
+		require.Zero(t, allocs, "VerifyASN1 - allocations")
+		_ = pub.VerifyDERFast(testMessageHash, sig) // Warm the pool.
+		allocs = testing.AllocsPerRun(10, func() {
+			_ = pub.VerifyDERFast(testMessageHash, sig)
+		})
+		require.Zero(t, allocs, "VerifyDERFast - allocations")
+
+		// Options are honored.
+		r, s, err := ParseASN1Signature(sig)
+		require.NoError(t, err, "ParseASN1Signature")
+		sNeg := secp256k1.NewScalar().Negate(s) // s <= n/2, so -s > n/2
+		highSSig := BuildASN1Signature(r, sNeg)
+		require.True(t, vr.VerifyASN1(pub, testMessageHash, highSSig), "VerifyASN1 - high s")
+		require.True(t, vr.VerifyRaw(pub, testMessageHash, r, sNeg), "VerifyRaw - high s")
+		require.True(t, pub.VerifyDERFast(testMessageHash, highSSig), "VerifyDERFast - high s")
+
+		vr = NewVerifierWithOptions(&ECDSAOptions{
+			RejectMalleable: true,
+		})
+		require.True(t, vr.VerifyASN1(pub, testMessageHash, sig), "VerifyASN1 - RejectMalleable, low s")
+		require.False(t, vr.VerifyASN1(pub, testMessageHash, highSSig), "VerifyASN1 - RejectMalleable, high s")
+		require.False(t, vr.VerifyRaw(pub, testMessageHash, r, sNeg), "VerifyRaw - RejectMalleable, high s")
+
+		vr = NewVerifierWithOptions(&ECDSAOptions{
+			Hash: crypto.SHA512,
+		})
+		require.False(t, vr.VerifyASN1(pub, testMessageHash, sig), "VerifyASN1 - Hash size mismatch")
+		require.False(t, vr.VerifyRaw(pub, testMessageHash, r, s), "VerifyRaw - Hash size mismatch")
+	})
+	t.Run("ECDSA/Signer", func(t *testing.T) {
+		priv, err := GenerateKey()
+		require.NoError(t, err, "GenerateKey")
+		pub := priv.PublicKey()
+
+		const ctx = "test-signer"
+		sr := priv.NewSigner(ctx)
+
+		for i := 0; i < 4; i++ {
+			sig, err := sr.SignASN1(rand.Reader, testMessageHash)
+			require.NoError(t, err, "[%d]: SignASN1", i)
+			require.True(t, pub.Verify(testMessageHash, sig, nil), "[%d]: Verify", i)
+
+			// The cached state MUST be equivalent to deriving it
+			// from scratch each call.
+			seed := []byte{byte(i)}
+			sig, err = sr.SignASN1(DeterministicRand(seed), testMessageHash)
+			require.NoError(t, err, "[%d]: SignASN1 - deterministic", i)
+
+			expected, err := priv.SignASN1WithConfig(&SignerConfig{
+				Rand: DeterministicRand(seed),
+				NonceDerivation: func(priv *PrivateKey, _ string, digest []byte, rand io.Reader) (io.Reader, error) {
+					e, err := hashToScalar(digest)
+					if err != nil {
+						return nil, err
+					}
+					return mitigateDebianAndSony(rand, domainSepSigner+ctx, priv, e)
+				},
+			}, testMessageHash)
+			require.NoError(t, err, "[%d]: SignASN1WithConfig", i)
+			require.Equal(t, expected, sig, "[%d]: SignASN1 - matches uncached", i)
+
+			sig2, err := priv.NewSigner("other").SignASN1(DeterministicRand(seed), testMessageHash)
+			require.NoError(t, err, "[%d]: SignASN1 - other ctx", i)
+			require.NotEqual(t, sig, sig2, "[%d]: SignASN1 - domain separated", i)
+		}
+
+		sig, err := sr.SignASN1(RFC6979SHA256(), testMessageHash)
+		require.NoError(t, err, "SignASN1 - RFC6979")
+		expected, err := priv.Sign(RFC6979SHA256(), testMessageHash, nil)
+		require.NoError(t, err, "Sign - RFC6979")
+		require.Equal(t, expected, sig, "SignASN1 - RFC6979")
+
+		_, err = sr.SignASN1(rand.Reader, testMessageHash[:5])
+		require.ErrorIs(t, err, errInvalidDigest, "SignASN1 - truncated digest")
+	})
+	t.Run("ECDSA/BatchVerify", func(t *testing.T) {
+		const n = 8
+
+		var (
+			keys   []*PublicKey
+			hashes [][]byte
+			sigs   [][2]*secp256k1.Scalar
+			vs     []byte
+		)
+		for i := 0; i < n; i++ {
+			priv, err := GenerateKey()
+			require.NoError(t, err, "GenerateKey")
+
+			h := sha256.Sum256([]byte(fmt.Sprintf("BatchVerify %d", i)))
+			r, s, v, err := priv.SignRaw(rand.Reader, h[:])
+			require.NoError(t, err, "SignRaw")
+
+			keys = append(keys, priv.PublicKey())
+			hashes = append(hashes, h[:])
+			sigs = append(sigs, [2]*secp256k1.Scalar{r, s})
+			vs = append(vs, v)
+		}
+
+		requireResults := func(expected []bool, ok bool, results []bool, descr string) {
+			allOk := true
+			for _, v := range expected {
+				allOk = allOk && v
+			}
+			require.Equal(t, allOk, ok, "%s: BatchVerify", descr)
+			require.Equal(t, expected, results, "%s: BatchVerify - results", descr)
+		}
+
+		expected := make([]bool, n)
+		for i := range expected {
+			expected[i] = true
+		}
+		ok, results := BatchVerify(keys, hashes, sigs, vs)
+		requireResults(expected, ok, results, "valid")
+
+		// Wrong recovery IDs do not alter the result.
+		badVs := bytes.Clone(vs)
+		badVs[3] ^= 1
+		ok, results = BatchVerify(keys, hashes, sigs, badVs)
+		requireResults(expected, ok, results, "bad recovery ID")
+
+		// A single invalid signature is identified.
+		badSigs := append([][2]*secp256k1.Scalar{}, sigs...)
+		badSigs[5] = sigs[6]
+		expected[5] = false
+		ok, results = BatchVerify(keys, hashes, badSigs, vs)
+		requireResults(expected, ok, results, "bad signature")
+
+		// Malleated signatures are still valid.
+		badSigs[5] = [2]*secp256k1.Scalar{sigs[5][0], secp256k1.NewScalar().Negate(sigs[5][1])}
+		badVs = bytes.Clone(vs)
+		badVs[5] ^= 1
+		expected[5] = true
+		ok, results = BatchVerify(keys, hashes, badSigs, badVs)
+		requireResults(expected, ok, results, "high s")
+
+		// Without recovery IDs, each signature is checked individually.
+		ok, results = BatchVerify(keys, hashes, badSigs, nil)
+		requireResults(expected, ok, results, "no recovery IDs")
+		badSigs[2] = sigs[3]
+		expected[2] = false
+		ok, results = BatchVerify(keys, hashes, badSigs, nil)
+		requireResults(expected, ok, results, "no recovery IDs, bad signature")
+
+		// Missing scalars are rejected, without panicking.
+		badSigs = append([][2]*secp256k1.Scalar{}, sigs...)
+		badSigs[1] = [2]*secp256k1.Scalar{nil, sigs[1][1]}
+		badSigs[4] = [2]*secp256k1.Scalar{sigs[4][0], nil}
+		for i := range expected {
+			expected[i] = i != 1 && i != 4
+		}
+		ok, results = BatchVerify(keys, hashes, badSigs, vs)
+		requireResults(expected, ok, results, "nil r/s")
+		ok, results = BatchVerify(keys, hashes, badSigs, nil)
+		requireResults(expected, ok, results, "nil r/s, no recovery IDs")
+
+		ok, results = BatchVerify(keys, hashes, sigs, vs[:n-1])
+		require.False(t, ok, "BatchVerify - length mismatch")
+		require.Nil(t, results, "BatchVerify - length mismatch")
+
+		ok, results = BatchVerify(nil, nil, nil, nil)
+		require.True(t, ok, "BatchVerify - empty")
+		require.Empty(t, results, "BatchVerify - empty")
+	})
+	t.Run("ECDSA/AttributeSignature", func(t *testing.T) {
+		var (
+			privs []*PrivateKey
+			pubs  []*PublicKey
+		)
+		for i := 0; i < 4; i++ {
+			priv, err := GenerateKey()
+			require.NoError(t, err, "[%d]: GenerateKey", i)
+			privs = append(privs, priv)
+			pubs = append(pubs, priv.PublicKey())
+		}
+
+		for i, priv := range privs {
+			sig, err := priv.Sign(rand.Reader, testMessageHash, nil)
+			require.NoError(t, err, "[%d]: Sign", i)
+
+			idx, ok := AttributeSignature(pubs, testMessageHash, sig)
+			require.True(t, ok, "[%d]: AttributeSignature", i)
+			require.Equal(t, i, idx, "[%d]: AttributeSignature", i)
+
+			idx, ok = AttributeSignature(pubs[:i], testMessageHash, sig)
+			require.False(t, ok, "[%d]: AttributeSignature - not in set", i)
+			require.Equal(t, -1, idx, "[%d]: AttributeSignature - not in set", i)
+
+			idx, ok = AttributeSignature(pubs, testMessageHash, sig[1:])
+			require.False(t, ok, "[%d]: AttributeSignature - bad sig", i)
+			require.Equal(t, -1, idx, "[%d]: AttributeSignature - bad sig", i)
+
+			idx, ok = AttributeSignature(pubs, testMessageHash[:5], sig)
+			require.False(t, ok, "[%d]: AttributeSignature - truncated digest", i)
+			require.Equal(t, -1, idx, "[%d]: AttributeSignature - truncated digest", i)
+		}
+	})
 	t.Run("ECDSA/Recover", func(t *testing.T) {
 		priv, err := GenerateKey()
 		require.NoError(t, err, "GenerateKey")
@@ -204,6 +797,311 @@ func TestSecec(t *testing.T) {
 		require.Error(t, err, "RecoverPublicKey - Bad recovery ID")
 		_, err = RecoverPublicKey(testMessageHash[:31], r, s, v)
 		require.ErrorIs(t, err, errInvalidDigest, "RecoverPublicKey - Truncated h")
+
+		q, err = RecoverPublicKeyStrictEthereum(testMessageHash, r, s, v)
+		require.NoError(t, err, "RecoverPublicKeyStrictEthereum")
+		require.True(t, pub.Equal(q))
+		for _, badV := range []byte{2, 3, 27, 28} {
+			_, err = RecoverPublicKeyStrictEthereum(testMessageHash, r, s, badV)
+			require.ErrorIs(t, err, errInvalidRecoveryID, "RecoverPublicKeyStrictEthereum - v = %d", badV)
+		}
+	})
+	t.Run("ECDSA/CompactRecoverable", func(t *testing.T) {
+		priv, err := GenerateKey()
+		require.NoError(t, err, "GenerateKey")
+		pub := priv.PublicKey()
+
+		for i := 0; i < 8; i++ {
+			sig, err := priv.SignCompactRecoverable(rand.Reader, testMessageHash)
+			require.NoError(t, err, "[%d]: SignCompactRecoverable", i)
+			require.Len(t, sig, CompactRecoverableSignatureSize, "[%d]: SignCompactRecoverable", i)
+			require.LessOrEqual(t, sig[CompactSignatureSize], byte(3), "[%d]: SignCompactRecoverable - v", i)
+
+			q, err := RecoverCompact(testMessageHash, sig)
+			require.NoError(t, err, "[%d]: RecoverCompact", i)
+			require.True(t, pub.Equal(q), "[%d]: RecoverCompact", i)
+
+			ok, err := pub.VerifyRecoverable(testMessageHash, sig)
+			require.NoError(t, err, "[%d]: VerifyRecoverable", i)
+			require.True(t, ok, "[%d]: VerifyRecoverable", i)
+		}
+
+		_, err = priv.SignCompactRecoverable(rand.Reader, testMessageHash[:5])
+		require.ErrorIs(t, err, errInvalidDigest, "SignCompactRecoverable - truncated digest")
+
+		sig, err := priv.SignCompactRecoverable(rand.Reader, testMessageHash)
+		require.NoError(t, err, "SignCompactRecoverable")
+		_, err = RecoverCompact(testMessageHash, sig[:CompactSignatureSize])
+		require.ErrorIs(t, err, errInvalidCompactSig, "RecoverCompact - truncated")
+
+		sig[CompactSignatureSize] += 27
+		_, err = RecoverCompact(testMessageHash, sig)
+		require.Error(t, err, "RecoverCompact - v with offset")
+	})
+	t.Run("ECDSA/VerifyRecoverable", func(t *testing.T) {
+		priv, err := GenerateKey()
+		require.NoError(t, err, "GenerateKey")
+		pub := priv.PublicKey()
+
+		opts := &ECDSAOptions{
+			Encoding: EncodingCompactRecoverable,
+		}
+		sig, err := priv.Sign(rand.Reader, testMessageHash, opts)
+		require.NoError(t, err, "Sign")
+
+		ok, err := pub.VerifyRecoverable(testMessageHash, sig)
+		require.NoError(t, err, "VerifyRecoverable")
+		require.True(t, ok, "VerifyRecoverable")
+
+		tmp := bytes.Clone(sig)
+		tmp[CompactSignatureSize] ^= 0x01
+		ok, err = pub.VerifyRecoverable(testMessageHash, tmp)
+		require.ErrorIs(t, err, errVMismatch, "VerifyRecoverable - wrong V")
+		require.False(t, ok, "VerifyRecoverable - wrong V")
+
+		tmp[CompactSignatureSize] = 27
+		ok, err = pub.VerifyRecoverable(testMessageHash, tmp)
+		require.ErrorIs(t, err, errVMismatch, "VerifyRecoverable - invalid V")
+		require.False(t, ok, "VerifyRecoverable - invalid V")
+
+		otherPriv, err := GenerateKey()
+		require.NoError(t, err, "GenerateKey - other")
+		ok, err = otherPriv.PublicKey().VerifyRecoverable(testMessageHash, sig)
+		require.NoError(t, err, "VerifyRecoverable - wrong key")
+		require.False(t, ok, "VerifyRecoverable - wrong key")
+
+		ok, err = pub.VerifyRecoverable(testMessageHash, sig[:CompactSignatureSize])
+		require.ErrorIs(t, err, errInvalidCompactSig, "VerifyRecoverable - truncated")
+		require.False(t, ok, "VerifyRecoverable - truncated")
+	})
+	t.Run("ECDSA/VerifyASN1WithTrailingByte", func(t *testing.T) {
+		priv, err := GenerateKey()
+		require.NoError(t, err, "GenerateKey")
+		pub := priv.PublicKey()
+
+		const hashType = 0x41
+
+		r, s, _, err := priv.SignRaw(rand.Reader, testMessageHash)
+		require.NoError(t, err, "SignRaw")
+
+		sig := append(BuildASN1Signature(r, s), hashType)
+		ht, ok := pub.VerifyASN1WithTrailingByte(testMessageHash, sig)
+		require.True(t, ok, "VerifyASN1WithTrailingByte")
+		require.EqualValues(t, hashType, ht, "VerifyASN1WithTrailingByte - hash type")
+
+		// High-s is accepted.
+		sNeg := secp256k1.NewScalar().Negate(s)
+		sig = append(BuildASN1Signature(r, sNeg), hashType)
+		ht, ok = pub.VerifyASN1WithTrailingByte(testMessageHash, sig)
+		require.True(t, ok, "VerifyASN1WithTrailingByte - high-s")
+		require.EqualValues(t, hashType, ht, "VerifyASN1WithTrailingByte - high-s, hash type")
+
+		// Missing trailing byte.
+		ht, ok = pub.VerifyASN1WithTrailingByte(testMessageHash, BuildASN1Signature(r, s))
+		require.False(t, ok, "VerifyASN1WithTrailingByte - no trailing byte")
+		require.Zero(t, ht, "VerifyASN1WithTrailingByte - no trailing byte")
+
+		ht, ok = pub.VerifyASN1WithTrailingByte(testMessageHash, nil)
+		require.False(t, ok, "VerifyASN1WithTrailingByte - nil")
+		require.Zero(t, ht, "VerifyASN1WithTrailingByte - nil")
+
+		otherPriv, err := GenerateKey()
+		require.NoError(t, err, "GenerateKey - other")
+		_, ok = otherPriv.PublicKey().VerifyASN1WithTrailingByte(testMessageHash, sig)
+		require.False(t, ok, "VerifyASN1WithTrailingByte - wrong key")
+	})
+	t.Run("ECDSA/IsStrictDER", func(t *testing.T) {
+		priv, err := GenerateKey()
+		require.NoError(t, err, "GenerateKey")
+
+		for i := 0; i < 8; i++ {
+			r, s, _, err := priv.SignRaw(rand.Reader, testMessageHash)
+			require.NoError(t, err, "[%d]: SignRaw", i)
+
+			sig := BuildASN1Signature(r, s)
+			require.True(t, IsStrictDER(sig), "[%d]: IsStrictDER", i)
+			require.False(t, IsStrictDER(append(sig, 0x01)), "[%d]: IsStrictDER - trailing byte", i)
+		}
+
+		for i, sig := range [][]byte{
+			nil,
+			helpers.MustBytesFromHex("3006020101020101"), // Minimal valid
+		} {
+			require.Equal(t, i != 0, IsStrictDER(sig), "[%d]: IsStrictDER", i)
+		}
+
+		for i, sig := range [][]byte{
+			helpers.MustBytesFromHex("300702020001020101"), // Padded R
+			helpers.MustBytesFromHex("300702010102020001"), // Padded S
+			helpers.MustBytesFromHex("3006020181020101"),   // Negative R
+			helpers.MustBytesFromHex("3006020101020181"),   // Negative S
+			helpers.MustBytesFromHex("3007020101020101"),   // Bad total length
+			helpers.MustBytesFromHex("3106020101020101"),   // Not a SEQUENCE
+			helpers.MustBytesFromHex("3006030101020101"),   // R not an INTEGER
+			helpers.MustBytesFromHex("3006020101030101"),   // S not an INTEGER
+			helpers.MustBytesFromHex("3006020002020081"),   // Zero-length R
+		} {
+			require.False(t, IsStrictDER(sig), "[%d]: IsStrictDER - invalid", i)
+		}
+	})
+	t.Run("ECDSA/SignLowRRecoverable", func(t *testing.T) {
+		priv, err := GenerateKey()
+		require.NoError(t, err, "GenerateKey")
+		pub := priv.PublicKey()
+
+		for i := 0; i < 16; i++ {
+			sig, err := priv.SignLowRRecoverable(rand.Reader, testMessageHash)
+			require.NoError(t, err, "[%d]: SignLowRRecoverable", i)
+			require.Len(t, sig, CompactRecoverableSignatureSize, "[%d]: SignLowRRecoverable", i)
+			require.Zero(t, sig[0]&0x80, "[%d]: SignLowRRecoverable - low-R", i)
+
+			r, s, v, err := ParseCompactRecoverableSignature(sig)
+			require.NoError(t, err, "[%d]: ParseCompactRecoverableSignature", i)
+			require.EqualValues(t, 0, s.IsGreaterThanHalfN(), "[%d]: SignLowRRecoverable - low-S", i)
+			require.LessOrEqual(t, len(BuildASN1Signature(r, s)), 70, "[%d]: SignLowRRecoverable - ASN.1 length", i)
+
+			q, err := RecoverPublicKey(testMessageHash, r, s, v)
+			require.NoError(t, err, "[%d]: RecoverPublicKey", i)
+			require.True(t, pub.Equal(q), "[%d]: RecoverPublicKey", i)
+		}
+
+		sig, err := priv.SignLowRRecoverable(RFC6979SHA256(), testMessageHash)
+		require.Nil(t, sig, "SignLowRRecoverable - RFC6979")
+		require.ErrorIs(t, err, errLowRGrinding, "SignLowRRecoverable - RFC6979")
+	})
+	t.Run("ECDSA/EIP2098", func(t *testing.T) {
+		priv, err := GenerateKey()
+		require.NoError(t, err, "GenerateKey")
+		pub := priv.PublicKey()
+
+		opts := &ECDSAOptions{
+			Encoding: EncodingEIP2098,
+		}
+		for i := 0; i < 8; i++ {
+			sig, err := priv.Sign(rand.Reader, testMessageHash, opts)
+			require.NoError(t, err, "[%d]: Sign", i)
+			require.Len(t, sig, CompactSignatureSize, "[%d]: Sign", i)
+			require.True(t, pub.Verify(testMessageHash, sig, opts), "[%d]: Verify", i)
+
+			r, s, v, err := ParseEIP2098Signature(sig)
+			require.NoError(t, err, "[%d]: ParseEIP2098Signature", i)
+			require.True(t, pub.VerifyRaw(testMessageHash, r, s), "[%d]: VerifyRaw", i)
+			q, err := RecoverPublicKey(testMessageHash, r, s, v)
+			require.NoError(t, err, "[%d]: RecoverPublicKey", i)
+			require.True(t, pub.Equal(q), "[%d]: RecoverPublicKey", i)
+
+			sig[32] ^= 0x80
+			require.False(t, pub.Verify(testMessageHash, sig, opts), "[%d]: Verify - flipped parity", i)
+		}
+
+		r, s, _, err := priv.SignRaw(rand.Reader, testMessageHash)
+		require.NoError(t, err, "SignRaw")
+		_, err = BuildEIP2098Signature(r, secp256k1.NewScalar().Negate(s), 0)
+		require.ErrorIs(t, err, errInvalidScalar, "BuildEIP2098Signature - high s")
+		_, err = BuildEIP2098Signature(r, s, 2)
+		require.ErrorIs(t, err, errInvalidRecoveryID, "BuildEIP2098Signature - v = 2")
+	})
+	t.Run("ECDSA/CompactLowS", func(t *testing.T) {
+		priv, err := GenerateKey()
+		require.NoError(t, err, "GenerateKey")
+		pub := priv.PublicKey()
+
+		r, s, _, err := priv.SignRaw(rand.Reader, testMessageHash)
+		require.NoError(t, err, "SignRaw")
+		highS := secp256k1.NewScalar().Negate(s)
+
+		sig := BuildCompactSignatureLowS(r, highS)
+		require.Equal(t, BuildCompactSignature(r, s), sig, "BuildCompactSignatureLowS - normalized")
+		require.Equal(t, sig, BuildCompactSignatureLowS(r, s), "BuildCompactSignatureLowS - low s")
+		require.EqualValues(t, 1, s.Equal(secp256k1.NewScalar().Negate(highS)), "BuildCompactSignatureLowS - s unaltered")
+
+		r2, s2, err := ParseCompactSignatureLowS(sig)
+		require.NoError(t, err, "ParseCompactSignatureLowS")
+		require.True(t, pub.VerifyRaw(testMessageHash, r2, s2), "VerifyRaw")
+
+		_, _, err = ParseCompactSignatureLowS(BuildCompactSignature(r, highS))
+		require.ErrorIs(t, err, errInvalidScalar, "ParseCompactSignatureLowS - high s")
+		_, _, err = ParseCompactSignatureLowS(sig[:15])
+		require.ErrorIs(t, err, errInvalidCompactSig, "ParseCompactSignatureLowS - truncated")
+	})
+	t.Run("ECDSA/ConvertSignature", func(t *testing.T) {
+		priv, err := GenerateKey()
+		require.NoError(t, err, "GenerateKey")
+		pub := priv.PublicKey()
+
+		r, s, v, err := priv.SignRaw(rand.Reader, testMessageHash)
+		require.NoError(t, err, "SignRaw")
+		eip2098Sig, err := BuildEIP2098Signature(r, s, v)
+		require.NoError(t, err, "BuildEIP2098Signature")
+
+		sigs := map[SignatureEncoding][]byte{
+			EncodingASN1:               BuildASN1Signature(r, s),
+			EncodingCompact:            BuildCompactSignature(r, s),
+			EncodingCompactRecoverable: BuildCompactRecoverableSignature(r, s, v),
+			EncodingEIP2098:            eip2098Sig,
+		}
+		for from, sig := range sigs {
+			for to, expected := range sigs {
+				converted, err := ConvertSignature(sig, from, to, pub, testMessageHash)
+				require.NoError(t, err, "ConvertSignature(%d, %d)", from, to)
+				require.Equal(t, expected, converted, "ConvertSignature(%d, %d)", from, to)
+			}
+		}
+
+		// High-s is normalized, and the recovery ID is re-derived.
+		sNeg := secp256k1.NewScalar().Negate(s)
+		highSig := BuildCompactRecoverableSignature(r, sNeg, v^1)
+		for to, expected := range sigs {
+			converted, err := ConvertSignature(highSig, EncodingCompactRecoverable, to, pub, testMessageHash)
+			require.NoError(t, err, "ConvertSignature(high-s, %d)", to)
+			require.Equal(t, expected, converted, "ConvertSignature(high-s, %d)", to)
+		}
+
+		otherPriv, err := GenerateKey()
+		require.NoError(t, err, "GenerateKey - other")
+		_, err = ConvertSignature(sigs[EncodingASN1], EncodingASN1, EncodingEIP2098, otherPriv.PublicKey(), testMessageHash)
+		require.ErrorIs(t, err, errInvalidSignature, "ConvertSignature - wrong key")
+
+		_, err = ConvertSignature(sigs[EncodingASN1], EncodingCompact, EncodingASN1, pub, testMessageHash)
+		require.ErrorIs(t, err, errInvalidCompactSig, "ConvertSignature - wrong from")
+
+		_, err = ConvertSignature(sigs[EncodingASN1], EncodingASN1, SignatureEncoding(69), pub, testMessageHash)
+		require.ErrorIs(t, err, errInvalidEncoding, "ConvertSignature - invalid to")
+	})
+	t.Run("ECDSA/VerifyStrict", func(t *testing.T) {
+		priv, err := GenerateKey()
+		require.NoError(t, err, "GenerateKey")
+		pub := priv.PublicKey()
+
+		r, s, _, err := priv.SignRaw(rand.Reader, testMessageHash)
+		require.NoError(t, err, "SignRaw")
+
+		ok, xReduced := pub.VerifyStrict(testMessageHash, r, s)
+		require.True(t, ok, "VerifyStrict")
+		require.False(t, xReduced, "VerifyStrict - xReduced")
+
+		ok, _ = pub.VerifyStrict(testMessageHash, s, r)
+		require.False(t, ok, "VerifyStrict - Bad sig")
+
+		var zero secp256k1.Scalar
+		ok, _ = pub.VerifyStrict(testMessageHash, &zero, s)
+		require.False(t, ok, "VerifyStrict - Zero r")
+		ok, _ = pub.VerifyStrict(testMessageHash[:31], r, s)
+		require.False(t, ok, "VerifyStrict - Truncated h")
+
+		// Forge a signature where xR >= n, by picking a small r that
+		// has a valid R with x = r + n, and recovering the public key.
+		var q *PublicKey
+		for i := uint64(1); ; i++ {
+			r = secp256k1.NewScalarFromUint64(i)
+			if q, err = RecoverPublicKey(testMessageHash, r, s, 2); err == nil {
+				break
+			}
+		}
+
+		ok, xReduced = q.VerifyStrict(testMessageHash, r, s)
+		require.True(t, ok, "VerifyStrict - forged")
+		require.True(t, xReduced, "VerifyStrict - forged xReduced")
 	})
 	t.Run("ECDSA/K", testEcdsaK)
 	t.Run("PrivateKey/Invalid", func(t *testing.T) {
@@ -217,6 +1115,56 @@ func TestSecec(t *testing.T) {
 			require.ErrorIs(t, err, errInvalidPrivateKey, "NewPrivateKey(%x)", v)
 		}
 	})
+	t.Run("PrivateKey/PublicKeysEqual", func(t *testing.T) {
+		const keyHex = "8a5c0f7d3e1b2a4968f0c1d2e3f40516a7b8c9d0e1f203142536475869708192"
+
+		// The same key, imported via different encodings.
+		priv1, err := NewPrivateKey(helpers.MustBytesFromHex(keyHex))
+		require.NoError(t, err, "NewPrivateKey - hex")
+		s, err := secp256k1.NewScalarFromCanonicalBytes(helpers.Must256BitsFromHex(keyHex))
+		require.NoError(t, err, "NewScalarFromCanonicalBytes")
+		priv2, err := NewPrivateKeyFromScalar(s)
+		require.NoError(t, err, "NewPrivateKeyFromScalar")
+		priv3, err := UnmarshalVersionedPrivateKey(priv1.MarshalVersioned())
+		require.NoError(t, err, "UnmarshalVersionedPrivateKey")
+
+		for i, other := range []*PrivateKey{priv2, priv3} {
+			require.True(t, priv1.Equal(other), "[%d]: Equal", i)
+			require.True(t, priv1.PublicKeysEqual(other), "[%d]: PublicKeysEqual", i)
+		}
+
+		otherPriv, err := GenerateKey()
+		require.NoError(t, err, "GenerateKey")
+		require.False(t, priv1.PublicKeysEqual(otherPriv), "PublicKeysEqual - different")
+	})
+	t.Run("PrivateKey/Versioned", func(t *testing.T) {
+		priv, err := GenerateKey()
+		require.NoError(t, err, "GenerateKey")
+
+		b := priv.MarshalVersioned()
+		require.Len(t, b, VersionedPrivateKeySize, "MarshalVersioned")
+		require.Equal(t, priv.Bytes(), b[2:], "MarshalVersioned - key")
+
+		priv2, err := UnmarshalVersionedPrivateKey(b)
+		require.NoError(t, err, "UnmarshalVersionedPrivateKey")
+		require.True(t, priv.Equal(priv2), "UnmarshalVersionedPrivateKey")
+
+		for _, tc := range []struct {
+			n   string
+			b   []byte
+			err error
+		}{
+			{"raw key", priv.Bytes()[:1], errInvalidVersionedKey},
+			{"truncated", b[:VersionedPrivateKeySize-1], errInvalidVersionedKey},
+			{"bad version", append([]byte{0x02}, b[1:]...), errUnsupportedVersion},
+			{"bad type", append([]byte{b[0], 0x69}, b[2:]...), errUnexpectedKeyType},
+			{"bad key", append(bytes.Clone(b[:2]), make([]byte, PrivateKeySize)...), errInvalidPrivateKey},
+		} {
+			k, err := UnmarshalVersionedPrivateKey(tc.b)
+			require.Nil(t, k, "UnmarshalVersionedPrivateKey - %s", tc.n)
+			require.ErrorIs(t, err, tc.err, "UnmarshalVersionedPrivateKey - %s", tc.n)
+		}
+	})
 	t.Run("PublicKey/Invalid", func(t *testing.T) {
 		k, err := NewPublicKey([]byte{0x00})
 		require.Nil(t, k, "NewPublicKey - identity")
@@ -230,6 +1178,305 @@ func TestSecec(t *testing.T) {
 			new(PublicKey).Bytes()
 		}, "uninitialized.Bytes()")
 	})
+	t.Run("PublicKey/IsCanonicalCompressedPublicKey", func(t *testing.T) {
+		priv, err := GenerateKey()
+		require.NoError(t, err, "GenerateKey")
+
+		pub := priv.PublicKey()
+		compressed := pub.CompressedBytes()
+		require.True(t, IsCanonicalCompressedPublicKey(compressed), "compressed")
+
+		tmp := bytes.Clone(compressed)
+		tmp[0] ^= 0x01
+		require.True(t, IsCanonicalCompressedPublicKey(tmp), "compressed - other Y")
+
+		for _, v := range []struct {
+			n string
+			b []byte
+		}{
+			{"uncompressed", pub.Bytes()},
+			{"identity", []byte{0x00}},
+			{"truncated", compressed[:32]},
+			{"bad prefix", append([]byte{0x04}, compressed[1:]...)},
+			{"x = p", helpers.MustBytesFromHex("02fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f")},
+			{"x not on curve", helpers.MustBytesFromHex("020000000000000000000000000000000000000000000000000000000000000005")},
+		} {
+			require.False(t, IsCanonicalCompressedPublicKey(v.b), v.n)
+		}
+	})
+	t.Run("PublicKey/CommitScalar", func(t *testing.T) {
+		priv, err := GenerateKey()
+		require.NoError(t, err, "GenerateKey")
+
+		pub, err := CommitScalar(priv.Scalar())
+		require.NoError(t, err, "CommitScalar")
+		require.True(t, priv.PublicKey().Equal(pub), "CommitScalar")
+
+		pub, err = CommitScalar(secp256k1.NewScalar())
+		require.Nil(t, pub, "CommitScalar - zero")
+		require.ErrorIs(t, err, errInvalidScalar, "CommitScalar - zero")
+	})
+	t.Run("PublicKey/Fingerprint", func(t *testing.T) {
+		priv, err := NewPrivateKeyFromScalar(secp256k1.NewScalarFromUint64(1))
+		require.NoError(t, err, "NewPrivateKeyFromScalar")
+		pub := priv.PublicKey()
+
+		expected := sha256.Sum256(pub.CompressedBytes())
+		require.EqualValues(t, expected[:], pub.Fingerprint(crypto.SHA256), "Fingerprint(SHA256)")
+		require.EqualValues(t, helpers.MustBytesFromHex("0f715baf5d4c2ed329785cef29e562f73488c8a2bb9dbc5700b361d54b9b0554"), pub.Fingerprint(crypto.SHA256), "Fingerprint(SHA256) - KAT")
+		require.Len(t, pub.Fingerprint(crypto.SHA512), 64, "Fingerprint(SHA512)")
+
+		require.Equal(t, "D3Fbr11MLtMpeFzvKeVi9zSIyKK7nbxXALNh1UubBVQ=", pub.FingerprintString(), "FingerprintString")
+	})
+	t.Run("PublicKey/VerifyASN1PEM", func(t *testing.T) {
+		priv, err := GenerateKey()
+		require.NoError(t, err, "GenerateKey")
+
+		pemPub := pem.EncodeToMemory(&pem.Block{
+			Type:  "PUBLIC KEY",
+			Bytes: priv.PublicKey().ASN1Bytes(),
+		})
+
+		sig, err := priv.Sign(rand.Reader, testMessageHash, nil)
+		require.NoError(t, err, "Sign")
+
+		ok, err := VerifyASN1PEM(pemPub, testMessageHash, sig)
+		require.NoError(t, err, "VerifyASN1PEM")
+		require.True(t, ok, "VerifyASN1PEM")
+
+		ok, err = VerifyASN1PEM(pemPub, testMessageHash[1:], sig)
+		require.NoError(t, err, "VerifyASN1PEM - truncated hash")
+		require.False(t, ok, "VerifyASN1PEM - truncated hash")
+
+		ok, err = VerifyASN1PEM(priv.PublicKey().ASN1Bytes(), testMessageHash, sig)
+		require.ErrorIs(t, err, errInvalidPEM, "VerifyASN1PEM - DER")
+		require.False(t, ok, "VerifyASN1PEM - DER")
+
+		badType := pem.EncodeToMemory(&pem.Block{
+			Type:  "EC PRIVATE KEY",
+			Bytes: priv.PublicKey().ASN1Bytes(),
+		})
+		_, err = VerifyASN1PEM(badType, testMessageHash, sig)
+		require.ErrorIs(t, err, errInvalidPEM, "VerifyASN1PEM - wrong type")
+
+		badSPKI := pem.EncodeToMemory(&pem.Block{
+			Type:  "PUBLIC KEY",
+			Bytes: priv.PublicKey().ASN1Bytes()[1:],
+		})
+		_, err = VerifyASN1PEM(badSPKI, testMessageHash, sig)
+		require.ErrorIs(t, err, errInvalidAsn1SPKI, "VerifyASN1PEM - bad SPKI")
+	})
+	t.Run("PEM", func(t *testing.T) {
+		// Generated with `openssl ecparam -name secp256k1 -genkey -noout`
+		// and `openssl ec -pubout`.
+		pemPriv, err := os.ReadFile("testdata/openssl_ec_private_key.pem")
+		require.NoError(t, err, "ReadFile - private key")
+		pemPub, err := os.ReadFile("testdata/openssl_public_key.pem")
+		require.NoError(t, err, "ReadFile - public key")
+
+		priv, err := ParsePEMPrivateKey(pemPriv)
+		require.NoError(t, err, "ParsePEMPrivateKey")
+		require.Equal(t, helpers.MustBytesFromHex("313ed81d87168411746a3d9a0ea065e2740666a743c7acb965e12603307c2624"), priv.Bytes(), "ParsePEMPrivateKey - scalar")
+
+		pub, err := ParsePEMPublicKey(pemPub)
+		require.NoError(t, err, "ParsePEMPublicKey")
+		require.True(t, pub.Equal(priv.PublicKey()), "public keys should match")
+
+		// OpenSSL output is byte-identical to ours.
+		require.Equal(t, pemPriv, MarshalPEMPrivateKey(priv), "MarshalPEMPrivateKey")
+		require.Equal(t, pemPub, MarshalPEMPublicKey(pub), "MarshalPEMPublicKey")
+
+		// The optional fields may be omitted.
+		priv2, err := ParseASN1PrivateKey(buildASN1PrivateKey(priv, false))
+		require.NoError(t, err, "ParseASN1PrivateKey - no parameters")
+		require.True(t, priv.Equal(priv2), "ParseASN1PrivateKey - no parameters")
+
+		var b cryptobyte.Builder
+		b.AddASN1(asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+			b.AddASN1Int64(1)
+			b.AddASN1OctetString(priv.Bytes())
+		})
+		priv2, err = ParseASN1PrivateKey(b.BytesOrPanic())
+		require.NoError(t, err, "ParseASN1PrivateKey - minimal")
+		require.True(t, priv.Equal(priv2), "ParseASN1PrivateKey - minimal")
+
+		// Mismatched public key.
+		otherPriv, err := GenerateKey()
+		require.NoError(t, err, "GenerateKey")
+		b = cryptobyte.Builder{}
+		b.AddASN1(asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+			b.AddASN1Int64(1)
+			b.AddASN1OctetString(priv.Bytes())
+			b.AddASN1(tagECPrivKeyPublicKey, func(b *cryptobyte.Builder) {
+				b.AddASN1BitString(otherPriv.PublicKey().Bytes())
+			})
+		})
+		_, err = ParseASN1PrivateKey(b.BytesOrPanic())
+		require.ErrorIs(t, err, errInvalidAsn1PubMismatch, "ParseASN1PrivateKey - mismatched public key")
+
+		_, err = ParseASN1PrivateKey(priv.ASN1Bytes()[1:])
+		require.ErrorIs(t, err, errInvalidAsn1PrivateKey, "ParseASN1PrivateKey - truncated")
+		_, err = ParsePEMPrivateKey(pemPub)
+		require.ErrorIs(t, err, errInvalidPEMPrivateKey, "ParsePEMPrivateKey - wrong type")
+		_, err = ParsePEMPublicKey(pemPriv)
+		require.ErrorIs(t, err, errInvalidPEM, "ParsePEMPublicKey - wrong type")
+	})
+	t.Run("PKCS8", func(t *testing.T) {
+		// Generated with `openssl pkcs8 -topk8 -nocrypt`, from the
+		// same key as `testdata/openssl_ec_private_key.pem`.
+		pemPKCS8, err := os.ReadFile("testdata/openssl_pkcs8_private_key.pem")
+		require.NoError(t, err, "ReadFile")
+		block, _ := pem.Decode(pemPKCS8)
+		require.NotNil(t, block, "pem.Decode")
+
+		priv, err := ParsePKCS8PrivateKey(block.Bytes)
+		require.NoError(t, err, "ParsePKCS8PrivateKey")
+		require.Equal(t, helpers.MustBytesFromHex("313ed81d87168411746a3d9a0ea065e2740666a743c7acb965e12603307c2624"), priv.Bytes(), "ParsePKCS8PrivateKey - scalar")
+
+		der, err := MarshalPKCS8PrivateKey(priv)
+		require.NoError(t, err, "MarshalPKCS8PrivateKey")
+		require.Equal(t, block.Bytes, der, "MarshalPKCS8PrivateKey - matches OpenSSL")
+
+		// The inner key may carry the curve parameters.
+		buildPKCS8 := func(oidCurve stdasn1.ObjectIdentifier, ecPrivKey []byte) []byte {
+			var b cryptobyte.Builder
+			b.AddASN1(asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+				b.AddASN1Int64(0)
+				b.AddASN1(asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+					b.AddASN1ObjectIdentifier(oidEcPublicKey)
+					b.AddASN1ObjectIdentifier(oidCurve)
+				})
+				b.AddASN1OctetString(ecPrivKey)
+			})
+			return b.BytesOrPanic()
+		}
+		priv2, err := ParsePKCS8PrivateKey(buildPKCS8(oidSecp256k1, priv.ASN1Bytes()))
+		require.NoError(t, err, "ParsePKCS8PrivateKey - inner parameters")
+		require.True(t, priv.Equal(priv2), "ParsePKCS8PrivateKey - inner parameters")
+
+		_, err = ParsePKCS8PrivateKey(buildPKCS8(stdasn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7}, priv.ASN1Bytes()))
+		require.ErrorIs(t, err, errInvalidAsn1Curve, "ParsePKCS8PrivateKey - P-256")
+		_, err = ParsePKCS8PrivateKey(der[1:])
+		require.ErrorIs(t, err, errInvalidPKCS8, "ParsePKCS8PrivateKey - truncated")
+	})
+	t.Run("JWK", func(t *testing.T) {
+		priv, err := GenerateKey()
+		require.NoError(t, err, "GenerateKey")
+		pub := priv.PublicKey()
+
+		for _, crv := range []string{"", JWKCurveSecp256k1, JWKCurveP256K} {
+			b, err := MarshalJWKPublicKey(pub, crv)
+			require.NoError(t, err, "MarshalJWKPublicKey(%s)", crv)
+			pub2, err := ParseJWKPublicKey(b)
+			require.NoError(t, err, "ParseJWKPublicKey(%s)", crv)
+			require.True(t, pub.Equal(pub2), "ParseJWKPublicKey(%s)", crv)
+
+			b, err = MarshalJWKPrivateKey(priv, crv)
+			require.NoError(t, err, "MarshalJWKPrivateKey(%s)", crv)
+			priv2, err := ParseJWKPrivateKey(b)
+			require.NoError(t, err, "ParseJWKPrivateKey(%s)", crv)
+			require.True(t, priv.Equal(priv2), "ParseJWKPrivateKey(%s)", crv)
+
+			pub2, err = ParseJWKPublicKey(b)
+			require.NoError(t, err, "ParseJWKPublicKey(%s) - private", crv)
+			require.True(t, pub.Equal(pub2), "ParseJWKPublicKey(%s) - private", crv)
+		}
+
+		_, err = MarshalJWKPublicKey(pub, "P-256")
+		require.ErrorIs(t, err, errInvalidJWKCurve, "MarshalJWKPublicKey - P-256")
+
+		// The generator.
+		b := []byte(`{"kty":"EC","crv":"secp256k1","x":"eb5mfvncu6xVoGKVzocLBwKb_NstzijZWfKBWxb4F5g","y":"SDradyajxGVdpPv8DhEIqP0XtEimhVQZnEfQj_sQ1Lg"}`)
+		expected := secp256k1.NewGeneratorPoint().UncompressedBytes()
+		g, err := ParseJWKPublicKey(b)
+		require.NoError(t, err, "ParseJWKPublicKey - generator")
+		require.Equal(t, expected, g.Bytes(), "ParseJWKPublicKey - generator")
+
+		for _, bad := range []string{
+			`{"kty":"EC","crv":"P-256","x":"eb5mfvncu6xVoGKVzocLBwKb_NstzijZWfKBWxb4F5g","y":"SDradyajxGVdpPv8DhEIqP0XtEimhVQZnEfQj_sQ1Lg"}`,
+			`{"kty":"OKP","crv":"secp256k1","x":"eb5mfvncu6xVoGKVzocLBwKb_NstzijZWfKBWxb4F5g","y":"SDradyajxGVdpPv8DhEIqP0XtEimhVQZnEfQj_sQ1Lg"}`,
+			`{"kty":"EC","crv":"secp256k1","x":"eb5mfvncu6xVoGKVzocLBwKb_NstzijZWfKBWxb4F5g","y":"SDradyajxGVdpPv8DhEIqP0XtEimhVQZnEfQj_sQ1Lw"}`,
+			`{"kty":"EC","crv":"secp256k1","x":"eb5mfvncu6xVoGKVzocLBwKb_NstzijZWfKBWxb4F5g","y":"SDradyajxGVdpPv8DhEIqP0XtEimhVQZnEfQj_sQ1Lh"}`, // Non-canonical
+			`{"kty":"EC","crv":"secp256k1","x":"eb5mfvncu6xVoGKVzocLBwKb_NstzijZWfKBWxb4F5g"}`,
+			`not json`,
+		} {
+			_, err = ParseJWKPublicKey([]byte(bad))
+			require.Error(t, err, "ParseJWKPublicKey(%s)", bad)
+		}
+
+		// Mismatched private key.
+		otherPriv, err := GenerateKey()
+		require.NoError(t, err, "GenerateKey")
+		b, err = MarshalJWKPublicKey(otherPriv.PublicKey(), "")
+		require.NoError(t, err, "MarshalJWKPublicKey")
+		b = append(b[:len(b)-1], []byte(`,"d":"`+base64.RawURLEncoding.EncodeToString(priv.Bytes())+`"}`)...)
+		_, err = ParseJWKPrivateKey(b)
+		require.ErrorIs(t, err, errInvalidJWK, "ParseJWKPrivateKey - mismatch")
+	})
+	t.Run("PublicKey/OpenSSH", func(t *testing.T) {
+		priv, err := GenerateKey()
+		require.NoError(t, err, "GenerateKey")
+		pub := priv.PublicKey()
+
+		b := MarshalOpenSSHPublicKey(pub)
+		expectedPrefix := append([]byte{0, 0, 0, 23}, "ecdsa-sha2-1.3.132.0.10"...)
+		expectedPrefix = append(expectedPrefix, 0, 0, 0, 12)
+		expectedPrefix = append(expectedPrefix, "1.3.132.0.10"...)
+		expectedPrefix = append(expectedPrefix, 0, 0, 0, 65)
+		require.Equal(t, expectedPrefix, b[:len(expectedPrefix)], "MarshalOpenSSHPublicKey - prefix")
+		require.Equal(t, pub.Bytes(), b[len(expectedPrefix):], "MarshalOpenSSHPublicKey - point")
+
+		pub2, err := ParseOpenSSHPublicKey(b)
+		require.NoError(t, err, "ParseOpenSSHPublicKey")
+		require.True(t, pub.Equal(pub2), "ParseOpenSSHPublicKey")
+
+		_, err = ParseOpenSSHPublicKey(b[:len(b)-1])
+		require.ErrorIs(t, err, errInvalidSSHKey, "ParseOpenSSHPublicKey - truncated")
+		_, err = ParseOpenSSHPublicKey(append(bytes.Clone(b), 0x00))
+		require.ErrorIs(t, err, errInvalidSSHKey, "ParseOpenSSHPublicKey - trailing garbage")
+
+		tmp := bytes.Clone(b)
+		copy(tmp[4:], "ecdsa-sha2-nistp256")
+		_, err = ParseOpenSSHPublicKey(tmp)
+		require.ErrorIs(t, err, errInvalidSSHKeyType, "ParseOpenSSHPublicKey - wrong key type")
+
+		tmp = bytes.Clone(b)
+		tmp[len(tmp)-1] ^= 0x69
+		_, err = ParseOpenSSHPublicKey(tmp)
+		require.Error(t, err, "ParseOpenSSHPublicKey - invalid point")
+	})
+	t.Run("PublicKey/NewPublicKeys", func(t *testing.T) {
+		var (
+			keys     [][]byte
+			expected []*PublicKey
+		)
+		for i := 0; i < 10; i++ {
+			priv, err := GenerateKey()
+			require.NoError(t, err, "GenerateKey")
+
+			pub := priv.PublicKey()
+			expected = append(expected, pub)
+			if i&1 == 0 {
+				keys = append(keys, pub.CompressedBytes())
+			} else {
+				keys = append(keys, pub.Bytes())
+			}
+		}
+
+		pks, err := NewPublicKeys(keys)
+		require.NoError(t, err, "NewPublicKeys")
+		require.Len(t, pks, len(expected), "NewPublicKeys")
+		for i, pk := range pks {
+			require.True(t, expected[i].Equal(pk), "[%d]: NewPublicKeys", i)
+		}
+
+		keys[3] = []byte{0x00}
+		keys[5] = []byte("not a key")
+		pks, err = NewPublicKeys(keys)
+		require.Nil(t, pks, "NewPublicKeys - invalid")
+		require.ErrorIs(t, err, errAIsInfinity, "NewPublicKeys - invalid")
+		require.ErrorContains(t, err, "index 3", "NewPublicKeys - invalid")
+	})
 	t.Run("PublicKey/Polarity", func(t *testing.T) {
 		var (
 			gotOdd, gotEven bool
@@ -251,6 +1498,30 @@ func TestSecec(t *testing.T) {
 		}
 		t.Logf("%d iters to see both odd and even Y", i+1)
 	})
+	t.Run("SelfTest", func(t *testing.T) {
+		require.NoError(t, SelfTest(), "SelfTest")
+	})
+	t.Run("DeterministicRand", func(t *testing.T) {
+		seed := []byte("reproducible simulation seed")
+
+		priv1, err := GenerateKeyFromReader(DeterministicRand(seed))
+		require.NoError(t, err, "GenerateKeyFromReader - 1")
+		priv2, err := GenerateKeyFromReader(DeterministicRand(seed))
+		require.NoError(t, err, "GenerateKeyFromReader - 2")
+		require.True(t, priv1.Equal(priv2), "GenerateKeyFromReader - same seed")
+
+		priv3, err := GenerateKeyFromReader(DeterministicRand([]byte("other seed")))
+		require.NoError(t, err, "GenerateKeyFromReader - 3")
+		require.False(t, priv1.Equal(priv3), "GenerateKeyFromReader - different seed")
+
+		// ECDSA signing is deterministic given the entropy source.
+		sig1, err := priv1.Sign(DeterministicRand(seed), testMessageHash, nil)
+		require.NoError(t, err, "Sign - 1")
+		sig2, err := priv1.Sign(DeterministicRand(seed), testMessageHash, nil)
+		require.NoError(t, err, "Sign - 2")
+		require.Equal(t, sig1, sig2, "Sign - same seed")
+		require.True(t, priv1.PublicKey().Verify(testMessageHash, sig1, nil), "Verify")
+	})
 	t.Run("Internal/sampleRandomScalar", func(t *testing.T) {
 		// All-zero entropy source should cause the rejection sampling
 		// to give up, because it keeps generating scalars that are 0.
@@ -341,6 +1612,15 @@ func BenchmarkSecec(b *testing.B) {
 				_, _ = randomPriv.Sign(RFC6979SHA256(), testMessageHash, nil)
 			}
 		})
+		b.Run("Sign/Signer", func(b *testing.B) {
+			sr := randomPriv.NewSigner("benchmark")
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				_, _ = sr.SignASN1(rand.Reader, testMessageHash)
+			}
+		})
 		b.Run("Sign/Paranoid", func(b *testing.B) {
 			opts := &ECDSAOptions{
 				SelfVerify: true,
@@ -362,6 +1642,14 @@ func BenchmarkSecec(b *testing.B) {
 				_ = randomPub.Bytes()
 			}
 		})
+		b.Run("CompressedBytes", func(b *testing.B) {
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				_ = randomPub.CompressedBytes()
+			}
+		})
 		b.Run("Verify", func(b *testing.B) {
 			b.ReportAllocs()
 			b.ResetTimer()
@@ -371,6 +1659,25 @@ func BenchmarkSecec(b *testing.B) {
 				require.True(b, ok)
 			}
 		})
+		b.Run("Verify/Verifier", func(b *testing.B) {
+			vr := NewVerifier()
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				ok := vr.VerifyASN1(randomPub, testMessageHash, randomSig)
+				require.True(b, ok)
+			}
+		})
+		b.Run("Verify/VerifyDERFast", func(b *testing.B) {
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				ok := randomPub.VerifyDERFast(testMessageHash, randomSig)
+				require.True(b, ok)
+			}
+		})
 		b.Run("Recover", func(b *testing.B) {
 			b.ReportAllocs()
 			b.ResetTimer()
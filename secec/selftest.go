@@ -0,0 +1,121 @@
+// Copyright (c) 2023 Yawning Angel
+//
+// SPDX-License-Identifier: BSD-3-Clause
+
+package secec
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+
+	"gitlab.com/yawning/secp256k1-voi"
+)
+
+const (
+	// https://bitcointalk.org/index.php?topic=285142.40
+	selfTestRFC6979Message = "Absence makes the heart grow fonder."
+	selfTestRFC6979Sig     = "3045022100afff580595971b8c1700e77069d73602aef4c2a760dbd697881423dfff845de80220579adb6a1ac03acde461b5821a049ebd39a8a8ebf2506b841b15c27342d2e342"
+
+	selfTestSeed = "secp256k1-voi/secec/SelfTest"
+)
+
+var (
+	errSelfTestRFC6979   = errors.New("secp256k1/secec: self-test failed: RFC 6979 known-answer")
+	errSelfTestRoundTrip = errors.New("secp256k1/secec: self-test failed: ECDSA round-trip")
+	errSelfTestDomainSep = errors.New("secp256k1/secec: self-test failed: nonce domain separation")
+)
+
+// SelfTest runs a set of known-answer and consistency checks, including
+// `secp256k1.SelfTest`, suitable for use as a power-on self-test, and
+// returns nil iff all of the checks pass.
+func SelfTest() error {
+	if err := secp256k1.SelfTest(); err != nil {
+		return err
+	}
+
+	if err := selfTestRFC6979(); err != nil {
+		return fmt.Errorf("%w: %w", errSelfTestRFC6979, err)
+	}
+	if err := selfTestRoundTrip(); err != nil {
+		return fmt.Errorf("%w: %w", errSelfTestRoundTrip, err)
+	}
+	if err := selfTestDomainSep(); err != nil {
+		return fmt.Errorf("%w: %w", errSelfTestDomainSep, err)
+	}
+
+	return nil
+}
+
+func selfTestRFC6979() error {
+	k, err := NewPrivateKeyFromScalar(secp256k1.NewScalarFromUint64(1))
+	if err != nil {
+		return err
+	}
+
+	digest := sha256.Sum256([]byte(selfTestRFC6979Message))
+	sig, err := k.Sign(RFC6979SHA256(), digest[:], nil)
+	if err != nil {
+		return err
+	}
+	if hex.EncodeToString(sig) != selfTestRFC6979Sig {
+		return errors.New("signature mismatch")
+	}
+
+	return nil
+}
+
+func selfTestRoundTrip() error {
+	rng := DeterministicRand([]byte(selfTestSeed))
+
+	k, err := GenerateKeyFromReader(rng)
+	if err != nil {
+		return err
+	}
+
+	digest := sha256.Sum256([]byte(selfTestSeed))
+	sig, err := k.Sign(rng, digest[:], nil)
+	if err != nil {
+		return err
+	}
+
+	pub := k.PublicKey()
+	if !pub.Verify(digest[:], sig, nil) {
+		return errors.New("valid signature rejected")
+	}
+
+	digest[0] ^= 0x01
+	if pub.Verify(digest[:], sig, nil) {
+		return errors.New("invalid signature accepted")
+	}
+
+	return nil
+}
+
+func selfTestDomainSep() error {
+	k, err := NewPrivateKeyFromScalar(secp256k1.NewScalarFromUint64(1))
+	if err != nil {
+		return err
+	}
+	e := secp256k1.NewScalarFromUint64(2)
+
+	var nonces [2][secp256k1.ScalarSize]byte
+	for i, ctx := range []string{domainSepECDSA, domainSepCounter} {
+		rd, err := mitigateDebianAndSony(DeterministicRand([]byte(selfTestSeed)), ctx, k, e)
+		if err != nil {
+			return err
+		}
+		if _, err = io.ReadFull(rd, nonces[i][:]); err != nil {
+			return err
+		}
+	}
+
+	if bytes.Equal(nonces[0][:], nonces[1][:]) {
+		return errors.New("nonces are identical")
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2023 Yawning Angel
+//
+// SPDX-License-Identifier: BSD-3-Clause
+
+package secec
+
+import (
+	csrand "crypto/rand"
+	"io"
+
+	"golang.org/x/crypto/sha3"
+
+	"gitlab.com/yawning/secp256k1-voi/internal/disalloweq"
+	"gitlab.com/yawning/secp256k1-voi/internal/rfc6979"
+)
+
+const domainSepSigner = "ECDSA-Sign-Signer:"
+
+// Signer is a ECDSA signer bound to a single PrivateKey and context
+// string, that caches the private key dependent portion of the nonce
+// derivation across calls, to reduce the per-signature overhead when
+// signing large numbers of digests.
+//
+// WARNING: A Signer is NOT safe for concurrent use.  Use one per
+// goroutine.
+type Signer struct {
+	_ disalloweq.DisallowEqual
+
+	k         *PrivateKey
+	nonceXOF  sha3.ShakeHash
+	nonceFunc NonceDerivationFunc
+}
+
+// NewSigner returns a new Signer for the PrivateKey `k`, with the
+// nonce derivation domain separated by the context string `ctx`.
+func (k *PrivateKey) NewSigner(ctx string) *Signer {
+	sr := &Signer{
+		k:        k,
+		nonceXOF: newNonceXOF(domainSepSigner+ctx, k),
+	}
+	sr.nonceFunc = sr.deriveNonce
+
+	return sr
+}
+
+// SignASN1 signs `digest` (which should be the result of hashing a
+// larger message) using the Signer's PrivateKey, using the signing
+// procedure as specified in SEC 1, Version 2.0, Section 4.1.3.  It
+// returns the ASN.1 encoded signature.
+//
+// Notes: If `rand` is nil, [crypto/rand.Reader] will be used.
+// `s` will always be less than or equal to `n / 2`.
+func (sr *Signer) SignASN1(rand io.Reader, digest []byte) ([]byte, error) {
+	if sr.nonceXOF == nil {
+		return nil, errZeroizedKey
+	}
+
+	r, s, _, err := sign(rand, sr.k, digest, sr.nonceFunc)
+	if err != nil {
+		return nil, err
+	}
+
+	return BuildASN1Signature(r, s), nil
+}
+
+func (sr *Signer) deriveNonce(priv *PrivateKey, _ string, digest []byte, rand io.Reader) (io.Reader, error) {
+	e, err := hashToScalar(digest)
+	if err != nil {
+		return nil, err
+	}
+
+	switch rand {
+	case readerRFC6979SHA256:
+		return rfc6979.NewDRBG(priv.scalar, e), nil
+	case nil:
+		rand = csrand.Reader
+	}
+
+	return mixNonceEntropy(sr.nonceXOF.Clone(), rand, e)
+}
+
+// Zeroize clears the cached private key dependent nonce derivation
+// state.  After Zeroize has been called, the Signer is unusable, and
+// all signing operations will return an error.  The Signer's
+// PrivateKey is left as is, and should be zeroized separately if
+// required.
+//
+// Note: `PrivateKey.Zeroize` does not clear the state cached by Signers
+// created from the key, so each Signer MUST be zeroized separately.
+// As with `PrivateKey.Zeroize`, this is best-effort, and can not erase
+// copies made by the underlying XOF implementation or the Go runtime.
+func (sr *Signer) Zeroize() {
+	if sr.nonceXOF != nil {
+		sr.nonceXOF.Reset()
+		sr.nonceXOF = nil
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright (c) 2023 Yawning Angel
+//
+// SPDX-License-Identifier: BSD-3-Clause
+
+package secec
+
+import (
+	"crypto"
+	"sync"
+
+	"gitlab.com/yawning/secp256k1-voi"
+	"gitlab.com/yawning/secp256k1-voi/internal/disalloweq"
+)
+
+var verifierPool = sync.Pool{
+	New: func() any {
+		return NewVerifier()
+	},
+}
+
+// Verifier is a ECDSA signature verifier that re-uses its temporary
+// scalars and points across calls, to reduce the number of heap
+// allocations incurred when verifying large numbers of signatures.
+//
+// WARNING: A Verifier is NOT safe for concurrent use.  Use one per
+// goroutine.
+type Verifier struct {
+	_ disalloweq.DisallowEqual
+
+	r, s, e *secp256k1.Scalar
+	scratch *verifyScratch
+
+	digestSize       int
+	rejectMalleable  bool
+	rejectZeroDigest bool
+}
+
+// VerifyASN1 verifies the ASN.1 encoded signature `sig` of `digest`,
+// using the PublicKey `k`, using the verification procedure as
+// specified in SEC 1, Version 2.0, Section 4.1.4.  Its return value
+// records whether the signature is valid.
+//
+// Note: This is equivalent to `k.Verify(digest, sig, opts)`, where
+// `opts` are the options the Verifier was created with, and does not
+// allocate.
+func (vr *Verifier) VerifyASN1(k *PublicKey, digest, sig []byte) bool {
+	if err := parseASN1Signature(vr.r, vr.s, sig); err != nil {
+		return false
+	}
+	if vr.rejectMalleable && vr.s.IsGreaterThanHalfN() != 0 {
+		return false
+	}
+
+	return vr.verify(k, digest, vr.r, vr.s)
+}
+
+// VerifyRaw verifies the `(r, s)` signature of `digest`, using the
+// PublicKey `k`, using the verification procedure as specified in
+// SEC 1, Version 2.0, Section 4.1.4.  Its return value records
+// whether the signature is valid.
+//
+// Note: This is equivalent to `k.VerifyRaw(digest, r, s)`, except
+// that the options the Verifier was created with are honored, and
+// does not allocate.
+func (vr *Verifier) VerifyRaw(k *PublicKey, digest []byte, r, s *secp256k1.Scalar) bool {
+	if r.IsZero() != 0 || s.IsZero() != 0 {
+		return false
+	}
+	if vr.rejectMalleable && s.IsGreaterThanHalfN() != 0 {
+		return false
+	}
+
+	return vr.verify(k, digest, r, s)
+}
+
+func (vr *Verifier) verify(k *PublicKey, digest []byte, r, s *secp256k1.Scalar) bool {
+	if vr.digestSize != 0 && len(digest) != vr.digestSize {
+		return false
+	}
+	if vr.rejectZeroDigest && isZeroDigest(digest) {
+		return false
+	}
+
+	e, err := setHashToScalar(vr.e, digest)
+	if err != nil {
+		return false
+	}
+
+	return nil == vr.scratch.verifyE(nil, k, e, r, s)
+}
+
+// VerifyDERFast verifies the DER encoded signature `der` of `hash`,
+// using the PublicKey `k`, using the verification procedure as
+// specified in SEC 1, Version 2.0, Section 4.1.4.  Its return value
+// records whether the signature is valid.
+//
+// Note: This is the performance-optimized equivalent of `VerifyASN1`,
+// that is safe for concurrent use and does not allocate.  The parse,
+// hash to scalar, and verification steps share temporary scalars and
+// points drawn from a pool, and `s` is inverted in variable time, as
+// it is public.
+func (k *PublicKey) VerifyDERFast(hash, der []byte) bool {
+	vr := verifierPool.Get().(*Verifier) //nolint:forcetypeassert
+	defer verifierPool.Put(vr)
+
+	if err := parseASN1Signature(vr.r, vr.s, der); err != nil {
+		return false
+	}
+
+	e, err := setHashToScalar(vr.e, hash)
+	if err != nil {
+		return false
+	}
+
+	return nil == vr.scratch.verifyEVartime(k, e, vr.r, vr.s)
+}
+
+// AttributeSignature verifies the ASN.1 encoded signature `sig` of
+// `digest` against each of the PublicKeys in `keys`, using the
+// verification procedure as specified in SEC 1, Version 2.0, Section
+// 4.1.4.  It returns the index of the first key that the signature is
+// valid for and true, or -1 and false if there is no such key.
+//
+// Note: The signature is only parsed once, regardless of the number of
+// keys.
+func AttributeSignature(keys []*PublicKey, digest, sig []byte) (int, bool) {
+	r, s, err := ParseASN1Signature(sig)
+	if err != nil {
+		return -1, false
+	}
+	e, err := hashToScalar(digest)
+	if err != nil {
+		return -1, false
+	}
+
+	sc := newVerifyScratch()
+	for i, k := range keys {
+		if sc.verifyE(nil, k, e, r, s) == nil {
+			return i, true
+		}
+	}
+
+	return -1, false
+}
+
+// NewVerifier returns a new Verifier, with the default options.
+func NewVerifier() *Verifier {
+	return NewVerifierWithOptions(nil)
+}
+
+// NewVerifierWithOptions returns a new Verifier, that honors the
+// `Hash`, `RejectMalleable`, and `RejectZeroDigest` fields of `opts`.
+// If `opts` is nil, the default options are used.
+func NewVerifierWithOptions(opts *ECDSAOptions) *Verifier {
+	vr := &Verifier{
+		r:       secp256k1.NewScalar(),
+		s:       secp256k1.NewScalar(),
+		e:       secp256k1.NewScalar(),
+		scratch: newVerifyScratch(),
+	}
+	if opts != nil {
+		hashFn := opts.Hash
+		if hashFn == crypto.Hash(0) {
+			hashFn = crypto.SHA256
+		}
+		var ok bool
+		if vr.digestSize, ok = digestSize(hashFn); !ok {
+			vr.digestSize = -1 // Reject all digests.
+		}
+		vr.rejectMalleable = opts.RejectMalleable
+		vr.rejectZeroDigest = opts.RejectZeroDigest
+	}
+
+	return vr
+}
@@ -0,0 +1,185 @@
+// Copyright (c) 2023 Yawning Angel
+//
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package vss implements Shamir's secret sharing over the secp256k1
+// scalar field, and Feldman's verifiable secret sharing extension.
+package vss
+
+import (
+	csrand "crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+
+	"gitlab.com/yawning/secp256k1-voi"
+	"gitlab.com/yawning/secp256k1-voi/internal/sampling"
+)
+
+var (
+	errInvalidThreshold = errors.New("secp256k1/secec/vss: invalid threshold")
+	errInvalidShare     = errors.New("secp256k1/secec/vss: invalid share")
+	errDuplicateShare   = errors.New("secp256k1/secec/vss: duplicate share index")
+	errNoShares         = errors.New("secp256k1/secec/vss: no shares")
+)
+
+// Share is a share of a secret, `(x, f(x))`, where `f` is the secret
+// sharing polynomial, and `f(0)` is the secret.
+type Share struct {
+	// X is the (public) index of the share, which is never zero.
+	X *secp256k1.Scalar
+	// Y is the (secret) value of the share.
+	Y *secp256k1.Scalar
+}
+
+// Split splits `secret` into `n` shares, any `threshold` of which can
+// be combined to recover the secret.  The shares have the indexes
+// `[1,n]`.
+//
+// Note: If `rand` is nil, [crypto/rand.Reader] will be used.
+func Split(rand io.Reader, secret *secp256k1.Scalar, threshold, n int) ([]Share, error) {
+	shares, coeffs, err := split(rand, secret, threshold, n)
+	for _, a := range coeffs {
+		a.Zero()
+	}
+
+	return shares, err
+}
+
+// FeldmanSplit splits `secret` into `n` shares, as with `Split`, and
+// additionally returns the commitments `C_j = a_j * G` to each of the
+// coefficients of the secret sharing polynomial, which can be used to
+// verify the shares with `VerifyShare`.
+//
+// Note: `C_0 = secret * G`, so the secret MUST have sufficient entropy
+// (eg: a private key) for the secret to remain hidden.
+func FeldmanSplit(rand io.Reader, secret *secp256k1.Scalar, threshold, n int) ([]Share, []*secp256k1.Point, error) {
+	shares, coeffs, err := split(rand, secret, threshold, n)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	commitments := make([]*secp256k1.Point, 0, len(coeffs))
+	for _, a := range coeffs {
+		commitments = append(commitments, secp256k1.NewIdentityPoint().ScalarBaseMult(a))
+		a.Zero()
+	}
+
+	return shares, commitments, nil
+}
+
+// VerifyShare verifies `share` against the coefficient commitments
+// `commitments`, by checking that `share.Y * G == sum(C_j * share.X^j)`.
+// Shares with an index of zero are rejected.
+//
+// Note: This is variable-time, as all of the inputs are assumed to be
+// public.
+func VerifyShare(share Share, commitments []*secp256k1.Point) bool { //nolint:gocritic
+	if share.X == nil || share.Y == nil || share.X.IsZero() != 0 || len(commitments) == 0 {
+		return false
+	}
+
+	// -Y * G + sum(C_j * X^j) == O
+	negY := secp256k1.NewScalar().Negate(share.Y)
+	xs := make([]*secp256k1.Scalar, 0, len(commitments))
+	xj := secp256k1.NewScalar().One()
+	for range commitments {
+		xs = append(xs, secp256k1.NewScalarFrom(xj))
+		xj.Multiply(xj, share.X)
+	}
+
+	sum := secp256k1.NewIdentityPoint().MultiScalarMultBasepointVartime(
+		[]*secp256k1.Scalar{negY},
+		xs,
+		commitments,
+	)
+
+	return sum.IsIdentity() == 1
+}
+
+// Combine recovers the secret from `shares`, via Lagrange interpolation
+// at `0`.  The caller is responsible for providing at least `threshold`
+// shares, as fewer shares will result in an incorrect secret.
+func Combine(shares []Share) (*secp256k1.Scalar, error) {
+	if len(shares) == 0 {
+		return nil, errNoShares
+	}
+	for i, share := range shares {
+		if share.X == nil || share.Y == nil || share.X.IsZero() != 0 {
+			return nil, fmt.Errorf("%w: shares[%d]", errInvalidShare, i)
+		}
+		for _, other := range shares[:i] {
+			if share.X.Equal(other.X) == 1 {
+				return nil, errDuplicateShare
+			}
+		}
+	}
+
+	// f(0) = sum(y_i * prod(x_j / (x_j - x_i))), for j != i
+	var (
+		secret = secp256k1.NewScalar()
+		num    = secp256k1.NewScalar()
+		den    = secp256k1.NewScalar()
+		tmp    = secp256k1.NewScalar()
+	)
+	for i, share := range shares {
+		num.One()
+		den.One()
+		for j, other := range shares {
+			if i == j {
+				continue
+			}
+			num.Multiply(num, other.X)
+			den.Multiply(den, tmp.Subtract(other.X, share.X))
+		}
+
+		tmp.Invert(den)
+		tmp.Multiply(tmp, num)
+		tmp.Multiply(tmp, share.Y)
+		secret.Add(secret, tmp)
+	}
+
+	return secret, nil
+}
+
+func split(rand io.Reader, secret *secp256k1.Scalar, threshold, n int) ([]Share, []*secp256k1.Scalar, error) {
+	if threshold < 1 || threshold > n {
+		return nil, nil, errInvalidThreshold
+	}
+	if rand == nil {
+		rand = csrand.Reader
+	}
+
+	// f(x) = a_0 + a_1 * x + ... + a_{t-1} * x^{t-1}, a_0 = secret
+	coeffs := make([]*secp256k1.Scalar, 0, threshold)
+	coeffs = append(coeffs, secp256k1.NewScalarFrom(secret))
+	for i := 1; i < threshold; i++ {
+		a, err := sampling.RandomScalar(rand)
+		if err != nil {
+			for _, a := range coeffs {
+				a.Zero()
+			}
+			return nil, nil, err
+		}
+		coeffs = append(coeffs, a)
+	}
+
+	shares := make([]Share, 0, n)
+	for i := 1; i <= n; i++ {
+		x := secp256k1.NewScalarFromUint64(uint64(i))
+
+		// Horner's method.
+		y := secp256k1.NewScalar()
+		for j := len(coeffs) - 1; j >= 0; j-- {
+			y.Multiply(y, x)
+			y.Add(y, coeffs[j])
+		}
+
+		shares = append(shares, Share{
+			X: x,
+			Y: y,
+		})
+	}
+
+	return shares, coeffs, nil
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2023 Yawning Angel
+//
+// SPDX-License-Identifier: BSD-3-Clause
+
+package vss
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gitlab.com/yawning/secp256k1-voi"
+	"gitlab.com/yawning/secp256k1-voi/internal/sampling"
+	"gitlab.com/yawning/secp256k1-voi/secec"
+)
+
+func TestVSS(t *testing.T) {
+	const (
+		threshold = 3
+		n         = 5
+	)
+
+	priv, err := secec.GenerateKey()
+	require.NoError(t, err, "GenerateKey")
+	secret := priv.Scalar()
+
+	t.Run("Shamir", func(t *testing.T) {
+		shares, err := Split(nil, secret, threshold, n)
+		require.NoError(t, err, "Split")
+		require.Len(t, shares, n, "Split - number of shares")
+
+		for _, subset := range [][]int{
+			{0, 1, 2},
+			{4, 2, 0},
+			{1, 3, 4},
+			{0, 1, 2, 3, 4},
+		} {
+			var s []Share
+			for _, idx := range subset {
+				s = append(s, shares[idx])
+			}
+
+			recovered, err := Combine(s)
+			require.NoError(t, err, "Combine(%v)", subset)
+			require.EqualValues(t, 1, secret.Equal(recovered), "Combine(%v)", subset)
+		}
+
+		recovered, err := Combine(shares[:threshold-1])
+		require.NoError(t, err, "Combine - below threshold")
+		require.EqualValues(t, 0, secret.Equal(recovered), "Combine - below threshold")
+
+		_, err = Combine([]Share{shares[0], shares[1], shares[0]})
+		require.ErrorIs(t, err, errDuplicateShare, "Combine - duplicate")
+		_, err = Combine(nil)
+		require.ErrorIs(t, err, errNoShares, "Combine - no shares")
+		_, err = Combine([]Share{{X: secp256k1.NewScalar(), Y: secret}})
+		require.ErrorIs(t, err, errInvalidShare, "Combine - zero index")
+
+		_, err = Split(bytes.NewReader(make([]byte, 13)), secret, threshold, n)
+		require.ErrorIs(t, err, sampling.ErrEntropySource, "Split - broken rand")
+
+		for _, v := range [][2]int{{0, 5}, {6, 5}, {-1, 5}} {
+			_, err = Split(nil, secret, v[0], v[1])
+			require.ErrorIs(t, err, errInvalidThreshold, "Split(%d, %d)", v[0], v[1])
+		}
+	})
+
+	t.Run("Feldman", func(t *testing.T) {
+		shares, commitments, err := FeldmanSplit(nil, secret, threshold, n)
+		require.NoError(t, err, "FeldmanSplit")
+		require.Len(t, commitments, threshold, "FeldmanSplit - number of commitments")
+		require.EqualValues(t, 1, commitments[0].Equal(priv.PublicKey().Point()), "C_0 == secret * G")
+
+		for i, share := range shares {
+			require.True(t, VerifyShare(share, commitments), "VerifyShare(%d)", i)
+		}
+
+		recovered, err := Combine(shares[1:4])
+		require.NoError(t, err, "Combine")
+		require.EqualValues(t, 1, secret.Equal(recovered), "Combine")
+
+		// Corrupted share value.
+		bad := Share{
+			X: shares[0].X,
+			Y: secp256k1.NewScalar().Add(shares[0].Y, secp256k1.NewScalarFromUint64(1)),
+		}
+		require.False(t, VerifyShare(bad, commitments), "VerifyShare - corrupted Y")
+
+		// Share presented at the wrong index.
+		bad = Share{
+			X: shares[1].X,
+			Y: shares[0].Y,
+		}
+		require.False(t, VerifyShare(bad, commitments), "VerifyShare - wrong X")
+
+		// Zero index (the secret itself).
+		bad = Share{
+			X: secp256k1.NewScalar(),
+			Y: secret,
+		}
+		require.False(t, VerifyShare(bad, commitments), "VerifyShare - zero X")
+
+		// Commitments from a different polynomial.
+		_, otherCommitments, err := FeldmanSplit(nil, secret, threshold, n)
+		require.NoError(t, err, "FeldmanSplit - other")
+		require.False(t, VerifyShare(shares[0], otherCommitments), "VerifyShare - other commitments")
+
+		require.False(t, VerifyShare(shares[0], nil), "VerifyShare - no commitments")
+	})
+}
@@ -8,7 +8,6 @@ import (
 	"crypto"
 	_ "crypto/sha256"
 	_ "crypto/sha512"
-	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -31,9 +30,6 @@ const (
 	fileEcdsaAsnSha256 = "./testdata/wycheproof/ecdsa_secp256k1_sha256_test.json"
 	fileEcdsaAsnSha512 = "./testdata/wycheproof/ecdsa_secp256k1_sha512_test.json"
 
-	jwkKtyEc        = "EC"
-	jwkCrvSecp256k1 = "P-256K"
-
 	resultValid      = "valid"
 	resultAcceptable = "acceptable"
 )
@@ -123,56 +119,6 @@ type SignatureTestGroup struct {
 	Tests        []SignatureTestCase `json:"tests"`
 }
 
-type JSONWebKey struct {
-	KeyType string `json:"kty"`
-	Crv     string `json:"crv"`
-	D       string `json:"d"`
-	X       string `json:"x"`
-	Y       string `json:"y"`
-}
-
-func (jwk *JSONWebKey) IsBasicOk(t *testing.T) error {
-	require.EqualValues(t, jwkKtyEc, jwk.KeyType, "kty")
-	if jwk.Crv != jwkCrvSecp256k1 {
-		return fmt.Errorf("jwk: unsupported curve: '%v'", jwk.Crv) //nolint:goerr113
-	}
-	return nil
-}
-
-func (jwk *JSONWebKey) ToPublic(t *testing.T) (*PublicKey, error) {
-	if err := jwk.IsBasicOk(t); err != nil {
-		return nil, err
-	}
-	xBytes, err := base64.RawURLEncoding.DecodeString(jwk.X)
-	require.NoError(t, err, "base64.RawURLEncoding.DecodeString(x)")
-	yBytes, err := base64.RawURLEncoding.DecodeString(jwk.Y)
-	require.NoError(t, err, "base64.RawURLEncoding.DecodeString(y)")
-
-	pt, err := secp256k1.NewPointFromCoords((*[secp256k1.CoordSize]byte)(xBytes), (*[secp256k1.CoordSize]byte)(yBytes))
-	if err != nil {
-		return nil, err
-	}
-
-	publicKey, err := NewPublicKeyFromPoint(pt)
-	require.NoError(t, err, "NewPublicKeyFromPoint")
-
-	return publicKey, nil
-}
-
-func (jwk *JSONWebKey) ToPrivate(t *testing.T) (*PrivateKey, error) {
-	jwkPub, err := jwk.ToPublic(t)
-	require.NoError(t, err, "privateKey: jwk.ToPublic")
-
-	dBytes, err := base64.RawURLEncoding.DecodeString(jwk.D)
-	require.NoError(t, err, "base64.RawURLEncoding.DecodeString(d)")
-
-	jwkPriv, err := NewPrivateKey(dBytes)
-	require.NoError(t, err, "NewPrivateKey")
-	require.True(t, jwkPriv.PublicKey().Equal(jwkPub))
-
-	return jwkPriv, nil
-}
-
 type SignaturePublicKey struct {
 	Type         string `json:"type"`
 	Curve        string `json:"curve"`
@@ -270,21 +216,15 @@ func (tc *DHTestCase) Run(t *testing.T, tg *DHTestGroup) {
 		privateKey, err = NewPrivateKey(tmp)
 		require.NoError(t, err, "NewPrivateKey")
 	case encodingWebCrypto:
-		var publicJWK, privateJWK JSONWebKey
-		err = json.Unmarshal(tc.Public, &publicJWK)
-		require.NoError(t, err, "json.Unmarshal(tc.Public)")
-		err = json.Unmarshal(tc.Private, &privateJWK)
-		require.NoError(t, err, "json.Unmarshal(tc.Private)")
-
-		publicKey, err = publicJWK.ToPublic(t)
+		publicKey, err = ParseJWKPublicKey(tc.Public)
 		if hasFlagBadPublic {
-			require.Error(t, err, "JSONWebKey.ToPublic: expected bad: %+v", tc.Flags)
+			require.Error(t, err, "ParseJWKPublicKey: expected bad: %+v", tc.Flags)
 			return
 		}
-		require.NoError(t, err, "JSONWebKey.ToPublic: %+v", tc.Flags)
+		require.NoError(t, err, "ParseJWKPublicKey: %+v", tc.Flags)
 
-		privateKey, err = privateJWK.ToPrivate(t)
-		require.NoError(t, err, "JSONWebKey.ToPrivate")
+		privateKey, err = ParseJWKPrivateKey(tc.Private)
+		require.NoError(t, err, "ParseJWKPrivateKey")
 	default:
 		t.Fatalf("unknown encoding: '%s'", tg.Encoding)
 	}
@@ -0,0 +1,63 @@
+// Copyright (c) 2023 Yawning Angel
+//
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package x509ish implements helpers for X.509-like constructs that use
+// secp256k1 throughout.
+//
+// WARNING: This is not, and will never be, a X.509 implementation.
+package x509ish
+
+import (
+	"crypto"
+	"errors"
+	"fmt"
+
+	"gitlab.com/yawning/secp256k1-voi/secec"
+)
+
+var (
+	// ErrInvalidIssuerKey is the error returned when the issuer's
+	// Subject Public Key Info is malformed.
+	ErrInvalidIssuerKey = errors.New("secp256k1/secec/x509ish: invalid issuer public key")
+
+	// ErrUnsupportedHash is the error returned when the hash function
+	// is unavailable.
+	ErrUnsupportedHash = errors.New("secp256k1/secec/x509ish: unsupported hash function")
+
+	// ErrInvalidSignature is the error returned when the signature
+	// fails to verify.
+	ErrInvalidSignature = errors.New("secp256k1/secec/x509ish: invalid signature")
+)
+
+// VerifySignedBlob verifies the ASN.1 encoded ECDSA signature `sigDER`
+// of `tbs` hashed with `h`, using the issuer public key encoded as an
+// ASN.1 Subject Public Key Info (`issuerSPKI`), per SEC 1, Version 2.0,
+// Appendix C.3.  It returns nil iff the signature is valid.
+//
+// All errors returned can be tested for with [errors.Is] against
+// ErrInvalidIssuerKey, ErrUnsupportedHash, and ErrInvalidSignature.
+func VerifySignedBlob(issuerSPKI, tbs, sigDER []byte, h crypto.Hash) error {
+	if !h.Available() {
+		return ErrUnsupportedHash
+	}
+
+	issuerKey, err := secec.ParseASN1PublicKey(issuerSPKI)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidIssuerKey, err)
+	}
+
+	hh := h.New()
+	_, _ = hh.Write(tbs)
+	digest := hh.Sum(nil)
+
+	opts := &secec.ECDSAOptions{
+		Hash:     h,
+		Encoding: secec.EncodingASN1,
+	}
+	if !issuerKey.Verify(digest, sigDER, opts) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2023 Yawning Angel
+//
+// SPDX-License-Identifier: BSD-3-Clause
+
+package x509ish
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gitlab.com/yawning/secp256k1-voi/secec"
+)
+
+func TestVerifySignedBlob(t *testing.T) {
+	// Build a trivial chain: root -> intermediate -> leaf, where each
+	// "certificate" is just the SPKI of the subject.
+	var keys []*secec.PrivateKey
+	for i := 0; i < 3; i++ {
+		k, err := secec.GenerateKey()
+		require.NoError(t, err, "GenerateKey")
+		keys = append(keys, k)
+	}
+
+	for i := 1; i < len(keys); i++ {
+		issuer, subject := keys[i-1], keys[i]
+
+		tbs := subject.PublicKey().ASN1Bytes()
+		digest := sha256.Sum256(tbs)
+		sig, err := issuer.Sign(rand.Reader, digest[:], nil)
+		require.NoError(t, err, "Sign")
+
+		issuerSPKI := issuer.PublicKey().ASN1Bytes()
+		err = VerifySignedBlob(issuerSPKI, tbs, sig, crypto.SHA256)
+		require.NoError(t, err, "VerifySignedBlob")
+
+		err = VerifySignedBlob(subject.PublicKey().ASN1Bytes(), tbs, sig, crypto.SHA256)
+		require.ErrorIs(t, err, ErrInvalidSignature, "VerifySignedBlob - wrong issuer")
+
+		err = VerifySignedBlob(issuerSPKI, tbs, sig, crypto.SHA512)
+		require.ErrorIs(t, err, ErrInvalidSignature, "VerifySignedBlob - wrong hash")
+
+		err = VerifySignedBlob(issuerSPKI[1:], tbs, sig, crypto.SHA256)
+		require.ErrorIs(t, err, ErrInvalidIssuerKey, "VerifySignedBlob - bad SPKI")
+
+		err = VerifySignedBlob(issuerSPKI, tbs, sig, crypto.Hash(0))
+		require.ErrorIs(t, err, ErrUnsupportedHash, "VerifySignedBlob - bad hash")
+	}
+}
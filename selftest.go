@@ -0,0 +1,61 @@
+// Copyright (c) 2023 Yawning Angel
+//
+// SPDX-License-Identifier: BSD-3-Clause
+
+package secp256k1
+
+import "errors"
+
+var (
+	errSelfTestGLVSplit       = errors.New("secp256k1: self-test failed: GLV split")
+	errSelfTestEndomorphism   = errors.New("secp256k1: self-test failed: endomorphism")
+	errSelfTestScalarMultCons = errors.New("secp256k1: self-test failed: scalar multiply consistency")
+)
+
+// SelfTest runs a set of known-answer and consistency checks of the
+// curve arithmetic, suitable for use as a power-on self-test, and
+// returns nil iff all of the checks pass.
+func SelfTest() error {
+	testScalars := []*Scalar{
+		NewScalarFromUint64(1),
+		NewScalarFromUint64(0xdeadbeefcafebabe),
+		newScalarFromCanonicalHex("0x7fffffffffffffffffffffffffffffff5d576e7357a4501ddfe92f46681b20a0"),
+		NewScalar().Negate(NewScalarFromUint64(1)),
+		NewScalar().Negate(scNegLambda),
+	}
+
+	// lambda * (x, y) = (beta * x, y)
+	lambda := NewScalar().Negate(scNegLambda)
+	lambdaG := NewIdentityPoint().ScalarBaseMult(lambda)
+	endoG := newMulBeta(NewGeneratorPoint())
+	if lambdaG.Equal(endoG) != 1 {
+		return errSelfTestEndomorphism
+	}
+
+	for _, k := range testScalars {
+		// k = k1 + k2 * lambda, |k1|, |k2| < 2^128
+		k1, k2 := k.splitGLV(NewScalar(), NewScalar())
+		k2NegLambda := NewScalar().Multiply(k2, scNegLambda)
+		if NewScalar().Subtract(k1, k2NegLambda).Equal(k) != 1 {
+			return errSelfTestGLVSplit
+		}
+		for _, ki := range []*Scalar{k1, k2} {
+			abs := NewScalar().ConditionalNegate(ki, ki.IsGreaterThanHalfN())
+			for _, b := range abs.Bytes()[:ScalarSize/2] {
+				if b != 0 {
+					return errSelfTestGLVSplit
+				}
+			}
+		}
+
+		// k * G, computed 3 different ways.
+		ctG := NewIdentityPoint().ScalarMult(k, NewGeneratorPoint())
+		baseG := NewIdentityPoint().ScalarBaseMult(k)
+		vartimeG := newRcvr().scalarMultVartimeGLV(k, NewGeneratorPoint())
+		if ctG.Equal(baseG)&ctG.Equal(vartimeG) != 1 {
+			return errSelfTestScalarMultCons
+		}
+	}
+
+	return nil
+}